@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
+)
+
+const glossaryUsage = `Usage: bakasub glossary <export|import> [flags] FILE
+
+Exchange a series glossary with spreadsheets, Anki decks and terminology tools.
+The format (csv, tsv, tbx) is taken from the file extension unless -format is set.
+Headerless TSV files, such as Anki text exports, are read as term and translation.
+
+Flags:
+`
+
+// runGlossary implements the "glossary" subcommand and returns the process exit code
+func runGlossary(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("glossary", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, glossaryUsage)
+		fs.PrintDefaults()
+	}
+	glossaryPath := fs.String("glossary", termbase.FileName, "series glossary file")
+	format := fs.String("format", "", "file format: csv, tsv or tbx")
+	sourceLang := fs.String("source-lang", "", "TBX source language (e.g. ja)")
+	targetLang := fs.String("target-lang", "", "TBX target language (e.g. pt-br)")
+	conflict := fs.String("conflict", string(exchange.ConflictPreferApproved), "import: keep, overwrite or prefer_approved")
+
+	if len(args) == 0 || (args[0] != "export" && args[0] != "import") {
+		fs.Usage()
+		return 2
+	}
+	action := args[0]
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+
+	policy, err := exchange.ParseConflictPolicy(*conflict)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+	langs := termbase.Languages{Source: *sourceLang, Target: *targetLang}
+
+	store, err := termbase.Load(*glossaryPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error loading glossary: %v\n", err)
+		return 1
+	}
+
+	if action == "export" {
+		n, err := termbase.Export(store, path, exchange.Format(*format), langs)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Exported %d terms to %s\n", n, path)
+		return 0
+	}
+
+	result, err := termbase.Import(store, path, exchange.Format(*format), langs, policy)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := store.Save(); err != nil {
+		fmt.Fprintf(stderr, "Error saving glossary: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Imported %s: %d added, %d updated, %d skipped\n", path, result.Added, result.Updated, result.Skipped)
+	if len(result.Conflicts) > 0 {
+		fmt.Fprintf(stdout, "Conflicting translations (%s): %s\n", policy, strings.Join(result.Conflicts, ", "))
+	}
+	return 0
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/lintreport"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+	"github.com/lsilvatti/bakasub/internal/core/pipeline"
+)
+
+const lintUsage = `Usage: bakasub lint [flags] FILE...
+
+Check translated subtitles with the quality gate rules and write a report per
+file (NAME.lint.json by default, next to the subtitle unless -out is set).
+Rule overrides come from the lint settings of the configuration.
+
+Flags:
+`
+
+// runLint implements the "lint" subcommand and returns the process exit code:
+// 0 on success, 1 on errors, 2 on usage errors and 3 when -fail-on is reached
+func runLint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, lintUsage)
+		fs.PrintDefaults()
+	}
+	source := fs.String("source", "", "original subtitle, enables the source-vs-target rules (single FILE only)")
+	sourceLang := fs.String("source-lang", "", "source language (e.g. en; default: detected from the source file, else en)")
+	targetLang := fs.String("target-lang", "", "target language (default: the configured target language)")
+	profile := fs.String("profile", "", "prompt profile whose lint overrides apply (e.g. anime)")
+	formats := fs.String("format", "json", "comma-separated report formats: json, html, csv")
+	outDir := fs.String("out", "", "directory for the reports (default: next to each FILE)")
+	failOn := fs.String("fail-on", "", "exit with code 3 when an issue of this severity or higher is left: high, med or low")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	if *source != "" && fs.NArg() > 1 {
+		fmt.Fprintln(stderr, "Error: -source needs a single FILE")
+		return 2
+	}
+
+	reportFormats, err := lintreport.ParseFormats(strings.Split(*formats, ","))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+	threshold, err := linter.ParseSeverity(*failOn)
+	if err != nil || threshold == linter.SeverityOff {
+		fmt.Fprintln(stderr, "Error: -fail-on: use high, med or low")
+		return 2
+	}
+
+	cfg := config.Default()
+	if config.Exists() {
+		if cfg, err = config.Load(); err != nil {
+			fmt.Fprintf(stderr, "Error loading config: %v\n", err)
+			return 1
+		}
+	}
+	if *targetLang == "" {
+		*targetLang = cfg.TargetLang
+	}
+	rules, err := pipeline.LintRules(cfg.Lint, *profile)
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: %v\n", err)
+	}
+
+	var sources []parser.SubtitleLine
+	if *source != "" {
+		original, err := parser.ParseFile(*source)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		sources = original.Lines
+	}
+
+	failed := false
+	for _, path := range fs.Args() {
+		file, err := parser.ParseFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+
+		result := linter.Check(file.Lines, linter.CheckOptions{
+			SourceLang: *sourceLang,
+			TargetLang: *targetLang,
+			Format:     file.Format,
+			Source:     sources,
+			Rules:      rules,
+		})
+		report := lintreport.New(filepath.Base(path), *sourceLang, *targetLang, sources, file.Lines, result.Issues)
+
+		var written []string
+		for _, format := range reportFormats {
+			out := lintreport.Path(path, format)
+			if *outDir != "" {
+				out = filepath.Join(*outDir, filepath.Base(out))
+			}
+			if err := lintreport.Export(report, out, format); err != nil {
+				fmt.Fprintf(stderr, "Error: %v\n", err)
+				return 1
+			}
+			written = append(written, out)
+		}
+
+		s := report.Summary()
+		fmt.Fprintf(stdout, "%s: %d HIGH, %d MED, %d LOW on %d of %d lines -> %s\n",
+			path, s.High, s.Medium, s.Low, s.Lines, report.TotalLines, strings.Join(written, ", "))
+
+		if *failOn != "" && reachesSeverity(s, threshold) {
+			failed = true
+		}
+	}
+
+	if failed {
+		return 3
+	}
+	return 0
+}
+
+// reachesSeverity reports whether a summary has an open issue of severity or higher
+func reachesSeverity(s lintreport.Summary, severity linter.Severity) bool {
+	switch severity {
+	case linter.SeverityHigh:
+		return s.High > 0
+	case linter.SeverityMedium:
+		return s.High+s.Medium > 0
+	default:
+		return s.High+s.Medium+s.Low > 0
+	}
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/db"
 	"github.com/lsilvatti/bakasub/internal/locales"
 	"github.com/lsilvatti/bakasub/internal/ui/dashboard"
 	"github.com/lsilvatti/bakasub/internal/ui/wizard"
@@ -19,6 +21,24 @@ func main() {
 		return
 	}
 
+	// Move files older versions left in the working directory
+	migrateLegacyFiles(os.Stderr)
+
+	// Headless translation memory import/export
+	if len(os.Args) > 1 && os.Args[1] == "tm" {
+		os.Exit(runTM(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	// Headless series glossary import/export
+	if len(os.Args) > 1 && os.Args[1] == "glossary" {
+		os.Exit(runGlossary(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	// Headless quality gate reports
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLint(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Wrap entire application with panic recovery (BSOD handler)
 	utils.SafeRun(func() {
 		// Check if config exists
@@ -55,6 +75,22 @@ func main() {
 	})
 }
 
+// migrateLegacyFiles moves config.json and bakasub.db from the working
+// directory to the config and data directories
+func migrateLegacyFiles(w io.Writer) {
+	if moved, err := config.MigrateLegacy(); err != nil {
+		fmt.Fprintf(w, "Warning: Failed to migrate config: %v\n", err)
+	} else if moved {
+		fmt.Fprintf(w, "Moved config.json to %s\n", config.Path())
+	}
+
+	if moved, err := db.MigrateLegacy(); err != nil {
+		fmt.Fprintf(w, "Warning: Failed to migrate translation memory: %v\n", err)
+	} else if moved {
+		fmt.Fprintf(w, "Moved bakasub.db to %s\n", db.DefaultPath())
+	}
+}
+
 func runWizard() {
 	cfg := config.Default()
 	wiz := wizard.New(cfg)
@@ -97,7 +133,10 @@ func runDashboard(cfg *config.Config) {
 	dashModel := dashboard.New(cfg)
 
 	p := tea.NewProgram(dashModel, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	_, err := p.Run()
+	// Every screen and the watcher share one cache; close it once on the way out
+	db.CloseInstance()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+	"github.com/lsilvatti/bakasub/internal/core/tm"
+)
+
+const tmUsage = `Usage: bakasub tm <export|import> [flags] FILE
+
+Exchange the translation memory with other CAT tools.
+The format (tmx, csv, tsv) is taken from the file extension unless -format is set.
+
+Flags:
+`
+
+// runTM implements the "tm" subcommand and returns the process exit code
+func runTM(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("tm", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, tmUsage)
+		fs.PrintDefaults()
+	}
+	dbPath := fs.String("db", "", "translation memory database (default: bakasub.db in the data directory)")
+	format := fs.String("format", "", "file format: tmx, csv or tsv")
+	langPair := fs.String("lang-pair", "", "only entries of this language pair (e.g. en->pt-br)")
+	project := fs.String("project", "", "only entries of this project")
+	since := fs.String("since", "", "only entries created on or after this date (YYYY-MM-DD)")
+	until := fs.String("until", "", "only entries created before this date (YYYY-MM-DD)")
+	sourceLang := fs.String("source-lang", "", "export: source language of entries translated with \"auto\" detection (required for TMX)")
+	conflict := fs.String("conflict", string(exchange.ConflictPreferApproved), "import: keep, overwrite or prefer_approved")
+	verbose := fs.Bool("v", false, "import: list the entries whose translation conflicted")
+
+	if len(args) == 0 || (args[0] != "export" && args[0] != "import") {
+		fs.Usage()
+		return 2
+	}
+	action := args[0]
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+
+	filter := db.EntryFilter{LangPair: *langPair, ProjectID: *project}
+	var err error
+	if filter.Since, err = parseDate(*since); err != nil {
+		fmt.Fprintf(stderr, "Error: -since: %v\n", err)
+		return 2
+	}
+	if filter.Until, err = parseDate(*until); err != nil {
+		fmt.Fprintf(stderr, "Error: -until: %v\n", err)
+		return 2
+	}
+	policy, err := exchange.ParseConflictPolicy(*conflict)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	cache, err := db.Open(*dbPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error opening translation memory: %v\n", err)
+		return 1
+	}
+	defer cache.Close()
+
+	if action == "export" {
+		n, err := tm.Export(cache, path, exchange.Format(*format), filter, *sourceLang)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Exported %d entries to %s\n", n, path)
+		return 0
+	}
+
+	result, err := tm.Import(cache, path, exchange.Format(*format), filter, policy)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Imported %s: %d added, %d updated, %d skipped, %d conflicts\n",
+		path, result.Added, result.Updated, result.Skipped, len(result.Conflicts))
+	printConflicts(stdout, result.Conflicts, policy, *verbose)
+	return 0
+}
+
+// printConflicts reports the entries whose translation differed from the
+// memory, listing them when verbose
+func printConflicts(w io.Writer, conflicts []string, policy exchange.ConflictPolicy, verbose bool) {
+	if len(conflicts) == 0 {
+		return
+	}
+	if !verbose {
+		fmt.Fprintf(w, "%d entries had a different translation (resolved with %s); run with -v to list them\n", len(conflicts), policy)
+		return
+	}
+	fmt.Fprintf(w, "Conflicting translations (resolved with %s):\n", policy)
+	for _, original := range conflicts {
+		fmt.Fprintf(w, "  %q\n", original)
+	}
+}
+
+// parseDate parses a YYYY-MM-DD date in local time ("" = zero time)
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/viper"
+
+	"github.com/lsilvatti/bakasub/internal/paths"
 )
 
 // TouchlessRules defines automation behavior when conflicts are detected
@@ -15,6 +17,77 @@ type TouchlessRules struct {
 	MuxingStrategy    string `json:"muxing_strategy" mapstructure:"muxing_strategy"`       // "replace", "create_new"
 }
 
+// BudgetLimits caps AI spending in USD. A zero limit disables that check.
+type BudgetLimits struct {
+	PerJob        float64 `json:"per_job" mapstructure:"per_job"`
+	PerDay        float64 `json:"per_day" mapstructure:"per_day"`
+	PerMonth      float64 `json:"per_month" mapstructure:"per_month"`
+	WarnThreshold float64 `json:"warn_threshold" mapstructure:"warn_threshold"` // Fraction of a limit (0.0-1.0) that triggers a warning
+}
+
+// BatchingSettings controls how subtitle lines are grouped into AI requests
+type BatchingSettings struct {
+	Strategy  string  `json:"strategy" mapstructure:"strategy"`     // "scene", "tokens", "fixed"
+	MaxTokens int     `json:"max_tokens" mapstructure:"max_tokens"` // Source tokens per batch (0 = derived from the model's context window)
+	BatchSize int     `json:"batch_size" mapstructure:"batch_size"` // Lines per batch for the fixed strategy
+	SceneGap  float64 `json:"scene_gap" mapstructure:"scene_gap"`   // Seconds of silence that mark a scene change
+}
+
+// CacheSettings controls how the translation memory is reused
+type CacheSettings struct {
+	LookupPolicy   string  `json:"lookup_policy" mapstructure:"lookup_policy"`     // "strict", "same_project", "any"
+	FuzzyThreshold float64 `json:"fuzzy_threshold" mapstructure:"fuzzy_threshold"` // Minimum similarity to reuse a near-identical line (1 = exact only)
+	MaxEntries     int     `json:"max_entries" mapstructure:"max_entries"`         // Entries kept after a job (0 = unlimited)
+	MaxSizeMB      int     `json:"max_size_mb" mapstructure:"max_size_mb"`         // Database size kept after a job (0 = unlimited)
+	EvictionPolicy string  `json:"eviction_policy" mapstructure:"eviction_policy"` // "lru" or "lfu"
+}
+
+// LintRuleConfig overrides one quality gate rule (see linter.Rules for the IDs)
+type LintRuleConfig struct {
+	Severity string         `json:"severity,omitempty" mapstructure:"severity"` // "high", "med", "low" or "off" ("" = rule default)
+	Options  map[string]any `json:"options,omitempty" mapstructure:"options"`   // Rule-specific options
+}
+
+// LintSettings configures the quality gate rules, globally and per prompt profile
+type LintSettings struct {
+	Rules    map[string]LintRuleConfig            `json:"rules" mapstructure:"rules"`       // Rule ID -> override for every profile
+	Profiles map[string]map[string]LintRuleConfig `json:"profiles" mapstructure:"profiles"` // Profile -> rule ID -> override, applied over Rules
+	Reports  []string                             `json:"reports" mapstructure:"reports"`   // Report formats written next to each output file ("json", "html", "csv")
+}
+
+// GlossarySettings controls how the glossary terms flagged keep or force are enforced
+type GlossarySettings struct {
+	Enforcement string `json:"enforcement" mapstructure:"enforcement"` // "verify" (fix or re-request after translation) or "placeholders" (also protect the terms in requests)
+}
+
+// ForProfile returns the rule overrides in effect for a prompt profile.
+// A profile's severity replaces the global one and its options are merged over it.
+func (s LintSettings) ForProfile(profile string) map[string]LintRuleConfig {
+	rules := make(map[string]LintRuleConfig, len(s.Rules))
+	for id, rule := range s.Rules {
+		rules[id] = rule
+	}
+
+	for id, override := range s.Profiles[profile] {
+		rule := rules[id]
+		if override.Severity != "" {
+			rule.Severity = override.Severity
+		}
+		if len(override.Options) > 0 {
+			options := make(map[string]any, len(rule.Options)+len(override.Options))
+			for k, v := range rule.Options {
+				options[k] = v
+			}
+			for k, v := range override.Options {
+				options[k] = v
+			}
+			rule.Options = options
+		}
+		rules[id] = rule
+	}
+	return rules
+}
+
 // PromptProfile represents a translation prompt configuration
 type PromptProfile struct {
 	Name         string  `json:"name" mapstructure:"name"`
@@ -40,8 +113,21 @@ type Config struct {
 	Temperature   float64 `json:"temperature" mapstructure:"temperature"`       // AI temperature (0.0-1.0)
 
 	// Processing Settings
-	RemoveHITags      bool    `json:"remove_hi_tags" mapstructure:"remove_hi_tags"`
-	GlobalTemperature float64 `json:"global_temperature" mapstructure:"global_temperature"`
+	RemoveHITags      bool             `json:"remove_hi_tags" mapstructure:"remove_hi_tags"`
+	GlobalTemperature float64          `json:"global_temperature" mapstructure:"global_temperature"`
+	Batching          BatchingSettings `json:"batching" mapstructure:"batching"`
+
+	// Translation Memory
+	Cache CacheSettings `json:"cache" mapstructure:"cache"`
+
+	// Spending Limits
+	Budget BudgetLimits `json:"budget" mapstructure:"budget"`
+
+	// Quality Gate
+	Lint LintSettings `json:"lint" mapstructure:"lint"`
+
+	// Series Glossary
+	Glossary GlossarySettings `json:"glossary" mapstructure:"glossary"`
 
 	// Automation
 	TouchlessMode  bool           `json:"touchless_mode" mapstructure:"touchless_mode"`
@@ -57,11 +143,29 @@ type Config struct {
 	SaveRawJSON      bool   `json:"save_raw_json" mapstructure:"save_raw_json"`
 }
 
+// configFile is the name of the configuration file, in the config directory
+// and, for versions that predate it, in the working directory
+const configFile = "config.json"
+
 var (
-	configPath = "config.json"
+	configPath string // Overrides Path when set
 	instance   *Config
 )
 
+// Path returns the location of config.json
+func Path() string {
+	if configPath != "" {
+		return configPath
+	}
+	return filepath.Join(paths.ConfigDir(), configFile)
+}
+
+// MigrateLegacy moves a config.json left in the working directory by older
+// versions to Path. It reports whether a file was moved.
+func MigrateLegacy() (bool, error) {
+	return paths.Migrate(configFile, Path())
+}
+
 // GetFactoryProfiles returns the built-in, immutable prompt profiles
 func GetFactoryProfiles() map[string]PromptProfile {
 	return map[string]PromptProfile{
@@ -181,7 +285,24 @@ func Default() *Config {
 		Temperature:       0.3,
 		GlobalTemperature: 0.3,
 		RemoveHITags:      true,
-		TouchlessMode:     false,
+		Batching: BatchingSettings{
+			Strategy:  "scene",
+			BatchSize: 50,
+			SceneGap:  3.0,
+		},
+		Cache: CacheSettings{
+			LookupPolicy:   "strict",
+			FuzzyThreshold: 0.95,
+			MaxSizeMB:      512,
+			EvictionPolicy: "lru",
+		},
+		Budget: BudgetLimits{
+			WarnThreshold: 0.8,
+		},
+		Glossary: GlossarySettings{
+			Enforcement: "verify",
+		},
+		TouchlessMode: false,
 		TouchlessRules: TouchlessRules{
 			MultipleSubtitles: "largest",
 			DefaultProfile:    "anime",
@@ -197,7 +318,7 @@ func Default() *Config {
 
 // Exists checks if config file exists
 func Exists() bool {
-	_, err := os.Stat(configPath)
+	_, err := os.Stat(Path())
 	return err == nil
 }
 
@@ -207,19 +328,15 @@ func Load() (*Config, error) {
 		return instance, nil
 	}
 
-	// Set config file details
-	viper.SetConfigName("config")
-	viper.SetConfigType("json")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("$HOME/.config/bakasub")
+	// Config file not found, return default
+	if !Exists() {
+		instance = Default()
+		return instance, nil
+	}
 
-	// Try to read existing config
+	viper.SetConfigFile(Path())
+	viper.SetConfigType("json")
 	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			// Config file not found, return default
-			instance = Default()
-			return instance, nil
-		}
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
@@ -245,7 +362,8 @@ func Load() (*Config, error) {
 // Save writes the configuration to config.json
 func (c *Config) Save() error {
 	// Ensure directory exists
-	configDir := filepath.Dir(configPath)
+	path := Path()
+	configDir := filepath.Dir(path)
 	if configDir != "." && configDir != "" {
 		if err := os.MkdirAll(configDir, 0755); err != nil {
 			return fmt.Errorf("failed to create config directory: %w", err)
@@ -263,6 +381,11 @@ func (c *Config) Save() error {
 	viper.Set("temperature", c.Temperature)
 	viper.Set("remove_hi_tags", c.RemoveHITags)
 	viper.Set("global_temperature", c.GlobalTemperature)
+	viper.Set("batching", c.Batching)
+	viper.Set("cache", c.Cache)
+	viper.Set("budget", c.Budget)
+	viper.Set("lint", c.Lint)
+	viper.Set("glossary", c.Glossary)
 	viper.Set("touchless_mode", c.TouchlessMode)
 	viper.Set("touchless_rules", c.TouchlessRules)
 	viper.Set("prompt_profiles", c.PromptProfiles)
@@ -272,7 +395,7 @@ func (c *Config) Save() error {
 	viper.Set("save_raw_json", c.SaveRawJSON)
 
 	// Write to file
-	if err := viper.WriteConfigAs(configPath); err != nil {
+	if err := viper.WriteConfigAs(path); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
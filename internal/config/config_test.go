@@ -64,6 +64,24 @@ func TestDefaultTouchlessRules(t *testing.T) {
 	}
 }
 
+func TestDefaultBudget(t *testing.T) {
+	cfg := Default()
+	if cfg.Budget.PerJob != 0 || cfg.Budget.PerDay != 0 || cfg.Budget.PerMonth != 0 {
+		t.Errorf("expected budget limits to be disabled by default, got %+v", cfg.Budget)
+	}
+
+	if cfg.Budget.WarnThreshold != 0.8 {
+		t.Errorf("expected WarnThreshold 0.8, got %f", cfg.Budget.WarnThreshold)
+	}
+}
+
+func TestDefaultGlossary(t *testing.T) {
+	cfg := Default()
+	if cfg.Glossary.Enforcement != "verify" {
+		t.Errorf("expected Enforcement 'verify', got %q", cfg.Glossary.Enforcement)
+	}
+}
+
 func TestGetFactoryProfiles(t *testing.T) {
 	profiles := GetFactoryProfiles()
 	expectedProfiles := []string{"anime", "movie", "series", "documentary", "youtube"}
@@ -358,3 +376,87 @@ func TestConfigStruct(t *testing.T) {
 		t.Error("SaveRawJSON should be true")
 	}
 }
+
+func TestDefaultBatching(t *testing.T) {
+	cfg := Default()
+	if cfg.Batching.Strategy != "scene" {
+		t.Errorf("expected scene batching by default, got %q", cfg.Batching.Strategy)
+	}
+
+	if cfg.Batching.MaxTokens != 0 || cfg.Batching.BatchSize != 50 {
+		t.Errorf("unexpected batching defaults: %+v", cfg.Batching)
+	}
+}
+
+func TestDefaultCachePolicy(t *testing.T) {
+	cfg := Default()
+	if cfg.Cache.LookupPolicy != "strict" {
+		t.Errorf("expected strict cache lookups by default, got %q", cfg.Cache.LookupPolicy)
+	}
+	if cfg.Cache.FuzzyThreshold != 0.95 {
+		t.Errorf("expected fuzzy threshold 0.95 by default, got %v", cfg.Cache.FuzzyThreshold)
+	}
+	if cfg.Cache.MaxEntries != 0 || cfg.Cache.MaxSizeMB != 512 || cfg.Cache.EvictionPolicy != "lru" {
+		t.Errorf("unexpected cache limit defaults: %+v", cfg.Cache)
+	}
+}
+
+func TestPathHonorsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("BAKASUB_HOME", home)
+
+	if got, want := Path(), filepath.Join(home, "config.json"); got != want {
+		t.Errorf("Path() = %q, want %q", got, want)
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	home := filepath.Join(t.TempDir(), "home")
+	t.Setenv("BAKASUB_HOME", home)
+	t.Chdir(t.TempDir())
+
+	if err := os.WriteFile("config.json", []byte(`{"target_lang":"es"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := MigrateLegacy()
+	if err != nil || !moved {
+		t.Fatalf("MigrateLegacy() = %v, %v; want moved", moved, err)
+	}
+	if !Exists() {
+		t.Error("config should exist in the config directory after migration")
+	}
+	if _, err := os.Stat("config.json"); !os.IsNotExist(err) {
+		t.Error("legacy config should be gone from the working directory")
+	}
+}
+
+func TestLintSettingsForProfile(t *testing.T) {
+	settings := LintSettings{
+		Rules: map[string]LintRuleConfig{
+			"punctuation": {Severity: "low", Options: map[string]any{"max_repeat": 2, "keep": true}},
+		},
+		Profiles: map[string]map[string]LintRuleConfig{
+			"anime": {
+				"punctuation":    {Options: map[string]any{"max_repeat": 3}},
+				"source-residue": {Severity: "off"},
+			},
+		},
+	}
+
+	rules := settings.ForProfile("anime")
+	punct := rules["punctuation"]
+	if punct.Severity != "low" || punct.Options["max_repeat"] != 3 || punct.Options["keep"] != true {
+		t.Errorf("profile options should merge over the global rule: %+v", punct)
+	}
+	if rules["source-residue"].Severity != "off" {
+		t.Errorf("profile-only rule missing: %+v", rules)
+	}
+	if settings.Rules["punctuation"].Options["max_repeat"] != 2 {
+		t.Error("ForProfile must not modify the global rules")
+	}
+
+	if rules := settings.ForProfile("movie"); len(rules) != 1 {
+		t.Errorf("other profiles should only get the global rules, got %+v", rules)
+	}
+}
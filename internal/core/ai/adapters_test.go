@@ -90,7 +90,7 @@ func TestOpenRouterAdapterSendBatch(t *testing.T) {
 
 	ctx := context.Background()
 	payload := []Line{{ID: 0, Text: "Hello world"}}
-	result, err := adapter.SendBatch(ctx, payload, "Translate to Portuguese")
+	result, _, err := adapter.SendBatch(ctx, payload, "Translate to Portuguese")
 
 	if err != nil {
 		t.Fatalf("SendBatch returned error: %v", err)
@@ -142,7 +142,7 @@ func TestGeminiAdapterSendBatch(t *testing.T) {
 
 	ctx := context.Background()
 	payload := []Line{{ID: 0, Text: "Hello world"}}
-	result, err := adapter.SendBatch(ctx, payload, "Translate to Portuguese")
+	result, _, err := adapter.SendBatch(ctx, payload, "Translate to Portuguese")
 
 	if err != nil {
 		t.Fatalf("SendBatch returned error: %v", err)
@@ -188,7 +188,7 @@ func TestOpenAIAdapterSendBatch(t *testing.T) {
 
 	ctx := context.Background()
 	payload := []Line{{ID: 0, Text: "Hello world"}}
-	result, err := adapter.SendBatch(ctx, payload, "Translate to Portuguese")
+	result, _, err := adapter.SendBatch(ctx, payload, "Translate to Portuguese")
 
 	if err != nil {
 		t.Fatalf("SendBatch returned error: %v", err)
@@ -231,7 +231,7 @@ func TestLocalLLMAdapterSendBatch(t *testing.T) {
 
 	ctx := context.Background()
 	payload := []Line{{ID: 0, Text: "Hello world"}}
-	result, err := adapter.SendBatch(ctx, payload, "Translate to Portuguese")
+	result, _, err := adapter.SendBatch(ctx, payload, "Translate to Portuguese")
 
 	if err != nil {
 		t.Fatalf("SendBatch returned error: %v", err)
@@ -339,7 +339,7 @@ func TestAPIErrorHandling(t *testing.T) {
 			}
 
 			ctx := context.Background()
-			_, err := adapter.SendBatch(ctx, []Line{{ID: 0, Text: "test"}}, "test")
+			_, _, err := adapter.SendBatch(ctx, []Line{{ID: 0, Text: "test"}}, "test")
 
 			if err == nil {
 				t.Error("Expected error but got nil")
@@ -347,3 +347,134 @@ func TestAPIErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+// TestOpenRouterAdapterSendBatchUsage tests usage and cost extraction
+func TestOpenRouterAdapterSendBatchUsage(t *testing.T) {
+	var gotUsageOpt bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openRouterRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		gotUsageOpt = req.Usage != nil && req.Usage.Include
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]interface{}{"content": `[{"i":0,"t":"Olá"}]`}},
+			},
+			"usage": map[string]interface{}{
+				"prompt_tokens":         120,
+				"completion_tokens":     30,
+				"cost":                  0.0042,
+				"prompt_tokens_details": map[string]interface{}{"cached_tokens": 100},
+			},
+		})
+	}))
+	defer server.Close()
+
+	adapter := &OpenRouterAdapter{apiKey: "k", model: "m", baseURL: server.URL, client: &http.Client{}}
+	_, usage, err := adapter.SendBatch(context.Background(), []Line{{ID: 0, Text: "Hi"}}, "p")
+	if err != nil {
+		t.Fatalf("SendBatch returned error: %v", err)
+	}
+
+	if !gotUsageOpt {
+		t.Error("request should ask for usage accounting")
+	}
+	if usage.PromptTokens != 120 || usage.CompletionTokens != 30 || usage.CachedTokens != 100 {
+		t.Errorf("unexpected token counts: %+v", usage)
+	}
+	if !usage.CostReported || usage.Cost != 0.0042 {
+		t.Errorf("expected reported cost 0.0042, got %+v", usage)
+	}
+	if usage.Requests != 1 {
+		t.Errorf("Requests = %d, want 1", usage.Requests)
+	}
+}
+
+// TestGeminiAdapterSendBatchUsage tests usageMetadata extraction
+func TestGeminiAdapterSendBatchUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"candidates": []map[string]interface{}{
+				{"content": map[string]interface{}{"parts": []map[string]interface{}{{"text": `[{"i":0,"t":"Olá"}]`}}}},
+			},
+			"usageMetadata": map[string]interface{}{
+				"promptTokenCount":     80,
+				"candidatesTokenCount": 20,
+			},
+		})
+	}))
+	defer server.Close()
+
+	adapter := &GeminiAdapter{apiKey: "k", model: "m", baseURL: server.URL, client: &http.Client{}}
+	_, usage, err := adapter.SendBatch(context.Background(), []Line{{ID: 0, Text: "Hi"}}, "p")
+	if err != nil {
+		t.Fatalf("SendBatch returned error: %v", err)
+	}
+
+	if usage.PromptTokens != 80 || usage.CompletionTokens != 20 {
+		t.Errorf("unexpected token counts: %+v", usage)
+	}
+	if usage.CostReported {
+		t.Error("Gemini does not report cost")
+	}
+}
+
+// TestOpenRouterAdapterListModelInfo tests pricing and capability parsing
+func TestOpenRouterAdapterListModelInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [
+			{"id": "openai/gpt-4o", "name": "GPT-4o", "context_length": 128000,
+			 "pricing": {"prompt": "0.0000025", "completion": "0.00001"},
+			 "supported_parameters": ["temperature", "structured_outputs"]},
+			{"id": "meta-llama/llama-3.3-70b-instruct:free", "name": "Llama 3.3 70B", "context_length": 131072,
+			 "pricing": {"prompt": "0", "completion": "0"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	adapter := &OpenRouterAdapter{apiKey: "key", baseURL: server.URL, client: &http.Client{}}
+
+	models, err := adapter.ListModelInfo(context.Background())
+	if err != nil {
+		t.Fatalf("ListModelInfo failed: %v", err)
+	}
+	if len(models) != 2 {
+		t.Fatalf("Expected 2 models, got %d", len(models))
+	}
+
+	gpt := models[0]
+	if gpt.InputPer1M < 2.49 || gpt.InputPer1M > 2.51 || gpt.OutputPer1M < 9.99 || gpt.OutputPer1M > 10.01 {
+		t.Errorf("Unexpected pricing: %+v", gpt)
+	}
+	if !gpt.PricingKnown || !gpt.StructuredOutput || gpt.ContextLength != 128000 {
+		t.Errorf("Unexpected metadata: %+v", gpt)
+	}
+
+	if !models[1].IsFree() || models[1].StructuredOutput {
+		t.Errorf("Expected free model without structured output, got %+v", models[1])
+	}
+}
+
+// TestGeminiAdapterListModelInfo tests context length parsing
+func TestGeminiAdapterListModelInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"models": [
+			{"name": "models/gemini-2.0-flash", "displayName": "Gemini 2.0 Flash", "inputTokenLimit": 1048576},
+			{"name": "models/embedding-001", "displayName": "Embedding"}
+		]}`))
+	}))
+	defer server.Close()
+
+	adapter := &GeminiAdapter{apiKey: "key", baseURL: server.URL, client: &http.Client{}}
+
+	models, err := adapter.ListModelInfo(context.Background())
+	if err != nil {
+		t.Fatalf("ListModelInfo failed: %v", err)
+	}
+	if len(models) != 1 {
+		t.Fatalf("Expected 1 model, got %d", len(models))
+	}
+	if models[0].ID != "gemini-2.0-flash" || models[0].ContextLength != 1048576 || models[0].PricingKnown {
+		t.Errorf("Unexpected model: %+v", models[0])
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
 )
 
 // GeminiAdapter implements LLMProvider for Google Gemini API using REST API
@@ -59,6 +61,11 @@ type geminiResponse struct {
 			} `json:"parts"`
 		} `json:"content"`
 	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount        int `json:"promptTokenCount"`
+		CandidatesTokenCount    int `json:"candidatesTokenCount"`
+		CachedContentTokenCount int `json:"cachedContentTokenCount"`
+	} `json:"usageMetadata"`
 	Error *struct {
 		Message string `json:"message"`
 		Code    int    `json:"code"`
@@ -66,11 +73,11 @@ type geminiResponse struct {
 }
 
 // SendBatch sends a batch of lines for translation
-func (g *GeminiAdapter) SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, error) {
+func (g *GeminiAdapter) SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, Usage, error) {
 	// Convert payload to minified JSON
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	// Build contents (combining system prompt and user payload)
@@ -94,14 +101,14 @@ func (g *GeminiAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 
 	reqJSON, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Create HTTP request
 	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", g.baseURL, g.model, g.apiKey)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqJSON))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -109,7 +116,7 @@ func (g *GeminiAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 	// Send request
 	resp, err := g.client.Do(req)
 	if err != nil {
-		return nil, &ProviderError{
+		return nil, Usage{}, &ProviderError{
 			Provider: "gemini",
 			Code:     "network_error",
 			Message:  err.Error(),
@@ -121,13 +128,13 @@ func (g *GeminiAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 	// Read response
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Parse response
 	var apiResp geminiResponse
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	// Check for API errors
@@ -141,7 +148,7 @@ func (g *GeminiAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 
 		retry := apiResp.Error.Code == 429 || apiResp.Error.Code >= 500
 
-		return nil, &ProviderError{
+		return nil, Usage{}, &ProviderError{
 			Provider: "gemini",
 			Code:     code,
 			Message:  apiResp.Error.Message,
@@ -149,9 +156,16 @@ func (g *GeminiAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 		}
 	}
 
+	usage := Usage{
+		PromptTokens:     apiResp.UsageMetadata.PromptTokenCount,
+		CompletionTokens: apiResp.UsageMetadata.CandidatesTokenCount,
+		CachedTokens:     apiResp.UsageMetadata.CachedContentTokenCount,
+		Requests:         1,
+	}
+
 	// Check for valid response
 	if len(apiResp.Candidates) == 0 {
-		return nil, fmt.Errorf("no candidates in response")
+		return nil, usage, fmt.Errorf("no candidates in response")
 	}
 
 	// Extract text content
@@ -161,16 +175,16 @@ func (g *GeminiAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 	}
 
 	if content == "" {
-		return nil, fmt.Errorf("no text content in response")
+		return nil, usage, fmt.Errorf("no text content in response")
 	}
 
 	// Parse translated lines from response
 	var translatedLines []Line
 	if err := json.Unmarshal([]byte(content), &translatedLines); err != nil {
-		return nil, fmt.Errorf("failed to parse translated lines: %w", err)
+		return nil, usage, fmt.Errorf("failed to parse translated lines: %w", err)
 	}
 
-	return translatedLines, nil
+	return translatedLines, usage, nil
 }
 
 // ValidateKey checks if the API key is valid
@@ -182,6 +196,22 @@ func (g *GeminiAdapter) ValidateKey(ctx context.Context) bool {
 
 // ListModels returns available Gemini models
 func (g *GeminiAdapter) ListModels(ctx context.Context) ([]string, error) {
+	infos, err := g.ListModelInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]string, len(infos))
+	for i, m := range infos {
+		models[i] = m.ID
+	}
+
+	return models, nil
+}
+
+// ListModelInfo returns typed model metadata from the Gemini API.
+// The API does not expose pricing, so PricingKnown is false.
+func (g *GeminiAdapter) ListModelInfo(ctx context.Context) ([]catalog.ModelInfo, error) {
 	url := fmt.Sprintf("%s/models?key=%s", g.baseURL, g.apiKey)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -223,7 +253,9 @@ func (g *GeminiAdapter) ListModels(ctx context.Context) ([]string, error) {
 	// Parse models response
 	var modelsResp struct {
 		Models []struct {
-			Name string `json:"name"`
+			Name            string `json:"name"`
+			DisplayName     string `json:"displayName"`
+			InputTokenLimit int    `json:"inputTokenLimit"`
 		} `json:"models"`
 	}
 
@@ -232,13 +264,19 @@ func (g *GeminiAdapter) ListModels(ctx context.Context) ([]string, error) {
 	}
 
 	// Filter for generateContent-capable models
-	var models []string
+	var models []catalog.ModelInfo
 	for _, m := range modelsResp.Models {
 		// Only include models that support generateContent (typically start with "models/gemini")
 		if strings.Contains(m.Name, "gemini") {
 			// Remove "models/" prefix if present
-			name := strings.TrimPrefix(m.Name, "models/")
-			models = append(models, name)
+			id := strings.TrimPrefix(m.Name, "models/")
+			models = append(models, catalog.ModelInfo{
+				ID:               id,
+				Name:             m.DisplayName,
+				Provider:         "gemini",
+				ContextLength:    m.InputTokenLimit,
+				StructuredOutput: true, // responseMimeType: application/json
+			})
 		}
 	}
 
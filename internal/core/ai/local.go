@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
 )
 
 // LocalLLMAdapter implements LLMProvider for local LLM servers (Ollama, LMStudio)
@@ -46,16 +48,18 @@ type localLLMResponse struct {
 	Message struct {
 		Content string `json:"content"`
 	} `json:"message"`
-	Done  bool   `json:"done"`
-	Error string `json:"error,omitempty"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	Error           string `json:"error,omitempty"`
 }
 
 // SendBatch sends a batch of lines for translation
-func (l *LocalLLMAdapter) SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, error) {
+func (l *LocalLLMAdapter) SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, Usage, error) {
 	// Convert payload to minified JSON
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	// Build messages (Ollama format)
@@ -74,14 +78,14 @@ func (l *LocalLLMAdapter) SendBatch(ctx context.Context, payload []Line, systemP
 
 	reqJSON, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Create HTTP request (Ollama uses /api/chat endpoint)
 	url := l.endpoint + "/api/chat"
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqJSON))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -89,7 +93,7 @@ func (l *LocalLLMAdapter) SendBatch(ctx context.Context, payload []Line, systemP
 	// Send request
 	resp, err := l.client.Do(req)
 	if err != nil {
-		return nil, &ProviderError{
+		return nil, Usage{}, &ProviderError{
 			Provider: "local",
 			Code:     "network_error",
 			Message:  fmt.Sprintf("failed to connect to %s: %v", l.endpoint, err),
@@ -101,18 +105,18 @@ func (l *LocalLLMAdapter) SendBatch(ctx context.Context, payload []Line, systemP
 	// Read response
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Parse response
 	var apiResp localLLMResponse
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	// Check for API errors
 	if apiResp.Error != "" {
-		return nil, &ProviderError{
+		return nil, Usage{}, &ProviderError{
 			Provider: "local",
 			Code:     "inference_error",
 			Message:  apiResp.Error,
@@ -120,15 +124,23 @@ func (l *LocalLLMAdapter) SendBatch(ctx context.Context, payload []Line, systemP
 		}
 	}
 
+	// Local inference is free; only token counts are tracked
+	usage := Usage{
+		PromptTokens:     apiResp.PromptEvalCount,
+		CompletionTokens: apiResp.EvalCount,
+		CostReported:     true,
+		Requests:         1,
+	}
+
 	// Parse translated lines from response
 	content := apiResp.Message.Content
 
 	var translatedLines []Line
 	if err := json.Unmarshal([]byte(content), &translatedLines); err != nil {
-		return nil, fmt.Errorf("failed to parse translated lines: %w", err)
+		return nil, usage, fmt.Errorf("failed to parse translated lines: %w", err)
 	}
 
-	return translatedLines, nil
+	return translatedLines, usage, nil
 }
 
 // ValidateKey checks if the local server is accessible
@@ -188,3 +200,23 @@ func (l *LocalLLMAdapter) ListModels(ctx context.Context) ([]string, error) {
 
 	return models, nil
 }
+
+// ListModelInfo returns the installed local models. Local inference is free.
+func (l *LocalLLMAdapter) ListModelInfo(ctx context.Context) ([]catalog.ModelInfo, error) {
+	names, err := l.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]catalog.ModelInfo, len(names))
+	for i, name := range names {
+		models[i] = catalog.ModelInfo{
+			ID:           name,
+			Name:         name,
+			Provider:     "local",
+			PricingKnown: true,
+		}
+	}
+
+	return models, nil
+}
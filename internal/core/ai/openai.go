@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
 )
 
 // OpenAIAdapter implements LLMProvider for OpenAI API
@@ -50,8 +52,11 @@ type openAIResponse struct {
 		} `json:"message"`
 	} `json:"choices"`
 	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
+		PromptTokens        int `json:"prompt_tokens"`
+		CompletionTokens    int `json:"completion_tokens"`
+		PromptTokensDetails struct {
+			CachedTokens int `json:"cached_tokens"`
+		} `json:"prompt_tokens_details"`
 	} `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
@@ -61,11 +66,11 @@ type openAIResponse struct {
 }
 
 // SendBatch sends a batch of lines for translation
-func (o *OpenAIAdapter) SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, error) {
+func (o *OpenAIAdapter) SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, Usage, error) {
 	// Convert payload to minified JSON
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	// Build messages
@@ -83,13 +88,13 @@ func (o *OpenAIAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 
 	reqJSON, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", o.baseURL+"/chat/completions", bytes.NewReader(reqJSON))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -98,7 +103,7 @@ func (o *OpenAIAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 	// Send request
 	resp, err := o.client.Do(req)
 	if err != nil {
-		return nil, &ProviderError{
+		return nil, Usage{}, &ProviderError{
 			Provider: "openai",
 			Code:     "network_error",
 			Message:  err.Error(),
@@ -110,13 +115,13 @@ func (o *OpenAIAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 	// Read response
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Parse response
 	var apiResp openAIResponse
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	// Check for API errors
@@ -130,7 +135,7 @@ func (o *OpenAIAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 			code = "invalid_key"
 		}
 
-		return nil, &ProviderError{
+		return nil, Usage{}, &ProviderError{
 			Provider: "openai",
 			Code:     code,
 			Message:  apiResp.Error.Message,
@@ -138,9 +143,17 @@ func (o *OpenAIAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 		}
 	}
 
+	// OpenAI does not report cost; it is computed from pricing by the caller
+	usage := Usage{
+		PromptTokens:     apiResp.Usage.PromptTokens,
+		CompletionTokens: apiResp.Usage.CompletionTokens,
+		CachedTokens:     apiResp.Usage.PromptTokensDetails.CachedTokens,
+		Requests:         1,
+	}
+
 	// Check for valid response
 	if len(apiResp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from OpenAI")
+		return nil, usage, fmt.Errorf("no response from OpenAI")
 	}
 
 	// Parse translated lines from response
@@ -148,10 +161,10 @@ func (o *OpenAIAdapter) SendBatch(ctx context.Context, payload []Line, systemPro
 
 	var translatedLines []Line
 	if err := json.Unmarshal([]byte(content), &translatedLines); err != nil {
-		return nil, fmt.Errorf("failed to parse translated lines: %w", err)
+		return nil, usage, fmt.Errorf("failed to parse translated lines: %w", err)
 	}
 
-	return translatedLines, nil
+	return translatedLines, usage, nil
 }
 
 // ValidateKey checks if the API key is valid by making a simple API request
@@ -228,6 +241,27 @@ func (o *OpenAIAdapter) ListModels(ctx context.Context) ([]string, error) {
 	return models, nil
 }
 
+// ListModelInfo returns typed model metadata from OpenAI.
+// The API does not expose pricing or context size, so only IDs are populated.
+func (o *OpenAIAdapter) ListModelInfo(ctx context.Context) ([]catalog.ModelInfo, error) {
+	ids, err := o.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]catalog.ModelInfo, len(ids))
+	for i, id := range ids {
+		models[i] = catalog.ModelInfo{
+			ID:               id,
+			Name:             id,
+			Provider:         "openai",
+			StructuredOutput: true,
+		}
+	}
+
+	return models, nil
+}
+
 // Close is a no-op for HTTP-based implementation
 func (o *OpenAIAdapter) Close() error {
 	return nil
@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
 )
 
 // OpenRouterAdapter implements LLMProvider for OpenRouter API
@@ -35,6 +38,12 @@ type openRouterRequest struct {
 	Model       string              `json:"model"`
 	Messages    []openRouterMessage `json:"messages"`
 	Temperature float64             `json:"temperature"`
+	Usage       *openRouterUsageOpt `json:"usage,omitempty"`
+}
+
+// openRouterUsageOpt asks OpenRouter to include usage accounting (with cost) in the response
+type openRouterUsageOpt struct {
+	Include bool `json:"include"`
 }
 
 type openRouterMessage struct {
@@ -50,8 +59,12 @@ type openRouterResponse struct {
 		} `json:"message"`
 	} `json:"choices"`
 	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
+		PromptTokens        int      `json:"prompt_tokens"`
+		CompletionTokens    int      `json:"completion_tokens"`
+		Cost                *float64 `json:"cost,omitempty"`
+		PromptTokensDetails struct {
+			CachedTokens int `json:"cached_tokens"`
+		} `json:"prompt_tokens_details"`
 	} `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
@@ -59,12 +72,27 @@ type openRouterResponse struct {
 	} `json:"error,omitempty"`
 }
 
+// usage converts the response usage block into a Usage record
+func (r *openRouterResponse) usage() Usage {
+	u := Usage{
+		PromptTokens:     r.Usage.PromptTokens,
+		CompletionTokens: r.Usage.CompletionTokens,
+		CachedTokens:     r.Usage.PromptTokensDetails.CachedTokens,
+		Requests:         1,
+	}
+	if r.Usage.Cost != nil {
+		u.Cost = *r.Usage.Cost
+		u.CostReported = true
+	}
+	return u
+}
+
 // SendBatch sends a batch of lines for translation
-func (o *OpenRouterAdapter) SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, error) {
+func (o *OpenRouterAdapter) SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, Usage, error) {
 	// Convert payload to minified JSON
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	// Build messages
@@ -78,17 +106,18 @@ func (o *OpenRouterAdapter) SendBatch(ctx context.Context, payload []Line, syste
 		Model:       o.model,
 		Messages:    messages,
 		Temperature: o.temperature,
+		Usage:       &openRouterUsageOpt{Include: true},
 	}
 
 	reqJSON, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", o.baseURL+"/chat/completions", bytes.NewReader(reqJSON))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -99,7 +128,7 @@ func (o *OpenRouterAdapter) SendBatch(ctx context.Context, payload []Line, syste
 	// Send request
 	resp, err := o.client.Do(req)
 	if err != nil {
-		return nil, &ProviderError{
+		return nil, Usage{}, &ProviderError{
 			Provider: "openrouter",
 			Code:     "network_error",
 			Message:  err.Error(),
@@ -111,13 +140,13 @@ func (o *OpenRouterAdapter) SendBatch(ctx context.Context, payload []Line, syste
 	// Read response
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Parse response
 	var apiResp openRouterResponse
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, Usage{}, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	// Check for API errors
@@ -129,7 +158,7 @@ func (o *OpenRouterAdapter) SendBatch(ctx context.Context, payload []Line, syste
 
 		retry := code == "rate_limit" || code == "timeout" || resp.StatusCode >= 500
 
-		return nil, &ProviderError{
+		return nil, Usage{}, &ProviderError{
 			Provider: "openrouter",
 			Code:     code,
 			Message:  apiResp.Error.Message,
@@ -137,9 +166,11 @@ func (o *OpenRouterAdapter) SendBatch(ctx context.Context, payload []Line, syste
 		}
 	}
 
+	usage := apiResp.usage()
+
 	// Check for valid response
 	if len(apiResp.Choices) == 0 {
-		return nil, fmt.Errorf("no choices in response")
+		return nil, usage, fmt.Errorf("no choices in response")
 	}
 
 	// Parse translated lines from response
@@ -147,10 +178,10 @@ func (o *OpenRouterAdapter) SendBatch(ctx context.Context, payload []Line, syste
 
 	var translatedLines []Line
 	if err := json.Unmarshal([]byte(content), &translatedLines); err != nil {
-		return nil, fmt.Errorf("failed to parse translated lines: %w", err)
+		return nil, usage, fmt.Errorf("failed to parse translated lines: %w", err)
 	}
 
-	return translatedLines, nil
+	return translatedLines, usage, nil
 }
 
 // ValidateKey checks if the API key is valid by making a minimal chat request
@@ -207,7 +238,24 @@ func (o *OpenRouterAdapter) ValidateKey(ctx context.Context) bool {
 }
 
 // ListModels returns available models from OpenRouter
+// Format: id|prompt_price_per_token|context_length
 func (o *OpenRouterAdapter) ListModels(ctx context.Context) ([]string, error) {
+	infos, err := o.ListModelInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]string, len(infos))
+	for i, m := range infos {
+		// Example: openai/gpt-4|0.00003|8192
+		models[i] = fmt.Sprintf("%s|%s|%d", m.ID, strconv.FormatFloat(m.InputPer1M/1000000, 'f', -1, 64), m.ContextLength)
+	}
+
+	return models, nil
+}
+
+// ListModelInfo returns typed model metadata (pricing, context, capabilities) from OpenRouter
+func (o *OpenRouterAdapter) ListModelInfo(ctx context.Context) ([]catalog.ModelInfo, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", o.baseURL+"/models", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -273,11 +321,9 @@ func (o *OpenRouterAdapter) ListModels(ctx context.Context) ([]string, error) {
 		return nil, fmt.Errorf("failed to parse models: %w", err)
 	}
 
-	models := make([]string, len(modelsResp.Data))
+	models := make([]catalog.ModelInfo, len(modelsResp.Data))
 	for i, m := range modelsResp.Data {
-		// Format: id|prompt_price|context_length
-		// Example: openai/gpt-4|0.00003|8192
-		models[i] = fmt.Sprintf("%s|%s|%d", m.ID, m.Pricing.Prompt, m.ContextLength)
+		models[i] = m.toModelInfo()
 	}
 
 	return models, nil
@@ -292,4 +338,32 @@ type OpenRouterModel struct {
 		Prompt     string `json:"prompt"`
 		Completion string `json:"completion"`
 	} `json:"pricing"`
+	SupportedParameters []string `json:"supported_parameters"`
+}
+
+// toModelInfo converts the API model (per-token string prices) into a catalog entry
+func (m OpenRouterModel) toModelInfo() catalog.ModelInfo {
+	info := catalog.ModelInfo{
+		ID:            m.ID,
+		Name:          m.Name,
+		Provider:      "openrouter",
+		ContextLength: m.ContextLength,
+	}
+
+	prompt, errPrompt := strconv.ParseFloat(m.Pricing.Prompt, 64)
+	completion, errCompletion := strconv.ParseFloat(m.Pricing.Completion, 64)
+	if errPrompt == nil && errCompletion == nil && prompt >= 0 && completion >= 0 {
+		info.InputPer1M = prompt * 1000000
+		info.OutputPer1M = completion * 1000000
+		info.PricingKnown = true
+	}
+
+	for _, p := range m.SupportedParameters {
+		if p == "structured_outputs" || p == "response_format" {
+			info.StructuredOutput = true
+			break
+		}
+	}
+
+	return info
 }
@@ -7,15 +7,17 @@ import (
 
 // Line represents a single subtitle line for translation
 type Line struct {
-	ID   int    `json:"i"` // Line ID (minified JSON key)
-	Text string `json:"t"` // Text content (minified JSON key)
+	ID      int    `json:"i"`           // Line ID (minified JSON key)
+	Text    string `json:"t"`           // Text content (minified JSON key)
+	Speaker string `json:"s,omitempty"` // Who speaks the line, when known (context only)
 }
 
 // LLMProvider defines the interface for AI translation providers
 type LLMProvider interface {
 	// SendBatch sends a batch of lines to the AI for translation
-	// Returns translated lines in the same order as input
-	SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, error)
+	// Returns translated lines in the same order as input, plus the token usage
+	// reported by the provider (usage may be populated even when err != nil)
+	SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, Usage, error)
 
 	// ValidateKey checks if the API key/endpoint is valid
 	ValidateKey(ctx context.Context) bool
@@ -134,16 +134,16 @@ func TestLLMProviderInterface(t *testing.T) {
 
 // mockProvider is a mock implementation of LLMProvider for testing
 type mockProvider struct {
-	sendBatchFunc   func(ctx context.Context, payload []Line, systemPrompt string) ([]Line, error)
+	sendBatchFunc   func(ctx context.Context, payload []Line, systemPrompt string) ([]Line, Usage, error)
 	validateKeyFunc func(ctx context.Context) bool
 	listModelsFunc  func(ctx context.Context) ([]string, error)
 }
 
-func (m *mockProvider) SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, error) {
+func (m *mockProvider) SendBatch(ctx context.Context, payload []Line, systemPrompt string) ([]Line, Usage, error) {
 	if m.sendBatchFunc != nil {
 		return m.sendBatchFunc(ctx, payload, systemPrompt)
 	}
-	return payload, nil
+	return payload, Usage{}, nil
 }
 
 func (m *mockProvider) ValidateKey(ctx context.Context) bool {
@@ -163,18 +163,18 @@ func (m *mockProvider) ListModels(ctx context.Context) ([]string, error) {
 // TestMockProviderSendBatch tests the mock provider
 func TestMockProviderSendBatch(t *testing.T) {
 	mock := &mockProvider{
-		sendBatchFunc: func(ctx context.Context, payload []Line, systemPrompt string) ([]Line, error) {
+		sendBatchFunc: func(ctx context.Context, payload []Line, systemPrompt string) ([]Line, Usage, error) {
 			result := make([]Line, len(payload))
 			for i, l := range payload {
 				result[i] = Line{ID: l.ID, Text: "Translated: " + l.Text}
 			}
-			return result, nil
+			return result, Usage{}, nil
 		},
 	}
 
 	ctx := context.Background()
 	payload := []Line{{ID: 0, Text: "Hello"}}
-	result, err := mock.SendBatch(ctx, payload, "test prompt")
+	result, _, err := mock.SendBatch(ctx, payload, "test prompt")
 
 	if err != nil {
 		t.Fatalf("SendBatch failed: %v", err)
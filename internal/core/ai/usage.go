@@ -0,0 +1,36 @@
+package ai
+
+// Usage holds the token accounting reported by a provider for a request
+type Usage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	CachedTokens     int     `json:"cached_tokens"` // Prompt tokens served from the provider's prompt cache
+	Cost             float64 `json:"cost"`          // USD
+	CostReported     bool    `json:"cost_reported"` // Whether Cost came from the provider (false = estimated)
+	Requests         int     `json:"requests"`      // Number of API calls aggregated
+}
+
+// TotalTokens returns prompt + completion tokens
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// Add accumulates another usage record into this one
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.CachedTokens += other.CachedTokens
+	u.Cost += other.Cost
+	u.Requests += other.Requests
+	// An aggregate is only "reported" if every part of it was
+	if u.Requests == other.Requests {
+		u.CostReported = other.CostReported
+	} else {
+		u.CostReported = u.CostReported && other.CostReported
+	}
+}
+
+// IsZero reports whether no usage has been recorded
+func (u Usage) IsZero() bool {
+	return u.Requests == 0 && u.TotalTokens() == 0
+}
@@ -0,0 +1,38 @@
+package ai
+
+import "testing"
+
+// TestUsageAdd tests usage aggregation
+func TestUsageAdd(t *testing.T) {
+	var total Usage
+	total.Add(Usage{PromptTokens: 100, CompletionTokens: 50, CachedTokens: 10, Cost: 0.01, CostReported: true, Requests: 1})
+	total.Add(Usage{PromptTokens: 200, CompletionTokens: 80, Cost: 0.02, CostReported: true, Requests: 1})
+
+	if total.PromptTokens != 300 || total.CompletionTokens != 130 || total.CachedTokens != 10 {
+		t.Errorf("unexpected token totals: %+v", total)
+	}
+	if total.TotalTokens() != 430 {
+		t.Errorf("TotalTokens() = %d, want 430", total.TotalTokens())
+	}
+	if total.Requests != 2 {
+		t.Errorf("Requests = %d, want 2", total.Requests)
+	}
+	if !total.CostReported {
+		t.Error("aggregate of reported costs should stay reported")
+	}
+
+	total.Add(Usage{PromptTokens: 10, Cost: 0.001, Requests: 1})
+	if total.CostReported {
+		t.Error("aggregate containing an estimate should not be reported")
+	}
+}
+
+// TestUsageIsZero tests IsZero
+func TestUsageIsZero(t *testing.T) {
+	if !(Usage{}).IsZero() {
+		t.Error("empty usage should be zero")
+	}
+	if (Usage{Requests: 1}).IsZero() {
+		t.Error("usage with a request should not be zero")
+	}
+}
@@ -0,0 +1,247 @@
+// Package catalog provides a typed, disk-cached catalog of AI models with
+// pricing and capability metadata refreshed from provider APIs.
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/paths"
+)
+
+// DefaultTTL is how long provider model lists are trusted before refreshing
+const DefaultTTL = 24 * time.Hour
+
+// ModelInfo describes a model offered by a provider
+type ModelInfo struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	Provider         string  `json:"provider"`       // openrouter, openai, gemini, local
+	ContextLength    int     `json:"context_length"` // Tokens (0 = unknown)
+	InputPer1M       float64 `json:"input_per_1m"`   // USD per 1M prompt tokens
+	OutputPer1M      float64 `json:"output_per_1m"`  // USD per 1M completion tokens
+	PricingKnown     bool    `json:"pricing_known"`  // Whether pricing came from the provider
+	StructuredOutput bool    `json:"structured_output"`
+}
+
+// IsFree reports whether the model is known to cost nothing
+func (m ModelInfo) IsFree() bool {
+	if strings.HasSuffix(strings.ToLower(m.ID), ":free") {
+		return true
+	}
+	return m.PricingKnown && m.InputPer1M == 0 && m.OutputPer1M == 0
+}
+
+// Fetcher retrieves the current model list from a provider
+type Fetcher interface {
+	ListModelInfo(ctx context.Context) ([]ModelInfo, error)
+}
+
+// ProviderModels holds the models fetched from one provider
+type ProviderModels struct {
+	FetchedAt time.Time   `json:"fetched_at"`
+	Models    []ModelInfo `json:"models"`
+}
+
+// Catalog is a thread-safe collection of models grouped by provider
+type Catalog struct {
+	Providers map[string]*ProviderModels `json:"providers"`
+	path      string
+	ttl       time.Duration
+	mu        sync.RWMutex
+}
+
+var (
+	shared     *Catalog
+	sharedOnce sync.Once
+)
+
+// DefaultPath returns the on-disk location of the shared catalog
+func DefaultPath() string {
+	return filepath.Join(paths.CacheDir(), "models.json")
+}
+
+// Shared returns the process-wide catalog loaded from DefaultPath
+func Shared() *Catalog {
+	sharedOnce.Do(func() {
+		shared = Load(DefaultPath())
+	})
+	return shared
+}
+
+// New creates an empty catalog persisted at path ("" = in-memory only)
+func New(path string) *Catalog {
+	return &Catalog{
+		Providers: make(map[string]*ProviderModels),
+		path:      path,
+		ttl:       DefaultTTL,
+	}
+}
+
+// Load reads a catalog from disk. A missing or corrupt file yields an empty catalog.
+func Load(path string) *Catalog {
+	c := New(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+
+	var stored Catalog
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Providers == nil {
+		return c
+	}
+
+	c.Providers = stored.Providers
+	return c
+}
+
+// SetTTL changes how long provider lists are considered fresh
+func (c *Catalog) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// Save writes the catalog to disk
+func (c *Catalog) Save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	c.mu.RLock()
+	data, err := json.MarshalIndent(c, "", "  ")
+	c.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create catalog directory: %w", err)
+	}
+
+	return os.WriteFile(c.path, data, 0644)
+}
+
+// IsStale reports whether the provider's models are missing or older than the TTL
+func (c *Catalog) IsStale(provider string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.Providers[provider]
+	if !ok || len(entry.Models) == 0 {
+		return true
+	}
+	return time.Since(entry.FetchedAt) > c.ttl
+}
+
+// Update replaces the model list of a provider
+func (c *Catalog) Update(provider string, models []ModelInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range models {
+		models[i].Provider = provider
+	}
+	c.Providers[provider] = &ProviderModels{
+		FetchedAt: time.Now(),
+		Models:    models,
+	}
+}
+
+// Refresh fetches the provider's models if the cached list is stale and saves the catalog.
+// On fetch failure the cached list (if any) is kept and the error is returned.
+func (c *Catalog) Refresh(ctx context.Context, provider string, fetcher Fetcher) error {
+	if !c.IsStale(provider) {
+		return nil
+	}
+
+	models, err := fetcher.ListModelInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.Update(provider, models)
+	return c.Save()
+}
+
+// Models returns the cached models of a provider
+func (c *Catalog) Models(provider string) []ModelInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.Providers[provider]
+	if !ok {
+		return nil
+	}
+	models := make([]ModelInfo, len(entry.Models))
+	copy(models, entry.Models)
+	return models
+}
+
+// Lookup finds a model by provider and ID. The named provider is searched
+// first, then the remaining providers in name order, so a model ID listed by
+// several providers always resolves to the same entry ("" = any provider).
+// IDs match exactly first, then ignoring the "vendor/" prefix (so "gpt-4o"
+// finds "openai/gpt-4o" and vice versa).
+func (c *Catalog) Lookup(provider, modelID string) (ModelInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	id := strings.ToLower(strings.TrimSpace(modelID))
+	if id == "" {
+		return ModelInfo{}, false
+	}
+
+	order := c.providerOrder(provider)
+	for _, name := range order {
+		for _, m := range c.Providers[name].Models {
+			if strings.ToLower(m.ID) == id {
+				return m, true
+			}
+		}
+	}
+
+	base := baseID(id)
+	for _, name := range order {
+		for _, m := range c.Providers[name].Models {
+			if baseID(strings.ToLower(m.ID)) == base {
+				return m, true
+			}
+		}
+	}
+
+	return ModelInfo{}, false
+}
+
+// providerOrder lists the cached providers with preferred first and the rest
+// sorted by name. Callers must hold the read lock.
+func (c *Catalog) providerOrder(preferred string) []string {
+	names := make([]string, 0, len(c.Providers))
+	for name := range c.Providers {
+		if name != preferred {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if _, ok := c.Providers[preferred]; ok {
+		names = append([]string{preferred}, names...)
+	}
+	return names
+}
+
+// baseID strips the "vendor/" prefix from a model ID
+func baseID(id string) string {
+	if idx := strings.LastIndex(id, "/"); idx >= 0 {
+		return id[idx+1:]
+	}
+	return id
+}
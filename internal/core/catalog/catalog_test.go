@@ -0,0 +1,165 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeFetcher struct {
+	models []ModelInfo
+	err    error
+	calls  int
+}
+
+func (f *fakeFetcher) ListModelInfo(ctx context.Context) ([]ModelInfo, error) {
+	f.calls++
+	return f.models, f.err
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "models.json")
+
+	c := New(path)
+	c.Update("openrouter", []ModelInfo{
+		{ID: "openai/gpt-4o", ContextLength: 128000, InputPer1M: 2.5, OutputPer1M: 10, PricingKnown: true},
+	})
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := Load(path)
+	models := loaded.Models("openrouter")
+	if len(models) != 1 {
+		t.Fatalf("Expected 1 model, got %d", len(models))
+	}
+	if models[0].Provider != "openrouter" || models[0].InputPer1M != 2.5 {
+		t.Errorf("Unexpected model after reload: %+v", models[0])
+	}
+	if loaded.IsStale("openrouter") {
+		t.Error("Freshly saved catalog should not be stale")
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	c := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if c == nil || len(c.Providers) != 0 {
+		t.Error("Expected empty catalog for missing file")
+	}
+	if !c.IsStale("openrouter") {
+		t.Error("Empty provider should be stale")
+	}
+}
+
+func TestIsStaleAfterTTL(t *testing.T) {
+	c := New("")
+	c.Update("gemini", []ModelInfo{{ID: "gemini-2.0-flash"}})
+	c.Providers["gemini"].FetchedAt = time.Now().Add(-2 * time.Hour)
+
+	c.SetTTL(time.Hour)
+	if !c.IsStale("gemini") {
+		t.Error("Expected stale after TTL")
+	}
+
+	c.SetTTL(3 * time.Hour)
+	if c.IsStale("gemini") {
+		t.Error("Expected fresh within TTL")
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "models.json"))
+	fetcher := &fakeFetcher{models: []ModelInfo{{ID: "gpt-4o"}}}
+
+	if err := c.Refresh(context.Background(), "openai", fetcher); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if err := c.Refresh(context.Background(), "openai", fetcher); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if fetcher.calls != 1 {
+		t.Errorf("Expected fresh catalog to skip fetch, got %d calls", fetcher.calls)
+	}
+
+	// Failures keep the cached list
+	c.SetTTL(0)
+	failing := &fakeFetcher{err: errors.New("offline")}
+	if err := c.Refresh(context.Background(), "openai", failing); err == nil {
+		t.Error("Expected error from failing fetcher")
+	}
+	if len(c.Models("openai")) != 1 {
+		t.Error("Cached models should survive a failed refresh")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	c := New("")
+	c.Update("openrouter", []ModelInfo{{ID: "openai/gpt-4o-mini", InputPer1M: 0.15}})
+	c.Update("gemini", []ModelInfo{{ID: "gemini-2.0-flash"}})
+
+	tests := []struct {
+		query string
+		want  string
+		found bool
+	}{
+		{"openai/gpt-4o-mini", "openai/gpt-4o-mini", true},
+		{"GPT-4o-mini", "openai/gpt-4o-mini", true},
+		{"google/gemini-2.0-flash", "gemini-2.0-flash", true},
+		{"gpt-4o", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := c.Lookup("", tt.query)
+		if ok != tt.found || got.ID != tt.want {
+			t.Errorf("Lookup(%q) = %q, %v; want %q, %v", tt.query, got.ID, ok, tt.want, tt.found)
+		}
+	}
+}
+
+func TestLookupDuplicateID(t *testing.T) {
+	c := New("")
+	c.Update("openrouter", []ModelInfo{{ID: "gpt-4o", InputPer1M: 5}})
+	c.Update("openai", []ModelInfo{{ID: "gpt-4o", InputPer1M: 2.5}})
+	c.Update("azure", []ModelInfo{{ID: "gpt-4o", InputPer1M: 3}})
+
+	tests := []struct {
+		provider string
+		want     float64
+	}{
+		{"openai", 2.5},
+		{"openrouter", 5},
+		{"", 3},       // First provider by name
+		{"gemini", 3}, // Not cached, same as any provider
+	}
+
+	for _, tt := range tests {
+		// Map iteration order changes between runs; repeat to catch it
+		for i := 0; i < 20; i++ {
+			got, ok := c.Lookup(tt.provider, "gpt-4o")
+			if !ok || got.InputPer1M != tt.want {
+				t.Fatalf("Lookup(%q, gpt-4o) = %+v, %v; want price %v", tt.provider, got, ok, tt.want)
+			}
+		}
+	}
+}
+
+func TestIsFree(t *testing.T) {
+	tests := []struct {
+		model ModelInfo
+		want  bool
+	}{
+		{ModelInfo{ID: "meta-llama/llama-3.3-70b-instruct:free"}, true},
+		{ModelInfo{ID: "local-model", PricingKnown: true}, true},
+		{ModelInfo{ID: "gemini-2.0-flash"}, false},
+		{ModelInfo{ID: "openai/gpt-4o", PricingKnown: true, InputPer1M: 2.5}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.model.IsFree(); got != tt.want {
+			t.Errorf("IsFree(%q) = %v, want %v", tt.model.ID, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package characters keeps the per-series character profiles (gender, speech
+// register, relationships) that tell the model how each character speaks and
+// is spoken about. It is stored as characters.json next to the media files.
+package characters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FileName is the name of the character profiles next to the media files
+const FileName = "characters.json"
+
+// Profile describes a character of the series
+type Profile struct {
+	Name          string            `json:"name"`
+	Aliases       []string          `json:"aliases,omitempty"`       // Other names and speaker labels
+	Gender        string            `json:"gender,omitempty"`        // e.g. female, male, nonbinary
+	Register      string            `json:"register,omitempty"`      // Speech register, e.g. formal, rough, childish
+	Relationships map[string]string `json:"relationships,omitempty"` // Other character -> relationship
+	Notes         string            `json:"notes,omitempty"`
+}
+
+// Names returns the name and aliases of the character
+func (p Profile) Names() []string {
+	return append([]string{p.Name}, p.Aliases...)
+}
+
+// Describe renders the profile as one prompt line
+func (p Profile) Describe() string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+	if len(p.Aliases) > 0 {
+		b.WriteString(" (also " + strings.Join(p.Aliases, ", ") + ")")
+	}
+
+	var details []string
+	if p.Gender != "" {
+		details = append(details, p.Gender)
+	}
+	if p.Register != "" {
+		details = append(details, "speaks "+p.Register)
+	}
+	others := make([]string, 0, len(p.Relationships))
+	for other := range p.Relationships {
+		others = append(others, other)
+	}
+	sort.Strings(others)
+	for _, other := range others {
+		details = append(details, fmt.Sprintf("%s of %s", p.Relationships[other], other))
+	}
+	if p.Notes != "" {
+		details = append(details, p.Notes)
+	}
+
+	if len(details) > 0 {
+		b.WriteString(": " + strings.Join(details, "; "))
+	}
+	return b.String()
+}
+
+// Store is the character profiles of one series
+type Store struct {
+	Path     string
+	Profiles []Profile
+}
+
+// PathFor returns the character profiles path for a media directory
+func PathFor(dir string) string {
+	return filepath.Join(dir, FileName)
+}
+
+// Load reads the character profiles; a missing file gives an empty store
+func Load(path string) (*Store, error) {
+	s := &Store{Path: path, Profiles: []Profile{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.Profiles); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Find returns the character with the given name or alias, ignoring case
+func (s *Store) Find(name string) *Profile {
+	name = strings.TrimSpace(name)
+	for i := range s.Profiles {
+		for _, n := range s.Profiles[i].Names() {
+			if strings.EqualFold(n, name) {
+				return &s.Profiles[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Relevant returns the characters among the speakers or mentioned in the
+// texts, in file order
+func (s *Store) Relevant(speakers, texts []string) []Profile {
+	all := words(strings.Join(texts, "\n"))
+	var relevant []Profile
+	for _, p := range s.Profiles {
+		for _, name := range p.Names() {
+			if name != "" && (containsFold(speakers, name) || mentions(all, name)) {
+				relevant = append(relevant, p)
+				break
+			}
+		}
+	}
+	return relevant
+}
+
+// containsFold reports whether names has name, ignoring case
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits a text into lowercase words of letters and digits
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// mentions reports whether the words of a text have name as whole words
+func mentions(textWords []string, name string) bool {
+	want := words(name)
+	if len(want) == 0 {
+		return false
+	}
+	for i := 0; i+len(want) <= len(textWords); i++ {
+		if slices.Equal(textWords[i:i+len(want)], want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package characters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoad tests reading profiles and finding them by name or alias
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	profiles := `[{"name":"Naruto","aliases":["NARUTO","Uzumaki"],"gender":"male","register":"brash and informal"},
+{"name":"Hinata","gender":"female","register":"shy and polite","relationships":{"Naruto":"admirer"}}]`
+	if err := os.WriteFile(path, []byte(profiles), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if p := s.Find("uzumaki"); p == nil || p.Name != "Naruto" {
+		t.Errorf("Find should match aliases ignoring case, got %+v", p)
+	}
+	if s.Find("Sasuke") != nil {
+		t.Error("Find should not match unknown names")
+	}
+
+	if s, err := Load(filepath.Join(t.TempDir(), FileName)); err != nil || len(s.Profiles) != 0 {
+		t.Errorf("a missing file should give an empty store, got %+v, %v", s, err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for an invalid file")
+	}
+}
+
+// TestDescribe tests the prompt line of a profile
+func TestDescribe(t *testing.T) {
+	p := Profile{
+		Name:          "Hinata",
+		Aliases:       []string{"Hyuga"},
+		Gender:        "female",
+		Register:      "shy and polite",
+		Relationships: map[string]string{"Neji": "cousin", "Naruto": "admirer"},
+	}
+	want := "Hinata (also Hyuga): female; speaks shy and polite; admirer of Naruto; cousin of Neji"
+	if got := p.Describe(); got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+	if got := (Profile{Name: "Neji"}).Describe(); got != "Neji" {
+		t.Errorf("Describe() = %q, want Neji", got)
+	}
+}
+
+// TestRelevant tests picking the profiles of speakers and mentioned characters
+func TestRelevant(t *testing.T) {
+	s := &Store{Profiles: []Profile{
+		{Name: "Naruto", Aliases: []string{"NARUTO"}},
+		{Name: "Hinata"},
+		{Name: "Ren"},
+		{Name: "Sasuke"},
+		{Name: "Rock Lee"},
+	}}
+
+	got := s.Relevant([]string{"naruto"}, []string{"Where is Sasuke?", "Rental shop."})
+	if len(got) != 2 || got[0].Name != "Naruto" || got[1].Name != "Sasuke" {
+		t.Errorf("unexpected profiles %+v", got)
+	}
+
+	got = s.Relevant(nil, []string{"ROCK LEE!", "A rock."})
+	if len(got) != 1 || got[0].Name != "Rock Lee" {
+		t.Errorf("unexpected profiles for a multi-word name %+v", got)
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/agnivade/levenshtein"
 	_ "modernc.org/sqlite"
+
+	"github.com/lsilvatti/bakasub/internal/paths"
 )
 
 // Cache represents a thread-safe translation cache backed by SQLite
@@ -20,43 +24,144 @@ type Cache struct {
 
 // CacheEntry represents a cached translation
 type CacheEntry struct {
+	ID             int64
 	OriginalHash   string
 	OriginalText   string
 	TranslatedText string
 	LangPair       string
-	Similarity     float64 // Only populated for fuzzy matches
+	Model          string
+	Profile        string
+	GlossaryHash   string
+	ProjectID      string
+	Provenance     string    // machine, machine_lint_fixed or approved
+	Similarity     float64   // Only populated for fuzzy matches
+	CreatedAt      time.Time // Only populated by ListEntries and imports
+}
+
+// Translation provenance, from least to most trusted
+const (
+	ProvenanceMachine   = "machine"            // Returned by the AI as-is
+	ProvenanceLintFixed = "machine_lint_fixed" // AI output corrected by the linter
+	ProvenanceApproved  = "approved"           // Reviewed or edited by a human
+)
+
+// IsApproved reports whether a human approved the translation
+func (e CacheEntry) IsApproved() bool {
+	return e.Provenance == ProvenanceApproved
+}
+
+// Scope returns the context the entry was produced in
+func (e CacheEntry) Scope() Scope {
+	return Scope{
+		LangPair:     e.LangPair,
+		Model:        e.Model,
+		Profile:      e.Profile,
+		GlossaryHash: e.GlossaryHash,
+		ProjectID:    e.ProjectID,
+	}
+}
+
+// entryColumns lists the columns scanned by scanEntry
+const entryColumns = "id, original_hash, original_text, translated_text, lang_pair, model, profile, glossary_hash, project_id, provenance"
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanEntry reads a row selected with entryColumns
+func scanEntry(row rowScanner) (CacheEntry, error) {
+	var e CacheEntry
+	err := row.Scan(&e.ID, &e.OriginalHash, &e.OriginalText, &e.TranslatedText, &e.LangPair,
+		&e.Model, &e.Profile, &e.GlossaryHash, &e.ProjectID, &e.Provenance)
+	return e, err
 }
 
 // CacheStats represents cache statistics
 type CacheStats struct {
 	TotalEntries int
-	HitRate      float64
-	SavedCost    float64 // Estimated USD saved
+	Lookups      int
+	ExactHits    int
+	FuzzyHits    int
+	Misses       int
+	HitRate      float64 // Percentage of lookups served by the cache
+	SavedTokens  int
+	SavedCost    float64 // USD saved, priced for the model of each job
 }
 
+// dbFile is the name of the translation memory database, in the data
+// directory and, for versions that predate it, in the working directory
+const dbFile = "bakasub.db"
+
 var (
-	instance     *Cache
-	instanceOnce sync.Once
+	instance   *Cache
+	instanceMu sync.Mutex
 )
 
-// GetInstance returns the singleton cache instance
+// DefaultPath returns the location of the translation memory database
+func DefaultPath() string {
+	return filepath.Join(paths.DataDir(), dbFile)
+}
+
+// GetInstance returns the process-wide cache shared by every screen and the
+// watcher, opening it at dbPath ("" = DefaultPath) on first use. Callers must
+// not Close it; CloseInstance releases it when the application exits.
 func GetInstance(dbPath string) (*Cache, error) {
-	var initErr error
-	instanceOnce.Do(func() {
-		instance, initErr = newCache(dbPath)
-	})
-	return instance, initErr
+	instanceMu.Lock()
+	defer instanceMu.Unlock()
+
+	if instance == nil {
+		cache, err := Open(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		instance = cache
+	}
+	return instance, nil
 }
 
-// Open is a convenience function to open/create the cache database
-// If dbPath is empty, uses the default path (bakasub.db in config dir)
+// CloseInstance closes the shared cache, if it was opened
+func CloseInstance() error {
+	instanceMu.Lock()
+	defer instanceMu.Unlock()
+
+	if instance == nil {
+		return nil
+	}
+	err := instance.Close()
+	instance = nil
+	return err
+}
+
+// Open opens/creates a cache database owned by the caller.
+// If dbPath is empty, uses DefaultPath.
 func Open(dbPath string) (*Cache, error) {
 	if dbPath == "" {
-		dbPath = "bakasub.db"
+		dbPath = DefaultPath()
+		if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
 	}
 	return newCache(dbPath)
 }
 
+// MigrateLegacy moves a bakasub.db left in the working directory by older
+// versions, with its WAL files, to DefaultPath. It reports whether the
+// database was moved.
+func MigrateLegacy() (bool, error) {
+	dest := DefaultPath()
+	if _, err := os.Stat(dest); err == nil {
+		return false, nil
+	}
+	// The WAL may hold committed transactions, so it moves before the database
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if _, err := paths.Migrate(dbFile+suffix, dest+suffix); err != nil {
+			return false, err
+		}
+	}
+	return paths.Migrate(dbFile, dest)
+}
+
 // newCache creates a new cache instance
 func newCache(dbPath string) (*Cache, error) {
 	db, err := sql.Open("sqlite", dbPath)
@@ -88,196 +193,372 @@ func newCache(dbPath string) (*Cache, error) {
 	return cache, nil
 }
 
-// initSchema creates the cache table if it doesn't exist
-func (c *Cache) initSchema() error {
-	schema := `
+// cacheTableSchema creates the translation memory table
+const cacheTableSchema = `
 	CREATE TABLE IF NOT EXISTS cache (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		original_hash TEXT NOT NULL,
 		original_text TEXT NOT NULL,
 		translated_text TEXT NOT NULL,
 		lang_pair TEXT NOT NULL,
+		model TEXT NOT NULL DEFAULT '',
+		profile TEXT NOT NULL DEFAULT '',
+		glossary_hash TEXT NOT NULL DEFAULT '',
+		project_id TEXT NOT NULL DEFAULT '',
+		provenance TEXT NOT NULL DEFAULT 'machine',
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		last_used DATETIME DEFAULT CURRENT_TIMESTAMP,
 		use_count INTEGER DEFAULT 1,
-		UNIQUE(original_hash, lang_pair)
+		UNIQUE(original_hash, lang_pair, model, profile, glossary_hash, project_id)
 	);
+	`
 
-	CREATE INDEX IF NOT EXISTS idx_original_hash ON cache(original_hash);
+// cacheIndexes creates the translation memory indexes
+const cacheIndexes = `
+	CREATE INDEX IF NOT EXISTS idx_original_hash ON cache(original_hash, lang_pair);
 	CREATE INDEX IF NOT EXISTS idx_lang_pair ON cache(lang_pair);
 	CREATE INDEX IF NOT EXISTS idx_original_text ON cache(original_text);
 	CREATE INDEX IF NOT EXISTS idx_last_used ON cache(last_used);
+	CREATE INDEX IF NOT EXISTS idx_project_id ON cache(project_id);
 	`
 
-	_, err := c.db.Exec(schema)
+// initSchema creates the tables if they don't exist and migrates older databases
+func (c *Cache) initSchema() error {
+	if _, err := c.db.Exec(cacheTableSchema); err != nil {
+		return err
+	}
+
+	if err := c.migrateScopeColumns(); err != nil {
+		return fmt.Errorf("failed to migrate cache table: %w", err)
+	}
+
+	if err := c.migrateProvenanceColumn(); err != nil {
+		return fmt.Errorf("failed to migrate cache table: %w", err)
+	}
+
+	if _, err := c.db.Exec(cacheIndexes); err != nil {
+		return err
+	}
+
+	if err := c.initFuzzyIndex(); err != nil {
+		return fmt.Errorf("failed to create fuzzy index: %w", err)
+	}
+
+	if _, err := c.db.Exec(usageSchema); err != nil {
+		return err
+	}
+
+	_, err := c.db.Exec(lookupSchema)
 	return err
 }
 
-// hashText generates a SHA256 hash of the text
-func hashText(text string) string {
-	hash := sha256.Sum256([]byte(text))
-	return fmt.Sprintf("%x", hash)
-}
+// columnExists reports whether a table has the given column
+func (c *Cache) columnExists(table, column string) (bool, error) {
+	rows, err := c.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
 
-// calculateSimilarity returns a similarity score between 0.0 (different) and 1.0 (identical)
-func calculateSimilarity(s1, s2 string) float64 {
-	// Normalize strings (lowercase, trim)
-	s1 = strings.ToLower(strings.TrimSpace(s1))
-	s2 = strings.ToLower(strings.TrimSpace(s2))
+	for rows.Next() {
+		var (
+			cid        int
+			name, typ  string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &defaultVal, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
 
-	if s1 == s2 {
-		return 1.0
+// migrateScopeColumns upgrades cache tables created before entries were
+// scoped. SQLite cannot change a UNIQUE constraint in place, so the table is
+// rebuilt; existing entries keep an empty scope (model, profile, glossary
+// and project unknown) and are only served by the non-strict policies.
+func (c *Cache) migrateScopeColumns() error {
+	exists, err := c.columnExists("cache", "model")
+	if err != nil || exists {
+		return err
 	}
 
-	// Calculate Levenshtein distance
-	distance := levenshtein.ComputeDistance(s1, s2)
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	// Convert to similarity percentage
-	maxLen := max(len(s1), len(s2))
-	if maxLen == 0 {
-		return 1.0
+	if _, err := tx.Exec("ALTER TABLE cache RENAME TO cache_legacy"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(cacheTableSchema); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO cache (original_hash, original_text, translated_text, lang_pair, created_at, last_used, use_count)
+		SELECT original_hash, original_text, translated_text, lang_pair, created_at, last_used, use_count
+		FROM cache_legacy
+	`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DROP TABLE cache_legacy"); err != nil {
+		return err
 	}
 
-	similarity := 1.0 - (float64(distance) / float64(maxLen))
-	return similarity
+	return tx.Commit()
 }
 
-// GetExactMatch retrieves an exact match from cache
-func (c *Cache) GetExactMatch(text, langPair string) (string, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// migrateProvenanceColumn adds the provenance column to cache tables created
+// before human approval was tracked. Existing entries are machine translations.
+func (c *Cache) migrateProvenanceColumn() error {
+	exists, err := c.columnExists("cache", "provenance")
+	if err != nil || exists {
+		return err
+	}
 
-	hash := hashText(text)
+	_, err = c.db.Exec("ALTER TABLE cache ADD COLUMN provenance TEXT NOT NULL DEFAULT 'machine'")
+	return err
+}
 
-	var translated string
-	err := c.db.QueryRow(`
-		SELECT translated_text 
-		FROM cache 
-		WHERE original_hash = ? AND lang_pair = ?
-		LIMIT 1
-	`, hash, langPair).Scan(&translated)
+// hashText generates a SHA256 hash of the text
+func hashText(text string) string {
+	hash := sha256.Sum256([]byte(text))
+	return fmt.Sprintf("%x", hash)
+}
 
-	if err == sql.ErrNoRows {
+// GetExactMatch retrieves an exact match from any scope of the language pair
+func (c *Cache) GetExactMatch(text, langPair string) (string, bool) {
+	entry, found := c.GetScopedMatch(text, Scope{LangPair: langPair}, PolicyAny)
+	if !found {
 		return "", false
 	}
+	return entry.TranslatedText, true
+}
+
+// GetScopedMatch retrieves an exact match allowed by the lookup policy,
+// preferring the entry whose scope is closest to the requested one
+func (c *Cache) GetScopedMatch(text string, scope Scope, policy LookupPolicy) (*CacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, err := c.exactMatch(hashText(text), scope, policy)
 	if err != nil {
-		return "", false
+		return nil, false
 	}
 
 	// Update usage stats
-	go c.updateUsage(hash, langPair)
+	go c.updateUsage(entry.ID)
 
-	return translated, true
+	return entry, true
 }
 
-// GetFuzzyMatch finds the best fuzzy match above the threshold
-func (c *Cache) GetFuzzyMatch(text, langPair string, threshold float64) (*CacheEntry, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// exactMatch queries the best exact match; callers hold the lock
+func (c *Cache) exactMatch(hash string, scope Scope, policy LookupPolicy) (*CacheEntry, error) {
+	where, whereArgs, order, orderArgs := scopeQuery(scope, policy)
+	args := append([]any{hash, scope.LangPair}, whereArgs...)
+	args = append(args, orderArgs...)
 
-	// First try exact match
-	hash := hashText(text)
-	var entry CacheEntry
-	err := c.db.QueryRow(`
-		SELECT original_hash, original_text, translated_text, lang_pair
-		FROM cache 
-		WHERE original_hash = ? AND lang_pair = ?
+	entry, err := scanEntry(c.db.QueryRow(`
+		SELECT `+entryColumns+`
+		FROM cache
+		WHERE original_hash = ? AND lang_pair = ?`+where+order+`
 		LIMIT 1
-	`, hash, langPair).Scan(&entry.OriginalHash, &entry.OriginalText, &entry.TranslatedText, &entry.LangPair)
-
-	if err == nil {
-		entry.Similarity = 1.0
-		go c.updateUsage(hash, langPair)
-		return &entry, true
+	`, args...))
+	if err != nil {
+		return nil, err
 	}
 
-	// Fuzzy search: get candidates from same language pair
-	// Use text length as a heuristic filter - similar length texts are more likely to match
-	textLen := len(text)
-	minLen := int(float64(textLen) * threshold) // e.g., 95% of original length
-	maxLen := int(float64(textLen) / threshold) // e.g., 105% of original length
+	entry.Similarity = 1.0
+	return &entry, nil
+}
 
-	rows, err := c.db.Query(`
-		SELECT original_hash, original_text, translated_text, lang_pair
-		FROM cache 
-		WHERE lang_pair = ? AND LENGTH(original_text) BETWEEN ? AND ?
-		ORDER BY last_used DESC
-		LIMIT 500
-	`, langPair, minLen, maxLen)
+// matchChunkSize keeps bulk lookups under SQLite's bound parameter limit
+const matchChunkSize = 500
 
-	if err != nil {
-		return nil, false
+// GetScopedMatches resolves exact matches for many texts at once, keyed by
+// text. Texts without an entry allowed by the policy are left out.
+func (c *Cache) GetScopedMatches(texts []string, scope Scope, policy LookupPolicy) (map[string]*CacheEntry, error) {
+	byHash := make(map[string]string, len(texts))
+	for _, text := range texts {
+		byHash[hashText(text)] = text
+	}
+	hashes := make([]string, 0, len(byHash))
+	for hash := range byHash {
+		hashes = append(hashes, hash)
 	}
-	defer rows.Close()
 
-	var bestMatch *CacheEntry
-	var bestSimilarity float64
+	where, whereArgs, order, orderArgs := scopeQuery(scope, policy)
+	matches := make(map[string]*CacheEntry, len(hashes))
+	var ids []int64
 
-	// Calculate similarity for each candidate
-	for rows.Next() {
-		var candidate CacheEntry
-		if err := rows.Scan(&candidate.OriginalHash, &candidate.OriginalText, &candidate.TranslatedText, &candidate.LangPair); err != nil {
-			continue
+	c.mu.RLock()
+	for start := 0; start < len(hashes); start += matchChunkSize {
+		chunk := hashes[start:min(start+matchChunkSize, len(hashes))]
+
+		args := make([]any, 0, len(chunk)+len(whereArgs)+len(orderArgs)+1)
+		for _, hash := range chunk {
+			args = append(args, hash)
+		}
+		args = append(args, scope.LangPair)
+		args = append(args, whereArgs...)
+		args = append(args, orderArgs...)
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(chunk)), ", ")
+		rows, err := c.db.Query(`
+			SELECT `+entryColumns+`
+			FROM cache
+			WHERE original_hash IN (`+placeholders+`) AND lang_pair = ?`+where+order, args...)
+		if err != nil {
+			c.mu.RUnlock()
+			return nil, err
 		}
 
-		similarity := calculateSimilarity(text, candidate.OriginalText)
-		if similarity >= threshold && similarity > bestSimilarity {
-			bestSimilarity = similarity
-			bestMatch = &candidate
-			bestMatch.Similarity = similarity
+		// Rows come best-first, so the first one of each text wins
+		for rows.Next() {
+			entry, err := scanEntry(rows)
+			if err != nil {
+				rows.Close()
+				c.mu.RUnlock()
+				return nil, err
+			}
+			text, ok := byHash[entry.OriginalHash]
+			if !ok || matches[text] != nil {
+				continue
+			}
+			entry.Similarity = 1.0
+			matches[text] = &entry
+			ids = append(ids, entry.ID)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			c.mu.RUnlock()
+			return nil, err
 		}
 	}
+	c.mu.RUnlock()
 
-	if bestMatch != nil {
-		go c.updateUsage(bestMatch.OriginalHash, langPair)
-		return bestMatch, true
+	if len(ids) > 0 {
+		go c.updateUsage(ids...)
 	}
+	return matches, nil
+}
 
-	return nil, false
+// GetFuzzyMatch finds the best fuzzy match above the threshold in any scope of the language pair
+func (c *Cache) GetFuzzyMatch(text, langPair string, threshold float64) (*CacheEntry, bool) {
+	return c.GetScopedFuzzyMatch(text, Scope{LangPair: langPair}, PolicyAny, threshold)
 }
 
-// SaveTranslation saves a translation to the cache
+// GetScopedFuzzyMatch finds the best fuzzy match above the threshold allowed by the lookup policy
+func (c *Cache) GetScopedFuzzyMatch(text string, scope Scope, policy LookupPolicy, threshold float64) (*CacheEntry, bool) {
+	// First try exact match
+	if entry, found := c.GetScopedMatch(text, scope, policy); found {
+		return entry, true
+	}
+
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultFuzzyThreshold
+	}
+
+	// Candidates come from the trigram index; scoring runs without holding the lock
+	candidates, err := c.fuzzyCandidates(text, scope, policy, threshold)
+	if err != nil {
+		return nil, false
+	}
+
+	bestMatch := bestFuzzyMatch(text, candidates, threshold)
+	if bestMatch == nil {
+		return nil, false
+	}
+
+	go c.updateUsage(bestMatch.ID)
+	return bestMatch, true
+}
+
+// SaveTranslation saves a translation to the cache without scope details
 func (c *Cache) SaveTranslation(original, translated, langPair string) error {
+	return c.SaveScopedTranslation(original, translated, Scope{LangPair: langPair})
+}
+
+// SaveScopedTranslation saves a machine translation produced in the given scope.
+// Entries of other scopes for the same text are left untouched.
+func (c *Cache) SaveScopedTranslation(original, translated string, scope Scope) error {
+	return c.SaveWithProvenance(original, translated, scope, ProvenanceMachine)
+}
+
+// upsertMachine inserts a translation; an existing entry of the same scope is
+// updated unless a human approved it
+const upsertMachine = `
+	INSERT INTO cache (original_hash, original_text, translated_text, lang_pair, model, profile, glossary_hash, project_id, provenance)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(original_hash, lang_pair, model, profile, glossary_hash, project_id) DO UPDATE SET
+		translated_text = excluded.translated_text,
+		provenance = excluded.provenance,
+		last_used = CURRENT_TIMESTAMP,
+		use_count = cache.use_count + 1
+	WHERE cache.provenance != 'approved'
+	`
+
+// SaveWithProvenance saves a translation produced in the given scope.
+// Human-approved entries are never overwritten; use ApproveTranslation to replace them.
+func (c *Cache) SaveWithProvenance(original, translated string, scope Scope, provenance string) error {
+	if provenance == "" {
+		provenance = ProvenanceMachine
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	hash := hashText(original)
-
-	// Check if entry already exists
-	var exists int
-	err := c.db.QueryRow(`
-		SELECT COUNT(*) FROM cache 
-		WHERE original_hash = ? AND lang_pair = ?
-	`, hash, langPair).Scan(&exists)
+	_, err := c.db.Exec(upsertMachine, hashText(original), original, translated, scope.LangPair,
+		scope.Model, scope.Profile, scope.GlossaryHash, scope.ProjectID, provenance)
 
 	if err != nil {
-		return fmt.Errorf("failed to check existing entry: %w", err)
+		return fmt.Errorf("failed to save cache entry: %w", err)
 	}
 
-	if exists > 0 {
-		// Update existing entry
-		_, err = c.db.Exec(`
-			UPDATE cache 
-			SET translated_text = ?, last_used = CURRENT_TIMESTAMP, use_count = use_count + 1
-			WHERE original_hash = ? AND lang_pair = ?
-		`, translated, hash, langPair)
-		return err
-	}
+	return nil
+}
 
-	// Insert new entry
-	_, err = c.db.Exec(`
-		INSERT INTO cache (original_hash, original_text, translated_text, lang_pair)
-		VALUES (?, ?, ?, ?)
-	`, hash, original, translated, langPair)
+// ApproveTranslation stores a human-approved translation in the given scope,
+// replacing any existing entry of that scope
+func (c *Cache) ApproveTranslation(original, translated string, scope Scope) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.db.Exec(`
+		INSERT INTO cache (original_hash, original_text, translated_text, lang_pair, model, profile, glossary_hash, project_id, provenance)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, 'approved')
+		ON CONFLICT(original_hash, lang_pair, model, profile, glossary_hash, project_id) DO UPDATE SET
+			translated_text = excluded.translated_text,
+			provenance = 'approved',
+			last_used = CURRENT_TIMESTAMP
+	`, hashText(original), original, translated, scope.LangPair, scope.Model, scope.Profile, scope.GlossaryHash, scope.ProjectID)
 
 	if err != nil {
-		return fmt.Errorf("failed to insert cache entry: %w", err)
+		return fmt.Errorf("failed to approve cache entry: %w", err)
 	}
 
 	return nil
 }
 
-// SaveBatch saves multiple translations in a single transaction
+// ApproveEdit records a human correction of a line as the approved
+// translation of original in scope, whatever the memory held for it: the
+// reviewed text may have been normalized, fuzzy-matched or served from
+// another scope, or never cached at all
+func (c *Cache) ApproveEdit(original, corrected string, scope Scope) error {
+	if original == "" || scope.LangPair == "" {
+		return fmt.Errorf("failed to approve edit: original text and language pair are required")
+	}
+	return c.ApproveTranslation(original, corrected, scope)
+}
+
+// SaveBatch saves multiple machine translations in a single transaction
 func (c *Cache) SaveBatch(entries []CacheEntry) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -288,22 +569,20 @@ func (c *Cache) SaveBatch(entries []CacheEntry) error {
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare(`
-		INSERT INTO cache (original_hash, original_text, translated_text, lang_pair)
-		VALUES (?, ?, ?, ?)
-		ON CONFLICT(original_hash, lang_pair) DO UPDATE SET
-			translated_text = excluded.translated_text,
-			last_used = CURRENT_TIMESTAMP,
-			use_count = cache.use_count + 1
-	`)
+	stmt, err := tx.Prepare(upsertMachine)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
 	for _, entry := range entries {
+		provenance := entry.Provenance
+		if provenance == "" || provenance == ProvenanceApproved {
+			provenance = ProvenanceMachine
+		}
 		hash := hashText(entry.OriginalText)
-		_, err := stmt.Exec(hash, entry.OriginalText, entry.TranslatedText, entry.LangPair)
+		_, err := stmt.Exec(hash, entry.OriginalText, entry.TranslatedText, entry.LangPair,
+			entry.Model, entry.Profile, entry.GlossaryHash, entry.ProjectID, provenance)
 		if err != nil {
 			return fmt.Errorf("failed to insert entry: %w", err)
 		}
@@ -312,19 +591,21 @@ func (c *Cache) SaveBatch(entries []CacheEntry) error {
 	return tx.Commit()
 }
 
-// updateUsage updates the last_used timestamp and use_count
-func (c *Cache) updateUsage(hash, langPair string) {
+// updateUsage updates the last_used timestamp and use_count of entries
+func (c *Cache) updateUsage(ids ...int64) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.db.Exec(`
-		UPDATE cache 
-		SET last_used = CURRENT_TIMESTAMP, use_count = use_count + 1
-		WHERE original_hash = ? AND lang_pair = ?
-	`, hash, langPair)
+	for _, id := range ids {
+		c.db.Exec(`
+			UPDATE cache 
+			SET last_used = CURRENT_TIMESTAMP, use_count = use_count + 1
+			WHERE id = ?
+		`, id)
+	}
 }
 
-// GetStats returns cache statistics
+// GetStats returns cache statistics from the recorded lookups
 func (c *Cache) GetStats() (*CacheStats, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -337,22 +618,18 @@ func (c *Cache) GetStats() (*CacheStats, error) {
 		return nil, fmt.Errorf("failed to get total entries: %w", err)
 	}
 
-	// Calculate average reuse (hit rate approximation)
-	var avgUseCount sql.NullFloat64
-	err = c.db.QueryRow("SELECT AVG(use_count) FROM cache").Scan(&avgUseCount)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get average use count: %w", err)
-	}
-
-	if avgUseCount.Valid && avgUseCount.Float64 > 0 {
-		stats.HitRate = (avgUseCount.Float64 - 1) / avgUseCount.Float64 * 100
+	var lookups LookupSummary
+	if err := c.db.QueryRow("SELECT " + lookupSums + " FROM lookup_log").Scan(summaryFields(&lookups)...); err != nil {
+		return nil, fmt.Errorf("failed to get lookups: %w", err)
 	}
 
-	// Estimate cost savings (assuming $0.15 per 1M tokens, ~300 tokens per entry)
-	tokensPerEntry := 300
-	costPerMillion := 0.15
-	totalTokens := stats.TotalEntries * tokensPerEntry
-	stats.SavedCost = (float64(totalTokens) / 1000000) * costPerMillion * (stats.HitRate / 100)
+	stats.Lookups = lookups.Lookups
+	stats.ExactHits = lookups.ExactHits
+	stats.FuzzyHits = lookups.FuzzyHits
+	stats.Misses = lookups.Misses
+	stats.HitRate = lookups.HitRate()
+	stats.SavedTokens = lookups.SavedTokens
+	stats.SavedCost = lookups.SavedCost
 
 	return &stats, nil
 }
@@ -1,16 +1,15 @@
 package db
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
-	"sync"
 	"testing"
 )
 
-// resetSingleton resets the singleton for testing
+// resetSingleton closes the shared instance for testing
 func resetSingleton() {
-	instance = nil
-	instanceOnce = sync.Once{}
+	CloseInstance()
 }
 
 // TestCacheStruct tests Cache structure
@@ -109,6 +108,70 @@ func TestCacheSingleton(t *testing.T) {
 	if cache.db == nil {
 		t.Error("cache.db should not be nil")
 	}
+
+	// Later callers share the open instance, whatever path they ask for
+	again, err := GetInstance("")
+	if err != nil || again != cache {
+		t.Errorf("GetInstance should return the shared instance, got %p (err %v), want %p", again, err, cache)
+	}
+
+	if err := CloseInstance(); err != nil {
+		t.Fatalf("CloseInstance failed: %v", err)
+	}
+	reopened, err := GetInstance(dbPath)
+	if err != nil {
+		t.Fatalf("GetInstance after CloseInstance failed: %v", err)
+	}
+	if reopened == cache {
+		t.Error("GetInstance should open a new instance after CloseInstance")
+	}
+	resetSingleton()
+}
+
+// TestDefaultPathHonorsHome tests that BAKASUB_HOME moves the database
+func TestDefaultPathHonorsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("BAKASUB_HOME", home)
+
+	if got, want := DefaultPath(), filepath.Join(home, "bakasub.db"); got != want {
+		t.Errorf("DefaultPath() = %q, want %q", got, want)
+	}
+}
+
+// TestMigrateLegacy tests that a database in the working directory moves to the data directory
+func TestMigrateLegacy(t *testing.T) {
+	home := filepath.Join(t.TempDir(), "home")
+	t.Setenv("BAKASUB_HOME", home)
+	t.Chdir(t.TempDir())
+
+	legacy, err := Open("bakasub.db")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	legacy.SaveTranslation("Hello", "Olá", "en->pt")
+	legacy.Close()
+
+	moved, err := MigrateLegacy()
+	if err != nil || !moved {
+		t.Fatalf("MigrateLegacy() = %v, %v; want moved", moved, err)
+	}
+	if _, err := os.Stat("bakasub.db"); !os.IsNotExist(err) {
+		t.Error("legacy database should be gone from the working directory")
+	}
+
+	cache, err := Open("")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer cache.Close()
+	if got, ok := cache.GetExactMatch("Hello", "en->pt"); !ok || got != "Olá" {
+		t.Errorf("migrated database should keep its entries, got %q", got)
+	}
+
+	// A second run finds nothing to move
+	if moved, err := MigrateLegacy(); err != nil || moved {
+		t.Errorf("second MigrateLegacy() = %v, %v; want nothing moved", moved, err)
+	}
 }
 
 // TestCacheInvalidPath tests GetInstance with an invalid path
@@ -412,3 +475,167 @@ func TestMax(t *testing.T) {
 		}
 	}
 }
+
+// TestApprovedEntriesAreNotOverwritten tests that machine saves keep human-approved text
+func TestApprovedEntriesAreNotOverwritten(t *testing.T) {
+	cache := openScopeTestCache(t)
+	scope := Scope{LangPair: "en->pt", Model: "gpt-4o", Profile: "anime"}
+
+	if err := cache.SaveScopedTranslation("Hello", "Olá", scope); err != nil {
+		t.Fatalf("SaveScopedTranslation failed: %v", err)
+	}
+	if err := cache.ApproveTranslation("Hello", "Oi", scope); err != nil {
+		t.Fatalf("ApproveTranslation failed: %v", err)
+	}
+	if err := cache.SaveWithProvenance("Hello", "Olá!", scope, ProvenanceLintFixed); err != nil {
+		t.Fatalf("SaveWithProvenance failed: %v", err)
+	}
+	if err := cache.SaveBatch([]CacheEntry{{OriginalText: "Hello", TranslatedText: "Olá!!", LangPair: "en->pt",
+		Model: "gpt-4o", Profile: "anime", Provenance: ProvenanceApproved}}); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	entry, found := cache.GetScopedMatch("Hello", scope, PolicyStrict)
+	if !found {
+		t.Fatal("expected approved entry")
+	}
+	if entry.TranslatedText != "Oi" || !entry.IsApproved() {
+		t.Errorf("got %q (%s), want approved %q", entry.TranslatedText, entry.Provenance, "Oi")
+	}
+}
+
+// TestLookupPrefersApproved tests that approved entries win over closer machine entries
+func TestLookupPrefersApproved(t *testing.T) {
+	cache := openScopeTestCache(t)
+	scope := Scope{LangPair: "en->pt", Model: "gpt-4o", ProjectID: "show"}
+
+	if err := cache.SaveScopedTranslation("Hello", "Olá", scope); err != nil {
+		t.Fatalf("SaveScopedTranslation failed: %v", err)
+	}
+	other := scope
+	other.Model = "gemini-2.0-flash"
+	if err := cache.ApproveTranslation("Hello", "Oi", other); err != nil {
+		t.Fatalf("ApproveTranslation failed: %v", err)
+	}
+
+	// Approved text is model independent, so even strict lookups serve it
+	for _, policy := range []LookupPolicy{PolicyStrict, PolicySameProject, PolicyAny} {
+		entry, found := cache.GetScopedMatch("Hello", scope, policy)
+		if !found || entry.TranslatedText != "Oi" {
+			t.Errorf("%s: got %+v, want approved entry", policy, entry)
+		}
+	}
+
+	entry, found := cache.GetScopedFuzzyMatch("Hello!", scope, PolicyStrict, 0.8)
+	if !found || entry.TranslatedText != "Oi" {
+		t.Errorf("fuzzy: got %+v, want approved entry", entry)
+	}
+}
+
+// TestApproveEdit tests recording a review correction
+func TestApproveEdit(t *testing.T) {
+	cache := openScopeTestCache(t)
+	scope := Scope{LangPair: "en->pt", Model: "gpt-4o"}
+	other := Scope{LangPair: "en->pt", Model: "gpt-4o", ProjectID: "other-series"}
+
+	// The review showed "Ele disse “oi”" after typography normalization
+	cache.SaveScopedTranslation(`He said "hi"`, `Ele disse "oi"`, scope)
+	cache.SaveScopedTranslation(`He said "hi"`, `Ele disse "oi"`, other)
+	cache.SaveScopedTranslation("Hi", "Olá", scope)
+
+	if err := cache.ApproveEdit(`He said "hi"`, "Ele disse “olá”", scope); err != nil {
+		t.Fatalf("ApproveEdit failed: %v", err)
+	}
+	entry, _ := cache.GetScopedMatch(`He said "hi"`, scope, PolicyStrict)
+	if entry == nil || entry.TranslatedText != "Ele disse “olá”" || entry.Provenance != ProvenanceApproved {
+		t.Errorf("edited line: got %+v, want approved correction", entry)
+	}
+	entry, _ = cache.GetScopedMatch("Hi", scope, PolicyStrict)
+	if entry == nil || entry.Provenance != ProvenanceMachine {
+		t.Errorf("Hi: got %+v, want untouched machine entry", entry)
+	}
+	entry, _ = cache.GetScopedMatch(`He said "hi"`, other, PolicyStrict)
+	if entry == nil || entry.Provenance != ProvenanceMachine {
+		t.Errorf("edited line in another scope: got %+v, want untouched machine entry", entry)
+	}
+
+	// Lines served from elsewhere have no entry in the scope yet
+	if err := cache.ApproveEdit("Bye", "Tchau", scope); err != nil {
+		t.Fatalf("ApproveEdit failed: %v", err)
+	}
+	if entry, _ = cache.GetScopedMatch("Bye", scope, PolicyStrict); entry == nil || !entry.IsApproved() {
+		t.Errorf("Bye: got %+v, want inserted approved entry", entry)
+	}
+
+	// Without the original there is nothing to scope the correction to
+	if err := cache.ApproveEdit("", "Oi", scope); err == nil {
+		t.Error("expected error for an edit without original")
+	}
+	if err := cache.ApproveEdit("Hi", "Oi", Scope{}); err == nil {
+		t.Error("expected error for an edit without language pair")
+	}
+}
+
+// TestMigrateProvenanceColumn tests upgrading scoped tables without provenance
+func TestMigrateProvenanceColumn(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "scoped.db")
+
+	cache, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	cache.SaveTranslation("Hello", "Olá", "en->pt")
+	if _, err := cache.db.Exec("ALTER TABLE cache DROP COLUMN provenance"); err != nil {
+		t.Fatalf("failed to drop column: %v", err)
+	}
+	cache.Close()
+
+	cache, err = Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed on old database: %v", err)
+	}
+	defer cache.Close()
+
+	entry, found := cache.GetScopedMatch("Hello", Scope{LangPair: "en->pt"}, PolicyAny)
+	if !found || entry.Provenance != ProvenanceMachine {
+		t.Errorf("got %+v, want machine entry after migration", entry)
+	}
+}
+
+// TestGetScopedMatches tests bulk lookups across several query chunks
+func TestGetScopedMatches(t *testing.T) {
+	cache := openScopeTestCache(t)
+
+	scope := Scope{LangPair: "en->pt", Model: "gpt-4o"}
+	other := Scope{LangPair: "en->pt", Model: "gpt-4o", ProjectID: "other-series"}
+	cache.SaveScopedTranslation("Eh?", "Hã?", scope)
+	cache.SaveScopedTranslation("Eh?", "Hein?", other)
+	cache.SaveScopedTranslation("Wait!", "Espera!", other)
+
+	texts := []string{"Eh?", "Wait!", "Eh?"}
+	for i := 0; i < matchChunkSize; i++ {
+		texts = append(texts, fmt.Sprintf("missing %d", i))
+	}
+
+	matches, err := cache.GetScopedMatches(texts, scope, PolicyStrict)
+	if err != nil {
+		t.Fatalf("GetScopedMatches failed: %v", err)
+	}
+	if len(matches) != 1 || matches["Eh?"] == nil || matches["Eh?"].TranslatedText != "Hã?" {
+		t.Errorf("strict matches = %v, want only Eh? -> Hã?", matches)
+	}
+
+	matches, err = cache.GetScopedMatches(texts, scope, PolicyAny)
+	if err != nil {
+		t.Fatalf("GetScopedMatches failed: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2", len(matches))
+	}
+	if got := matches["Eh?"].TranslatedText; got != "Hã?" {
+		t.Errorf("Eh? = %q, want closest scope %q", got, "Hã?")
+	}
+	if got := matches["Wait!"].TranslatedText; got != "Espera!" {
+		t.Errorf("Wait! = %q, want %q", got, "Espera!")
+	}
+}
@@ -0,0 +1,256 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EvictionPolicy selects which entries are removed first when the cache is over its limits
+type EvictionPolicy string
+
+const (
+	EvictLRU EvictionPolicy = "lru" // Least recently used first
+	EvictLFU EvictionPolicy = "lfu" // Least frequently used first
+)
+
+// ParseEvictionPolicy converts a config value to a policy, defaulting to LRU
+func ParseEvictionPolicy(s string) EvictionPolicy {
+	if EvictionPolicy(strings.ToLower(strings.TrimSpace(s))) == EvictLFU {
+		return EvictLFU
+	}
+	return EvictLRU
+}
+
+// evictionOrder is the ORDER BY clause ranking entries by eviction priority
+func (p EvictionPolicy) evictionOrder() string {
+	if p == EvictLFU {
+		return " ORDER BY use_count ASC, last_used ASC, id ASC"
+	}
+	return " ORDER BY last_used ASC, use_count ASC, id ASC"
+}
+
+// EvictionLimits bounds the cache size (0 = unlimited)
+type EvictionLimits struct {
+	MaxEntries int
+	MaxBytes   int64
+	Policy     EvictionPolicy
+}
+
+// Enabled reports whether any limit is set
+func (l EvictionLimits) Enabled() bool {
+	return l.MaxEntries > 0 || l.MaxBytes > 0
+}
+
+// EvictedEntry describes an entry removed by eviction
+type EvictedEntry struct {
+	OriginalText   string
+	TranslatedText string
+	LangPair       string
+	UseCount       int
+	LastUsed       time.Time
+}
+
+// evictionReportSample caps how many evicted entries a report lists
+const evictionReportSample = 20
+
+// EvictionReport describes what an eviction run removed
+type EvictionReport struct {
+	Policy        EvictionPolicy
+	EntriesBefore int
+	EntriesAfter  int
+	BytesBefore   int64
+	BytesAfter    int64
+	EvictedCount  int
+	Evicted       []EvictedEntry // The first evicted entries, in eviction order
+	Protected     int            // Approved entries, which are never evicted
+	OverLimit     bool           // Still over a limit because only approved entries remain
+}
+
+// String summarizes the report on one line
+func (r EvictionReport) String() string {
+	s := fmt.Sprintf("evicted %d of %d entries (%s), %s -> %s",
+		r.EvictedCount, r.EntriesBefore, strings.ToUpper(string(r.Policy)),
+		FormatBytes(r.BytesBefore), FormatBytes(r.BytesAfter))
+	if r.OverLimit {
+		s += fmt.Sprintf(", still over limit (%d approved entries protected)", r.Protected)
+	}
+	return s
+}
+
+// FormatBytes formats a size with binary units
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// Size returns the bytes used by the database, excluding free pages
+func (c *Cache) Size() (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.usedBytes()
+}
+
+// usedBytes computes the database size; callers hold the lock
+func (c *Cache) usedBytes() (int64, error) {
+	var pageCount, freePages, pageSize int64
+	if err := c.db.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, err
+	}
+	if err := c.db.QueryRow("PRAGMA freelist_count").Scan(&freePages); err != nil {
+		return 0, err
+	}
+	if err := c.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, err
+	}
+	return (pageCount - freePages) * pageSize, nil
+}
+
+// maxSizeRounds bounds the delete/measure iterations of a size-limited eviction
+const maxSizeRounds = 8
+
+// Evict removes entries until the cache fits its limits, least valuable
+// first according to the policy. Approved entries are never evicted.
+func (c *Cache) Evict(limits EvictionLimits) (*EvictionReport, error) {
+	if limits.Policy == "" {
+		limits.Policy = EvictLRU
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &EvictionReport{Policy: limits.Policy}
+	var err error
+	if report.EntriesBefore, err = c.countEntries(""); err != nil {
+		return nil, err
+	}
+	if report.Protected, err = c.countEntries(" WHERE provenance = 'approved'"); err != nil {
+		return nil, err
+	}
+	if report.BytesBefore, err = c.usedBytes(); err != nil {
+		return nil, fmt.Errorf("failed to measure cache size: %w", err)
+	}
+
+	entries := report.EntriesBefore
+	evictable := entries - report.Protected
+
+	if limits.MaxEntries > 0 && entries > limits.MaxEntries {
+		n := min(entries-limits.MaxEntries, evictable)
+		if err := c.evictEntries(n, limits.Policy, report); err != nil {
+			return nil, err
+		}
+		entries -= n
+		evictable -= n
+		report.OverLimit = entries > limits.MaxEntries
+	}
+
+	size := report.BytesBefore
+	if limits.MaxBytes > 0 && size > limits.MaxBytes {
+		// Entries vary in size, so estimate how many to remove and measure again
+		for round := 0; round < maxSizeRounds && size > limits.MaxBytes && evictable > 0; round++ {
+			perEntry := size / int64(max(entries, 1))
+			if perEntry < 1 {
+				perEntry = 1
+			}
+			n := min(int((size-limits.MaxBytes)/perEntry)+1, evictable)
+			if err := c.evictEntries(n, limits.Policy, report); err != nil {
+				return nil, err
+			}
+			entries -= n
+			evictable -= n
+			if size, err = c.usedBytes(); err != nil {
+				return nil, fmt.Errorf("failed to measure cache size: %w", err)
+			}
+		}
+		report.OverLimit = report.OverLimit || size > limits.MaxBytes
+	}
+
+	// Return the freed pages to the file system
+	if report.EvictedCount > 0 && limits.MaxBytes > 0 {
+		if _, err := c.db.Exec("VACUUM"); err != nil {
+			return nil, fmt.Errorf("failed to compact database: %w", err)
+		}
+	}
+
+	report.EntriesAfter = entries
+	if report.BytesAfter, err = c.usedBytes(); err != nil {
+		return nil, fmt.Errorf("failed to measure cache size: %w", err)
+	}
+	return report, nil
+}
+
+// countEntries counts cache entries matching a WHERE clause; callers hold the lock
+func (c *Cache) countEntries(where string) (int, error) {
+	var n int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM cache" + where).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count entries: %w", err)
+	}
+	return n, nil
+}
+
+// evictEntries deletes the n unapproved entries ranked first by the policy,
+// adding them to the report; callers hold the lock
+func (c *Cache) evictEntries(n int, policy EvictionPolicy, report *EvictionReport) error {
+	if n <= 0 {
+		return nil
+	}
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, original_text, translated_text, lang_pair, use_count, last_used
+		FROM cache
+		WHERE provenance != 'approved'`+policy.evictionOrder()+`
+		LIMIT ?
+	`, n)
+	if err != nil {
+		return fmt.Errorf("failed to select entries to evict: %w", err)
+	}
+
+	var ids []any
+	for rows.Next() {
+		var id int64
+		var e EvictedEntry
+		var lastUsed sql.NullString
+		if err := rows.Scan(&id, &e.OriginalText, &e.TranslatedText, &e.LangPair, &e.UseCount, &lastUsed); err != nil {
+			rows.Close()
+			return err
+		}
+		e.LastUsed = parseSQLiteTime(lastUsed.String)
+		ids = append(ids, id)
+		if len(report.Evicted) < evictionReportSample {
+			report.Evicted = append(report.Evicted, e)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(ids); start += matchChunkSize {
+		chunk := ids[start:min(start+matchChunkSize, len(ids))]
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(chunk)), ", ")
+		if _, err := tx.Exec("DELETE FROM cache WHERE id IN ("+placeholders+")", chunk...); err != nil {
+			return fmt.Errorf("failed to evict entries: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	report.EvictedCount += len(ids)
+	return nil
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// setUsage sets the usage columns of an entry directly
+func setUsage(t *testing.T, cache *Cache, original, lastUsed string, useCount int) {
+	t.Helper()
+	if _, err := cache.db.Exec("UPDATE cache SET last_used = ?, use_count = ? WHERE original_text = ?",
+		lastUsed, useCount, original); err != nil {
+		t.Fatalf("failed to set usage: %v", err)
+	}
+}
+
+// remaining returns the original texts left in the cache
+func remaining(t *testing.T, cache *Cache) map[string]bool {
+	t.Helper()
+	entries, err := cache.ListEntries(EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries failed: %v", err)
+	}
+	texts := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		texts[e.OriginalText] = true
+	}
+	return texts
+}
+
+// TestEvictMaxEntries tests LRU and LFU ordering under an entry limit
+func TestEvictMaxEntries(t *testing.T) {
+	tests := []struct {
+		policy EvictionPolicy
+		kept   []string
+	}{
+		// "old" was used long ago but often; "rare" recently but once
+		{EvictLRU, []string{"rare", "fresh"}},
+		{EvictLFU, []string{"old", "fresh"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			cache := openScopeTestCache(t)
+			scope := Scope{LangPair: "en->pt"}
+			for _, text := range []string{"old", "rare", "fresh", "stale"} {
+				cache.SaveScopedTranslation(text, text+"-pt", scope)
+			}
+			setUsage(t, cache, "old", "2024-01-01 00:00:00", 50)
+			setUsage(t, cache, "rare", "2024-06-01 00:00:00", 1)
+			setUsage(t, cache, "fresh", "2024-06-02 00:00:00", 10)
+			setUsage(t, cache, "stale", "2023-01-01 00:00:00", 1)
+
+			report, err := cache.Evict(EvictionLimits{MaxEntries: 2, Policy: tt.policy})
+			if err != nil {
+				t.Fatalf("Evict failed: %v", err)
+			}
+			if report.EvictedCount != 2 || report.EntriesAfter != 2 || report.OverLimit {
+				t.Errorf("unexpected report: %+v", report)
+			}
+			if len(report.Evicted) != 2 || report.Evicted[0].OriginalText != "stale" {
+				t.Errorf("stale entry should be evicted first, got %+v", report.Evicted)
+			}
+
+			left := remaining(t, cache)
+			for _, text := range tt.kept {
+				if !left[text] {
+					t.Errorf("%q should have been kept, left %v", text, left)
+				}
+			}
+		})
+	}
+}
+
+// TestEvictProtectsApproved tests that approved entries survive any limit
+func TestEvictProtectsApproved(t *testing.T) {
+	cache := openScopeTestCache(t)
+	scope := Scope{LangPair: "en->pt"}
+	cache.SaveScopedTranslation("machine", "máquina", scope)
+	cache.ApproveTranslation("reviewed", "revisado", scope)
+	setUsage(t, cache, "reviewed", "2000-01-01 00:00:00", 1)
+
+	report, err := cache.Evict(EvictionLimits{MaxEntries: 0, MaxBytes: 1})
+	if err != nil {
+		t.Fatalf("Evict failed: %v", err)
+	}
+
+	left := remaining(t, cache)
+	if !left["reviewed"] || left["machine"] {
+		t.Errorf("only the approved entry should remain, left %v", left)
+	}
+	if !report.OverLimit || report.Protected != 1 {
+		t.Errorf("report should flag the protected entry keeping the cache over its limit: %+v", report)
+	}
+	if !strings.Contains(report.String(), "protected") {
+		t.Errorf("String() should mention protected entries: %q", report.String())
+	}
+}
+
+// TestEvictMaxBytes tests that a size limit shrinks the database
+func TestEvictMaxBytes(t *testing.T) {
+	cache := openScopeTestCache(t)
+	scope := Scope{LangPair: "en->pt"}
+	long := strings.Repeat("lorem ipsum dolor sit amet ", 20)
+	for i := 0; i < 400; i++ {
+		cache.SaveScopedTranslation(fmt.Sprintf("%d %s", i, long), long, scope)
+	}
+
+	before, err := cache.Size()
+	if err != nil {
+		t.Fatalf("Size failed: %v", err)
+	}
+	limit := before / 2
+
+	report, err := cache.Evict(EvictionLimits{MaxBytes: limit})
+	if err != nil {
+		t.Fatalf("Evict failed: %v", err)
+	}
+	if report.EvictedCount == 0 || report.EvictedCount == 400 {
+		t.Errorf("expected part of the cache to be evicted, got %d", report.EvictedCount)
+	}
+	if report.BytesAfter > limit {
+		t.Errorf("size after eviction = %d, want <= %d", report.BytesAfter, limit)
+	}
+	if len(report.Evicted) != evictionReportSample {
+		t.Errorf("report lists %d entries, want the first %d", len(report.Evicted), evictionReportSample)
+	}
+}
+
+// TestEvictWithinLimits tests that nothing is evicted under the limits
+func TestEvictWithinLimits(t *testing.T) {
+	cache := openScopeTestCache(t)
+	cache.SaveScopedTranslation("Hello", "Olá", Scope{LangPair: "en->pt"})
+
+	report, err := cache.Evict(EvictionLimits{MaxEntries: 10, MaxBytes: 1 << 30})
+	if err != nil {
+		t.Fatalf("Evict failed: %v", err)
+	}
+	if report.EvictedCount != 0 || report.EntriesAfter != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+// TestParseEvictionPolicy tests config parsing
+func TestParseEvictionPolicy(t *testing.T) {
+	for input, want := range map[string]EvictionPolicy{"lfu": EvictLFU, " LFU ": EvictLFU, "lru": EvictLRU, "": EvictLRU, "bogus": EvictLRU} {
+		if got := ParseEvictionPolicy(input); got != want {
+			t.Errorf("ParseEvictionPolicy(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// TestFormatBytes tests size formatting
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 2048: "2.0 KiB", 5 << 20: "5.0 MiB"} {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+)
+
+// EntryFilter selects translation memory entries for export or import.
+// Zero values match everything.
+type EntryFilter struct {
+	LangPair  string    // e.g. "en->pt-br"
+	ProjectID string    // Project/series ID
+	Since     time.Time // Created at or after
+	Until     time.Time // Created before
+}
+
+// Matches reports whether an entry passes the filter.
+// Entries without a creation date pass the date range.
+func (f EntryFilter) Matches(e CacheEntry) bool {
+	if f.LangPair != "" && !strings.EqualFold(f.LangPair, e.LangPair) {
+		return false
+	}
+	if f.ProjectID != "" && f.ProjectID != e.ProjectID {
+		return false
+	}
+	if !e.CreatedAt.IsZero() {
+		if !f.Since.IsZero() && e.CreatedAt.Before(f.Since) {
+			return false
+		}
+		if !f.Until.IsZero() && !e.CreatedAt.Before(f.Until) {
+			return false
+		}
+	}
+	return true
+}
+
+// ListEntries returns the entries matching the filter, oldest first
+func (c *Cache) ListEntries(filter EntryFilter) ([]CacheEntry, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	query := "SELECT " + entryColumns + ", created_at FROM cache WHERE 1 = 1"
+	var args []any
+	if filter.LangPair != "" {
+		query += " AND lang_pair = ? COLLATE NOCASE"
+		args = append(args, filter.LangPair)
+	}
+	if filter.ProjectID != "" {
+		query += " AND project_id = ?"
+		args = append(args, filter.ProjectID)
+	}
+	if !filter.Since.IsZero() {
+		query += " AND created_at >= ?"
+		args = append(args, filter.Since.UTC().Format(sqliteTimeFormat))
+	}
+	if !filter.Until.IsZero() {
+		query += " AND created_at < ?"
+		args = append(args, filter.Until.UTC().Format(sqliteTimeFormat))
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []CacheEntry
+	for rows.Next() {
+		var e CacheEntry
+		var createdAt sql.NullString
+		if err := rows.Scan(&e.ID, &e.OriginalHash, &e.OriginalText, &e.TranslatedText, &e.LangPair,
+			&e.Model, &e.Profile, &e.GlossaryHash, &e.ProjectID, &e.Provenance, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to read cache entry: %w", err)
+		}
+		e.CreatedAt = parseSQLiteTime(createdAt.String)
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// ImportEntries merges entries into the cache in a single transaction,
+// resolving entries that already exist in the same scope with policy
+func (c *Cache) ImportEntries(entries []CacheEntry, policy exchange.ConflictPolicy) (exchange.ImportResult, error) {
+	var result exchange.ImportResult
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, entry := range entries {
+		if entry.OriginalText == "" || entry.LangPair == "" {
+			result.Skipped++
+			continue
+		}
+		if entry.Provenance == "" {
+			entry.Provenance = ProvenanceMachine
+		}
+		hash := hashText(entry.OriginalText)
+
+		var existing CacheEntry
+		err := tx.QueryRow(`
+			SELECT id, translated_text, provenance FROM cache
+			WHERE original_hash = ? AND lang_pair = ? COLLATE NOCASE AND model = ? AND profile = ? AND glossary_hash = ? AND project_id = ?
+		`, hash, entry.LangPair, entry.Model, entry.Profile, entry.GlossaryHash, entry.ProjectID).Scan(
+			&existing.ID, &existing.TranslatedText, &existing.Provenance)
+
+		switch {
+		case err == sql.ErrNoRows:
+			createdAt := entry.CreatedAt
+			if createdAt.IsZero() {
+				createdAt = time.Now()
+			}
+			if _, err := tx.Exec(`
+				INSERT INTO cache (original_hash, original_text, translated_text, lang_pair, model, profile, glossary_hash, project_id, provenance, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`, hash, entry.OriginalText, entry.TranslatedText, entry.LangPair, entry.Model, entry.Profile,
+				entry.GlossaryHash, entry.ProjectID, entry.Provenance, createdAt.UTC().Format(sqliteTimeFormat)); err != nil {
+				return result, fmt.Errorf("failed to insert entry: %w", err)
+			}
+			result.Added++
+
+		case err != nil:
+			return result, fmt.Errorf("failed to look up entry: %w", err)
+
+		case existing.TranslatedText == entry.TranslatedText && existing.Provenance == entry.Provenance:
+			result.Skipped++
+
+		default:
+			if existing.TranslatedText != entry.TranslatedText {
+				result.Conflicts = append(result.Conflicts, entry.OriginalText)
+			}
+			if !policy.Replaces(existing.IsApproved(), entry.IsApproved()) {
+				result.Skipped++
+				continue
+			}
+			if _, err := tx.Exec(`
+				UPDATE cache SET translated_text = ?, provenance = ?, last_used = CURRENT_TIMESTAMP
+				WHERE id = ?
+			`, entry.TranslatedText, entry.Provenance, existing.ID); err != nil {
+				return result, fmt.Errorf("failed to update entry: %w", err)
+			}
+			result.Updated++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("failed to commit import: %w", err)
+	}
+
+	return result, nil
+}
+
+// parseSQLiteTime parses a DATETIME column value (UTC); invalid values yield the zero time
+func parseSQLiteTime(s string) time.Time {
+	for _, layout := range []string{sqliteTimeFormat, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+)
+
+// TestListEntriesFilter tests filtering entries by lang pair, project and date
+func TestListEntriesFilter(t *testing.T) {
+	cache := openScopeTestCache(t)
+
+	old := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	if _, err := cache.ImportEntries([]CacheEntry{
+		{OriginalText: "Hello", TranslatedText: "Olá", LangPair: "en->pt", ProjectID: "show", CreatedAt: old},
+		{OriginalText: "Bye", TranslatedText: "Tchau", LangPair: "en->pt", ProjectID: "show", CreatedAt: recent},
+		{OriginalText: "Bye", TranslatedText: "Adiós", LangPair: "en->es", ProjectID: "movie", CreatedAt: recent},
+	}, exchange.ConflictKeep); err != nil {
+		t.Fatalf("ImportEntries failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter EntryFilter
+		want   int
+	}{
+		{"all", EntryFilter{}, 3},
+		{"lang pair", EntryFilter{LangPair: "EN->PT"}, 2},
+		{"project", EntryFilter{ProjectID: "movie"}, 1},
+		{"since", EntryFilter{Since: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}, 2},
+		{"until", EntryFilter{Until: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := cache.ListEntries(tt.filter)
+			if err != nil {
+				t.Fatalf("ListEntries failed: %v", err)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("got %d entries, want %d", len(entries), tt.want)
+			}
+			for _, e := range entries {
+				if !tt.filter.Matches(e) {
+					t.Errorf("entry %+v does not match its own filter", e)
+				}
+			}
+		})
+	}
+
+	entries, _ := cache.ListEntries(EntryFilter{ProjectID: "show"})
+	if len(entries) != 2 || !entries[0].CreatedAt.Equal(old) || entries[0].Provenance != ProvenanceMachine {
+		t.Errorf("expected oldest entry first with its creation date, got %+v", entries)
+	}
+}
+
+// TestImportConflictPolicies tests how imports resolve existing entries
+func TestImportConflictPolicies(t *testing.T) {
+	scope := Scope{LangPair: "en->pt"}
+	machine := CacheEntry{OriginalText: "Hello", TranslatedText: "Olá!", LangPair: "en->pt", Provenance: ProvenanceMachine}
+	approved := CacheEntry{OriginalText: "Hello", TranslatedText: "Oi", LangPair: "en->pt", Provenance: ProvenanceApproved}
+
+	hello := []string{"Hello"}
+
+	tests := []struct {
+		name       string
+		existing   string // provenance of the existing entry
+		incoming   CacheEntry
+		policy     exchange.ConflictPolicy
+		want       string
+		wantResult exchange.ImportResult
+	}{
+		{"keep", ProvenanceMachine, approved, exchange.ConflictKeep, "Olá", exchange.ImportResult{Skipped: 1, Conflicts: hello}},
+		{"overwrite approved", ProvenanceApproved, machine, exchange.ConflictOverwrite, "Olá!", exchange.ImportResult{Updated: 1, Conflicts: hello}},
+		{"prefer approved keeps approved", ProvenanceApproved, machine, exchange.ConflictPreferApproved, "Olá", exchange.ImportResult{Skipped: 1, Conflicts: hello}},
+		{"prefer approved takes approved", ProvenanceMachine, approved, exchange.ConflictPreferApproved, "Oi", exchange.ImportResult{Updated: 1, Conflicts: hello}},
+		{"prefer approved replaces machine", ProvenanceMachine, machine, exchange.ConflictPreferApproved, "Olá!", exchange.ImportResult{Updated: 1, Conflicts: hello}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := openScopeTestCache(t)
+			if tt.existing == ProvenanceApproved {
+				cache.ApproveTranslation("Hello", "Olá", scope)
+			} else {
+				cache.SaveScopedTranslation("Hello", "Olá", scope)
+			}
+
+			result, err := cache.ImportEntries([]CacheEntry{tt.incoming,
+				{OriginalText: "New", TranslatedText: "Novo", LangPair: "en->pt"}}, tt.policy)
+			if err != nil {
+				t.Fatalf("ImportEntries failed: %v", err)
+			}
+			tt.wantResult.Added = 1
+			if !reflect.DeepEqual(result, tt.wantResult) {
+				t.Errorf("result = %+v, want %+v", result, tt.wantResult)
+			}
+
+			entry, found := cache.GetScopedMatch("Hello", scope, PolicyStrict)
+			if !found || entry.TranslatedText != tt.want {
+				t.Errorf("got %+v, want %q", entry, tt.want)
+			}
+		})
+	}
+}
+
+// TestImportLangPairCase tests that language pairs differing only in case conflict
+func TestImportLangPairCase(t *testing.T) {
+	cache := openScopeTestCache(t)
+	cache.SaveScopedTranslation("Hello", "Olá", Scope{LangPair: "en->pt-br"})
+
+	result, err := cache.ImportEntries([]CacheEntry{
+		{OriginalText: "Hello", TranslatedText: "Oi", LangPair: "EN->PT-BR", Provenance: ProvenanceApproved},
+	}, exchange.ConflictPreferApproved)
+	if err != nil {
+		t.Fatalf("ImportEntries failed: %v", err)
+	}
+	if !reflect.DeepEqual(result, exchange.ImportResult{Updated: 1, Conflicts: []string{"Hello"}}) {
+		t.Errorf("result = %+v, want one update", result)
+	}
+
+	entries, _ := cache.ListEntries(EntryFilter{LangPair: "en->pt-br"})
+	if len(entries) != 1 || entries[0].TranslatedText != "Oi" {
+		t.Errorf("got %+v, want the existing entry updated", entries)
+	}
+}
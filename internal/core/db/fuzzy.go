@@ -0,0 +1,205 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/agnivade/levenshtein"
+)
+
+// DefaultFuzzyThreshold is the minimum similarity for reusing a near-identical line
+const DefaultFuzzyThreshold = 0.95
+
+// fuzzyCandidateLimit bounds how many indexed candidates are scored per lookup
+const fuzzyCandidateLimit = 50
+
+// ftsSchema creates the trigram index over original texts. It is an
+// external-content FTS5 table kept in sync with the cache table by triggers,
+// so the text is not stored twice.
+const ftsSchema = `
+	CREATE VIRTUAL TABLE IF NOT EXISTS cache_fts USING fts5(
+		original_text,
+		content = 'cache',
+		content_rowid = 'id',
+		tokenize = 'trigram'
+	);
+
+	CREATE TRIGGER IF NOT EXISTS cache_fts_insert AFTER INSERT ON cache BEGIN
+		INSERT INTO cache_fts(rowid, original_text) VALUES (new.id, new.original_text);
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS cache_fts_delete AFTER DELETE ON cache BEGIN
+		INSERT INTO cache_fts(cache_fts, rowid, original_text) VALUES ('delete', old.id, old.original_text);
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS cache_fts_update AFTER UPDATE OF original_text ON cache BEGIN
+		INSERT INTO cache_fts(cache_fts, rowid, original_text) VALUES ('delete', old.id, old.original_text);
+		INSERT INTO cache_fts(rowid, original_text) VALUES (new.id, new.original_text);
+	END;
+	`
+
+// initFuzzyIndex creates the trigram index, building it from existing
+// entries the first time it is created
+func (c *Cache) initFuzzyIndex() error {
+	var exists int
+	if err := c.db.QueryRow(
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'cache_fts'",
+	).Scan(&exists); err != nil {
+		return err
+	}
+
+	if _, err := c.db.Exec(ftsSchema); err != nil {
+		return err
+	}
+
+	if exists == 0 {
+		_, err := c.db.Exec("INSERT INTO cache_fts(cache_fts) VALUES ('rebuild')")
+		return err
+	}
+	return nil
+}
+
+// normalizeForMatch lowercases text and collapses whitespace runs
+func normalizeForMatch(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), unicode.IsSpace), " ")
+}
+
+// ftsPhrase quotes text as an FTS5 phrase. With the trigram tokenizer a
+// phrase matches entries containing it as a substring (case-insensitive).
+func ftsPhrase(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+}
+
+// candidateQuery builds an FTS5 query matching every entry within maxEdits
+// edits of text. Split into maxEdits+1 pieces, such an entry still contains
+// at least one piece verbatim, so OR-ing the pieces as substring phrases is
+// both complete and selective. When pieces would be shorter than a trigram,
+// any shared trigram is matched instead. It returns "" for texts too short
+// to index.
+func candidateQuery(text string, maxEdits int) string {
+	runes := []rune(normalizeForMatch(text))
+	if len(runes) < 3 {
+		return ""
+	}
+
+	pieces := maxEdits + 1
+	size := len(runes) / pieces
+	if size >= 3 {
+		terms := make([]string, 0, pieces)
+		for i := 0; i < pieces; i++ {
+			end := (i + 1) * size
+			if i == pieces-1 {
+				end = len(runes)
+			}
+			terms = append(terms, ftsPhrase(string(runes[i*size:end])))
+		}
+		return strings.Join(terms, " OR ")
+	}
+
+	seen := make(map[string]bool, len(runes))
+	terms := make([]string, 0, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		gram := string(runes[i : i+3])
+		if !seen[gram] {
+			seen[gram] = true
+			terms = append(terms, ftsPhrase(gram))
+		}
+	}
+	return strings.Join(terms, " OR ")
+}
+
+// calculateSimilarity returns a similarity score between 0.0 (different) and 1.0 (identical).
+// It is the Levenshtein distance over characters, ignoring case and
+// whitespace differences, relative to the longer text.
+func calculateSimilarity(s1, s2 string) float64 {
+	s1 = normalizeForMatch(s1)
+	s2 = normalizeForMatch(s2)
+
+	if s1 == s2 {
+		return 1.0
+	}
+
+	maxLen := max(utf8.RuneCountInString(s1), utf8.RuneCountInString(s2))
+	if maxLen == 0 {
+		return 1.0
+	}
+
+	distance := levenshtein.ComputeDistance(s1, s2)
+	return 1.0 - (float64(distance) / float64(maxLen))
+}
+
+// qualifiedEntryColumns is entryColumns for queries joining the FTS table,
+// which also has an original_text column
+var qualifiedEntryColumns = "cache." + strings.ReplaceAll(entryColumns, ", ", ", cache.")
+
+// fuzzyCandidates returns the indexed entries that may reach threshold
+// similarity with text, restricted to the scope, policy and a length window that could reach
+// threshold
+func (c *Cache) fuzzyCandidates(text string, scope Scope, policy LookupPolicy, threshold float64) ([]CacheEntry, error) {
+	// Similarity can't exceed shorter/longer length, so skip entries outside that window
+	textLen := utf8.RuneCountInString(normalizeForMatch(text))
+	minLen := int(float64(textLen) * threshold)
+	maxLen := int(float64(textLen)/threshold) + 1
+	maxEdits := int((1 - threshold) * float64(maxLen))
+
+	query := candidateQuery(text, maxEdits)
+	if query == "" {
+		return nil, nil
+	}
+
+	where, whereArgs, order, orderArgs := scopeQuery(scope, policy)
+	args := append([]any{query, scope.LangPair, minLen, maxLen}, whereArgs...)
+	args = append(args, orderArgs...)
+	args = append(args, fuzzyCandidateLimit)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// Rank by shared trigrams (BM25), then by scope
+	rows, err := c.db.Query(`
+		SELECT `+qualifiedEntryColumns+`
+		FROM cache_fts
+		CROSS JOIN cache ON cache.id = cache_fts.rowid
+		WHERE cache_fts MATCH ? AND lang_pair = ? AND LENGTH(cache.original_text) BETWEEN ? AND ?`+where+
+		strings.Replace(order, "ORDER BY", "ORDER BY cache_fts.rank,", 1)+`
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []CacheEntry
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, entry)
+	}
+	return candidates, rows.Err()
+}
+
+// bestFuzzyMatch scores candidates against text and returns the most similar
+// one reaching threshold. Ties go to approved entries, then to the earlier
+// (better scoped) candidate.
+func bestFuzzyMatch(text string, candidates []CacheEntry, threshold float64) *CacheEntry {
+	for i := range candidates {
+		candidates[i].Similarity = calculateSimilarity(text, candidates[i].OriginalText)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Similarity != candidates[j].Similarity {
+			return candidates[i].Similarity > candidates[j].Similarity
+		}
+		return candidates[i].IsApproved() && !candidates[j].IsApproved()
+	})
+
+	if len(candidates) == 0 || candidates[0].Similarity < threshold {
+		return nil
+	}
+	best := candidates[0]
+	return &best
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCandidateQuery tests FTS query construction
+func TestCandidateQuery(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxEdits int
+		want     string
+	}{
+		{"ab", 0, ""},
+		{"Hello  World", 0, `"hello world"`},
+		{"abcdefghi", 2, `"abc" OR "def" OR "ghi"`},
+		{"abcdefghij", 2, `"abc" OR "def" OR "ghij"`},
+		{`say "hi"`, 1, `"say " OR """hi"""`},
+		// Pieces shorter than a trigram: match any shared trigram
+		{"aaaab", 3, `"aaa" OR "aab"`},
+		{"おはよう", 1, `"おはよ" OR "はよう"`},
+	}
+
+	for _, tt := range tests {
+		if got := candidateQuery(tt.text, tt.maxEdits); got != tt.want {
+			t.Errorf("candidateQuery(%q, %d) = %s, want %s", tt.text, tt.maxEdits, got, tt.want)
+		}
+	}
+}
+
+// TestCalculateSimilarityUnicode tests that similarity counts characters, not bytes
+func TestCalculateSimilarityUnicode(t *testing.T) {
+	// One of four characters differs
+	if got := calculateSimilarity("おはよう", "おはよー"); got != 0.75 {
+		t.Errorf("similarity = %v, want 0.75", got)
+	}
+	if got := calculateSimilarity("Hello   world", "hello world"); got != 1.0 {
+		t.Errorf("whitespace-only difference = %v, want 1.0", got)
+	}
+}
+
+// TestFuzzyMatchBeyondRecentEntries tests that old entries are still found
+func TestFuzzyMatchBeyondRecentEntries(t *testing.T) {
+	cache := openScopeTestCache(t)
+
+	target := "The dragon sleeps beneath the northern mountain"
+	entries := []CacheEntry{{OriginalText: target, TranslatedText: "O dragão dorme", LangPair: "en->pt"}}
+	// Many more recent lines of the same length
+	for i := 0; i < 1000; i++ {
+		entries = append(entries, CacheEntry{
+			OriginalText:   fmt.Sprintf("Line number %05d of the filler dialogue text!!", i),
+			TranslatedText: "filler",
+			LangPair:       "en->pt",
+		})
+	}
+	if err := cache.SaveBatch(entries); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+
+	entry, found := cache.GetFuzzyMatch("The dragon sleeps beneath the northern mountain.", "en->pt", 0.95)
+	if !found || entry.TranslatedText != "O dragão dorme" {
+		t.Fatalf("expected fuzzy match for the old entry, got %+v", entry)
+	}
+	if entry.Similarity < 0.95 || entry.Similarity >= 1 {
+		t.Errorf("Similarity = %v, want in [0.95, 1)", entry.Similarity)
+	}
+}
+
+// TestFuzzyMatchThreshold tests that the threshold is honoured
+func TestFuzzyMatchThreshold(t *testing.T) {
+	cache := openScopeTestCache(t)
+	cache.SaveTranslation("Where are you going tonight?", "Aonde você vai hoje à noite?", "en->pt")
+
+	// 25/28 characters in common
+	query := "Where are you going today?"
+	if _, found := cache.GetFuzzyMatch(query, "en->pt", 0.95); found {
+		t.Error("should not match at 0.95")
+	}
+	if _, found := cache.GetFuzzyMatch(query, "en->pt", 0.80); !found {
+		t.Error("should match at 0.80")
+	}
+	if _, found := cache.GetFuzzyMatch(query, "en->es", 0.80); found {
+		t.Error("should not match another language pair")
+	}
+}
+
+// TestFuzzyIndexFollowsChanges tests that updates and deletes reach the index
+func TestFuzzyIndexFollowsChanges(t *testing.T) {
+	cache := openScopeTestCache(t)
+	cache.SaveTranslation("Let's go to the beach tomorrow", "Vamos à praia amanhã", "en->pt")
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, found := cache.GetFuzzyMatch("Let's go to the beach tomorrow!", "en->pt", 0.9); found {
+		t.Error("deleted entry should not be found")
+	}
+
+	var indexed int
+	if err := cache.db.QueryRow("SELECT COUNT(*) FROM cache_fts WHERE cache_fts MATCH '\"bea\"'").Scan(&indexed); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if indexed != 0 {
+		t.Errorf("index still has %d rows for deleted entries", indexed)
+	}
+}
+
+// TestFuzzyIndexBuiltForExistingDatabase tests that upgrading indexes existing entries
+func TestFuzzyIndexBuiltForExistingDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "old.db")
+
+	cache, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, stmt := range []string{
+		"DROP TRIGGER cache_fts_insert", "DROP TRIGGER cache_fts_delete",
+		"DROP TRIGGER cache_fts_update", "DROP TABLE cache_fts",
+	} {
+		if _, err := cache.db.Exec(stmt); err != nil {
+			t.Fatalf("%s failed: %v", stmt, err)
+		}
+	}
+	cache.SaveTranslation("A storm is coming from the sea", "Uma tempestade vem do mar", "en->pt")
+	cache.Close()
+
+	cache, err = Open(dbPath)
+	if err != nil {
+		t.Fatalf("re-open failed: %v", err)
+	}
+	defer cache.Close()
+
+	if _, found := cache.GetFuzzyMatch("A storm is coming from the sea!", "en->pt", 0.9); !found {
+		t.Error("expected entry saved before the index existed to be found")
+	}
+}
+
+// benchmarkCache fills a cache with n distinct lines
+func benchmarkCache(b *testing.B, n int) *Cache {
+	b.Helper()
+	cache, err := Open(filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatalf("Open failed: %v", err)
+	}
+	b.Cleanup(func() { cache.Close() })
+
+	words := strings.Fields("the a dragon knight sword castle night river storm we you never always " +
+		"fight run hide find lost friend enemy tomorrow today why where how believe promise")
+	entries := make([]CacheEntry, 0, n)
+	for i := 0; i < n; i++ {
+		var sb strings.Builder
+		for j, x := 0, i; j < 8; j++ {
+			sb.WriteString(words[(x+j*7)%len(words)])
+			sb.WriteByte(' ')
+			x /= 3
+		}
+		fmt.Fprintf(&sb, "#%d", i)
+		entries = append(entries, CacheEntry{OriginalText: sb.String(), TranslatedText: "x", LangPair: "en->pt"})
+	}
+	if err := cache.SaveBatch(entries); err != nil {
+		b.Fatalf("SaveBatch failed: %v", err)
+	}
+	return cache
+}
+
+func benchmarkFuzzyMatch(b *testing.B, n int) {
+	cache := benchmarkCache(b, n)
+	query := "the dragon knight sword castle night river storm #123456"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.GetFuzzyMatch(query, "en->pt", DefaultFuzzyThreshold)
+	}
+}
+
+func BenchmarkFuzzyMatch1K(b *testing.B)   { benchmarkFuzzyMatch(b, 1_000) }
+func BenchmarkFuzzyMatch10K(b *testing.B)  { benchmarkFuzzyMatch(b, 10_000) }
+func BenchmarkFuzzyMatch100K(b *testing.B) { benchmarkFuzzyMatch(b, 100_000) }
+
+// BenchmarkLengthWindowScan measures the previous approach (length window,
+// 500 most recent rows, Levenshtein on each) for comparison
+func BenchmarkLengthWindowScan(b *testing.B) {
+	cache := benchmarkCache(b, 100_000)
+	query := "the dragon knight sword castle night river storm #123456"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := cache.db.Query(`
+			SELECT original_text FROM cache
+			WHERE lang_pair = ? AND LENGTH(original_text) BETWEEN ? AND ?
+			ORDER BY last_used DESC LIMIT 500
+		`, "en->pt", int(float64(len(query))*0.95), int(float64(len(query))/0.95))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for rows.Next() {
+			var text sql.NullString
+			rows.Scan(&text)
+			calculateSimilarity(query, text.String)
+		}
+		rows.Close()
+	}
+}
+
+func BenchmarkCalculateSimilarity(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		calculateSimilarity("I can't believe you came all this way!", "I can't believe you came all the way!")
+	}
+}
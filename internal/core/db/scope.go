@@ -0,0 +1,103 @@
+package db
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Scope identifies the context a translation was produced in.
+// Entries are unique per original text and scope.
+type Scope struct {
+	LangPair     string
+	Model        string
+	Profile      string // Prompt profile (e.g. "anime")
+	GlossaryHash string // See GlossaryFingerprint
+	ProjectID    string // Optional project/series ID
+}
+
+// langPairSeparator joins source and target languages in LangPair
+const langPairSeparator = "->"
+
+// JoinLangPair builds a LangPair from source and target language codes
+func JoinLangPair(source, target string) string {
+	return source + langPairSeparator + target
+}
+
+// SplitLangPair returns the source and target language codes of a LangPair
+func SplitLangPair(langPair string) (source, target string) {
+	source, target, _ = strings.Cut(langPair, langPairSeparator)
+	return source, target
+}
+
+// LookupPolicy controls which scopes may serve a cached translation
+type LookupPolicy string
+
+const (
+	// PolicyStrict only reuses entries produced with the same model, profile,
+	// glossary and project
+	PolicyStrict LookupPolicy = "strict"
+	// PolicySameProject reuses any entry of the same project, preferring the
+	// closest model/profile/glossary match
+	PolicySameProject LookupPolicy = "same_project"
+	// PolicyAny reuses entries from any scope, preferring the closest match
+	PolicyAny LookupPolicy = "any"
+)
+
+// ParseLookupPolicy converts a config value to a policy, defaulting to strict
+func ParseLookupPolicy(s string) LookupPolicy {
+	switch LookupPolicy(strings.ToLower(strings.TrimSpace(s))) {
+	case PolicySameProject:
+		return PolicySameProject
+	case PolicyAny:
+		return PolicyAny
+	default:
+		return PolicyStrict
+	}
+}
+
+// GlossaryFingerprint returns a short, order-independent hash of the terms
+// of a glossary that change a translation. Identity mappings (names detected
+// per episode and series candidates kept as-is) are skipped, so a newly
+// detected name does not invalidate the translations of earlier episodes.
+// A glossary without such terms has an empty fingerprint.
+func GlossaryFingerprint(glossary map[string]string) string {
+	keys := make([]string, 0, len(glossary))
+	for k, v := range glossary {
+		if strings.TrimSpace(k) != strings.TrimSpace(v) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", k, glossary[k])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:16]
+}
+
+// scopeQuery returns the WHERE clause (after the hash/lang pair conditions)
+// and ORDER BY clause selecting entries for a scope under a policy
+func scopeQuery(scope Scope, policy LookupPolicy) (where string, whereArgs []any, order string, orderArgs []any) {
+	switch policy {
+	case PolicyAny:
+		// No restriction
+	case PolicySameProject:
+		where = " AND project_id = ?"
+		whereArgs = []any{scope.ProjectID}
+	default:
+		// Human-approved text does not depend on the model that drafted it
+		where = " AND (model = ? OR provenance = 'approved') AND profile = ? AND glossary_hash = ? AND project_id = ?"
+		whereArgs = []any{scope.Model, scope.Profile, scope.GlossaryHash, scope.ProjectID}
+	}
+
+	// Human-approved first, then closest scope, then most recently used
+	order = " ORDER BY (provenance = 'approved') DESC, (project_id = ?) + (model = ?) + (profile = ?) + (glossary_hash = ?) DESC, last_used DESC"
+	orderArgs = []any{scope.ProjectID, scope.Model, scope.Profile, scope.GlossaryHash}
+	return where, whereArgs, order, orderArgs
+}
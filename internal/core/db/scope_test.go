@@ -0,0 +1,199 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+// openScopeTestCache opens a fresh cache in a temporary directory
+func openScopeTestCache(t *testing.T) *Cache {
+	t.Helper()
+	cache, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { cache.Close() })
+	return cache
+}
+
+// TestScopedLookupPolicies tests that each policy only serves allowed scopes
+func TestScopedLookupPolicies(t *testing.T) {
+	cache := openScopeTestCache(t)
+
+	anime := Scope{LangPair: "en->pt", Model: "gpt-4o", Profile: "anime", GlossaryHash: "g1", ProjectID: "show-a"}
+	if err := cache.SaveScopedTranslation("Let's go!", "Vamos nessa!", anime); err != nil {
+		t.Fatalf("SaveScopedTranslation failed: %v", err)
+	}
+
+	documentary := Scope{LangPair: "en->pt", Model: "gemini-2.0-flash", Profile: "documentary", ProjectID: "show-a"}
+	otherProject := Scope{LangPair: "en->pt", Model: "gpt-4o", Profile: "anime", GlossaryHash: "g1", ProjectID: "show-b"}
+
+	tests := []struct {
+		name   string
+		scope  Scope
+		policy LookupPolicy
+		found  bool
+	}{
+		{"strict same scope", anime, PolicyStrict, true},
+		{"strict other model", documentary, PolicyStrict, false},
+		{"same project other model", documentary, PolicySameProject, true},
+		{"same project other project", otherProject, PolicySameProject, false},
+		{"any other project", otherProject, PolicyAny, true},
+	}
+
+	for _, tt := range tests {
+		_, found := cache.GetScopedMatch("Let's go!", tt.scope, tt.policy)
+		if found != tt.found {
+			t.Errorf("%s: found = %v, want %v", tt.name, found, tt.found)
+		}
+	}
+}
+
+// TestScopedSaveKeepsOtherScopes tests that saving in one scope does not overwrite another
+func TestScopedSaveKeepsOtherScopes(t *testing.T) {
+	cache := openScopeTestCache(t)
+
+	a := Scope{LangPair: "en->pt", Model: "gpt-4o", Profile: "anime"}
+	b := Scope{LangPair: "en->pt", Model: "gpt-4o", Profile: "documentary"}
+	cache.SaveScopedTranslation("Good morning", "Bom dia!", a)
+	cache.SaveScopedTranslation("Good morning", "Bom dia.", b)
+
+	entry, found := cache.GetScopedMatch("Good morning", a, PolicyStrict)
+	if !found || entry.TranslatedText != "Bom dia!" {
+		t.Errorf("expected anime translation, got %+v", entry)
+	}
+
+	// Non-strict lookups prefer the closest scope
+	entry, found = cache.GetScopedMatch("Good morning", Scope{LangPair: "en->pt", Profile: "documentary"}, PolicyAny)
+	if !found || entry.TranslatedText != "Bom dia." {
+		t.Errorf("expected closest (documentary) translation, got %+v", entry)
+	}
+
+	fuzzy, found := cache.GetScopedFuzzyMatch("Good morning!", b, PolicyStrict, 0.9)
+	if !found || fuzzy.TranslatedText != "Bom dia." || fuzzy.Profile != "documentary" {
+		t.Errorf("expected scoped fuzzy match, got %+v", fuzzy)
+	}
+}
+
+// TestMigrateLegacyCacheTable tests upgrading a database created before scoped entries
+func TestMigrateLegacyCacheTable(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+
+	legacy, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	_, err = legacy.Exec(`
+		CREATE TABLE cache (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			original_hash TEXT NOT NULL,
+			original_text TEXT NOT NULL,
+			translated_text TEXT NOT NULL,
+			lang_pair TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			last_used DATETIME DEFAULT CURRENT_TIMESTAMP,
+			use_count INTEGER DEFAULT 1,
+			UNIQUE(original_hash, lang_pair)
+		);
+		CREATE INDEX idx_original_hash ON cache(original_hash);
+	`)
+	if err != nil {
+		t.Fatalf("failed to create legacy table: %v", err)
+	}
+	if _, err := legacy.Exec(`INSERT INTO cache (original_hash, original_text, translated_text, lang_pair, use_count)
+		VALUES (?, ?, ?, ?, 3)`, hashText("Hello"), "Hello", "Olá", "en->pt"); err != nil {
+		t.Fatalf("failed to insert legacy row: %v", err)
+	}
+	legacy.Close()
+
+	cache, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed on legacy database: %v", err)
+	}
+	defer cache.Close()
+
+	// Legacy rows have an unknown scope: only non-strict lookups see them
+	scope := Scope{LangPair: "en->pt", Model: "gpt-4o", Profile: "anime"}
+	if _, found := cache.GetScopedMatch("Hello", scope, PolicyStrict); found {
+		t.Error("legacy entry should not satisfy a strict lookup")
+	}
+	if translated, found := cache.GetExactMatch("Hello", "en->pt"); !found || translated != "Olá" {
+		t.Errorf("expected legacy entry to survive migration, got %q", translated)
+	}
+
+	// The new unique key allows the same text in another scope
+	if err := cache.SaveScopedTranslation("Hello", "Oi", scope); err != nil {
+		t.Fatalf("SaveScopedTranslation after migration failed: %v", err)
+	}
+	stats, err := cache.GetStats()
+	if err != nil || stats.TotalEntries != 2 {
+		t.Errorf("expected 2 entries after migration, got %+v (%v)", stats, err)
+	}
+
+	// Re-opening an already migrated database is a no-op
+	cache.Close()
+	reopened, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("re-open failed: %v", err)
+	}
+	reopened.Close()
+}
+
+// TestGlossaryFingerprint tests glossary hashing
+func TestGlossaryFingerprint(t *testing.T) {
+	if GlossaryFingerprint(nil) != "" {
+		t.Error("empty glossary should have empty fingerprint")
+	}
+
+	a := GlossaryFingerprint(map[string]string{"Nakama": "Companheiros", "Jutsu": "Técnica"})
+	b := GlossaryFingerprint(map[string]string{"Jutsu": "Técnica", "Nakama": "Companheiros"})
+	c := GlossaryFingerprint(map[string]string{"Nakama": "Companheiros", "Jutsu": "Jutsu"})
+
+	if a != b {
+		t.Error("fingerprint should not depend on map order")
+	}
+	if a == c {
+		t.Error("different translations should change the fingerprint")
+	}
+
+	// Names kept as-is (per-episode NER, series candidates) must not change it
+	withNames := GlossaryFingerprint(map[string]string{"Nakama": "Companheiros", "Jutsu": "Técnica", "Kakashi": "Kakashi"})
+	if withNames != a {
+		t.Error("identity mappings should not change the fingerprint")
+	}
+	if GlossaryFingerprint(map[string]string{"Naruto": "Naruto"}) != "" {
+		t.Error("glossary of identity mappings should have empty fingerprint")
+	}
+	if len(a) != 16 {
+		t.Errorf("expected 16-char fingerprint, got %q", a)
+	}
+}
+
+// TestParseLookupPolicy tests config parsing
+func TestParseLookupPolicy(t *testing.T) {
+	tests := map[string]LookupPolicy{
+		"":             PolicyStrict,
+		"strict":       PolicyStrict,
+		"same_project": PolicySameProject,
+		"ANY":          PolicyAny,
+		"bogus":        PolicyStrict,
+	}
+
+	for input, want := range tests {
+		if got := ParseLookupPolicy(input); got != want {
+			t.Errorf("ParseLookupPolicy(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// TestLangPairHelpers tests joining and splitting language pairs
+func TestLangPairHelpers(t *testing.T) {
+	pair := JoinLangPair("en", "pt-br")
+	if pair != "en->pt-br" {
+		t.Errorf("JoinLangPair = %q", pair)
+	}
+	if source, target := SplitLangPair(pair); source != "en" || target != "pt-br" {
+		t.Errorf("SplitLangPair = %q, %q", source, target)
+	}
+}
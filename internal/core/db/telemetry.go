@@ -0,0 +1,283 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// LookupRecord counts the cache lookups made for a file of a job
+type LookupRecord struct {
+	JobID       string
+	LangPair    string
+	Model       string
+	Lookups     int
+	ExactHits   int
+	FuzzyHits   int
+	Misses      int
+	SavedTokens int     // Prompt + completion tokens the hits did not cost
+	SavedCost   float64 // USD, priced for Model when the lookups were made
+	CreatedAt   time.Time
+}
+
+// LookupSummary aggregates lookup records
+type LookupSummary struct {
+	Lookups     int
+	ExactHits   int
+	FuzzyHits   int
+	Misses      int
+	SavedTokens int
+	SavedCost   float64
+}
+
+// Hits returns the exact and fuzzy hits
+func (s LookupSummary) Hits() int {
+	return s.ExactHits + s.FuzzyHits
+}
+
+// HitRate returns the percentage of lookups served by the cache
+func (s LookupSummary) HitRate() float64 {
+	if s.Lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits()) / float64(s.Lookups) * 100
+}
+
+// LookupTrendPoint is the lookup summary of one day
+type LookupTrendPoint struct {
+	Day time.Time // Local midnight
+	LookupSummary
+}
+
+// LangPairLookups is the lookup summary of a language pair
+type LangPairLookups struct {
+	LangPair string
+	LookupSummary
+}
+
+// JobLookups is the lookup summary of a job
+type JobLookups struct {
+	JobID    string
+	LangPair string
+	LastAt   time.Time
+	LookupSummary
+}
+
+// lookupSchema creates the lookup telemetry table
+const lookupSchema = `
+	CREATE TABLE IF NOT EXISTS lookup_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_id TEXT NOT NULL DEFAULT '',
+		lang_pair TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		lookups INTEGER NOT NULL DEFAULT 0,
+		exact_hits INTEGER NOT NULL DEFAULT 0,
+		fuzzy_hits INTEGER NOT NULL DEFAULT 0,
+		misses INTEGER NOT NULL DEFAULT 0,
+		saved_tokens INTEGER NOT NULL DEFAULT 0,
+		saved_cost REAL NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_lookup_created_at ON lookup_log(created_at);
+	CREATE INDEX IF NOT EXISTS idx_lookup_job_id ON lookup_log(job_id);
+	`
+
+// lookupSums selects the columns scanned into a LookupSummary
+const lookupSums = `COALESCE(SUM(lookups), 0), COALESCE(SUM(exact_hits), 0), COALESCE(SUM(fuzzy_hits), 0),
+	COALESCE(SUM(misses), 0), COALESCE(SUM(saved_tokens), 0), COALESCE(SUM(saved_cost), 0)`
+
+// summaryFields returns the scan destinations of lookupSums
+func summaryFields(s *LookupSummary) []any {
+	return []any{&s.Lookups, &s.ExactHits, &s.FuzzyHits, &s.Misses, &s.SavedTokens, &s.SavedCost}
+}
+
+// RecordLookups persists the lookup counts of a file
+func (c *Cache) RecordLookups(rec LookupRecord) error {
+	if rec.Lookups == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	createdAt := rec.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	_, err := c.db.Exec(`
+		INSERT INTO lookup_log (job_id, lang_pair, model, lookups, exact_hits, fuzzy_hits, misses, saved_tokens, saved_cost, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, rec.JobID, rec.LangPair, rec.Model, rec.Lookups, rec.ExactHits, rec.FuzzyHits, rec.Misses,
+		rec.SavedTokens, rec.SavedCost, createdAt.UTC().Format(sqliteTimeFormat))
+
+	if err != nil {
+		return fmt.Errorf("failed to record lookups: %w", err)
+	}
+
+	return nil
+}
+
+// GetLookupSummary aggregates lookups recorded in [since, until)
+func (c *Cache) GetLookupSummary(since, until time.Time) (*LookupSummary, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var summary LookupSummary
+	err := c.db.QueryRow(`
+		SELECT `+lookupSums+`
+		FROM lookup_log
+		WHERE created_at >= ? AND created_at < ?
+	`, since.UTC().Format(sqliteTimeFormat), until.UTC().Format(sqliteTimeFormat)).Scan(summaryFields(&summary)...)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lookups: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// GetJobLookups aggregates lookups recorded for a job
+func (c *Cache) GetJobLookups(jobID string) (*LookupSummary, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var summary LookupSummary
+	err := c.db.QueryRow(`
+		SELECT `+lookupSums+`
+		FROM lookup_log
+		WHERE job_id = ?
+	`, jobID).Scan(summaryFields(&summary)...)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job lookups: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// GetLookupTrend returns one point per day for the days up to and including
+// now's, oldest first. Days without lookups are zero.
+func (c *Cache) GetLookupTrend(days int, now time.Time) ([]LookupTrendPoint, error) {
+	if days <= 0 {
+		return nil, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	points := make([]LookupTrendPoint, days)
+	for i := range points {
+		points[i].Day = today.AddDate(0, 0, i-days+1)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rows, err := c.db.Query(`
+		SELECT created_at, lookups, exact_hits, fuzzy_hits, misses, saved_tokens, saved_cost
+		FROM lookup_log
+		WHERE created_at >= ?
+	`, points[0].Day.UTC().Format(sqliteTimeFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lookup trend: %w", err)
+	}
+	defer rows.Close()
+
+	// Bucket in Go, since days start at local midnight while rows are stored in UTC
+	for rows.Next() {
+		var createdAt string
+		var s LookupSummary
+		if err := rows.Scan(append([]any{&createdAt}, summaryFields(&s)...)...); err != nil {
+			return nil, err
+		}
+		at := parseSQLiteTime(createdAt)
+		if at.IsZero() {
+			continue
+		}
+		at = at.In(now.Location())
+		i := int(time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, now.Location()).Sub(points[0].Day).Hours()/24 + 0.5)
+		if i < 0 || i >= days {
+			continue
+		}
+		points[i].LookupSummary.add(s)
+	}
+
+	return points, rows.Err()
+}
+
+// GetLookupsByLangPair aggregates all lookups per language pair, most looked up first
+func (c *Cache) GetLookupsByLangPair() ([]LangPairLookups, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rows, err := c.db.Query(`
+		SELECT lang_pair, ` + lookupSums + `
+		FROM lookup_log
+		GROUP BY lang_pair
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lookups by language pair: %w", err)
+	}
+	defer rows.Close()
+
+	var pairs []LangPairLookups
+	for rows.Next() {
+		var p LangPairLookups
+		if err := rows.Scan(append([]any{&p.LangPair}, summaryFields(&p.LookupSummary)...)...); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].Lookups != pairs[j].Lookups {
+			return pairs[i].Lookups > pairs[j].Lookups
+		}
+		return pairs[i].LangPair < pairs[j].LangPair
+	})
+	return pairs, nil
+}
+
+// GetRecentJobLookups aggregates the lookups of the most recent jobs, newest first
+func (c *Cache) GetRecentJobLookups(limit int) ([]JobLookups, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rows, err := c.db.Query(`
+		SELECT job_id, MAX(lang_pair), MAX(created_at), `+lookupSums+`
+		FROM lookup_log
+		WHERE job_id != ''
+		GROUP BY job_id
+		ORDER BY MAX(created_at) DESC, MAX(id) DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job lookups: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []JobLookups
+	for rows.Next() {
+		var j JobLookups
+		var lastAt string
+		if err := rows.Scan(append([]any{&j.JobID, &j.LangPair, &lastAt}, summaryFields(&j.LookupSummary)...)...); err != nil {
+			return nil, err
+		}
+		j.LastAt = parseSQLiteTime(lastAt)
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// add accumulates another summary
+func (s *LookupSummary) add(other LookupSummary) {
+	s.Lookups += other.Lookups
+	s.ExactHits += other.ExactHits
+	s.FuzzyHits += other.FuzzyHits
+	s.Misses += other.Misses
+	s.SavedTokens += other.SavedTokens
+	s.SavedCost += other.SavedCost
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestRecordLookups tests that lookups are aggregated per job and language pair
+func TestRecordLookups(t *testing.T) {
+	cache := openScopeTestCache(t)
+
+	records := []LookupRecord{
+		{JobID: "job-1", LangPair: "en->pt-br", Model: "gpt-4o", Lookups: 10, ExactHits: 4, FuzzyHits: 1, Misses: 5, SavedTokens: 200, SavedCost: 0.002},
+		{JobID: "job-1", LangPair: "en->pt-br", Model: "gpt-4o", Lookups: 10, ExactHits: 6, Misses: 4, SavedTokens: 300, SavedCost: 0.003},
+		{JobID: "job-2", LangPair: "ja->en", Model: "gemini-2.0-flash", Lookups: 5, Misses: 5},
+		{JobID: "job-3", LangPair: "ja->en"}, // Nothing looked up: not recorded
+	}
+	for _, rec := range records {
+		if err := cache.RecordLookups(rec); err != nil {
+			t.Fatalf("RecordLookups failed: %v", err)
+		}
+	}
+
+	job, err := cache.GetJobLookups("job-1")
+	if err != nil {
+		t.Fatalf("GetJobLookups failed: %v", err)
+	}
+	if job.Lookups != 20 || job.Hits() != 11 || job.Misses != 9 || job.SavedTokens != 500 {
+		t.Errorf("unexpected job summary: %+v", job)
+	}
+	if math.Abs(job.HitRate()-55) > 1e-9 {
+		t.Errorf("HitRate() = %.1f, want 55", job.HitRate())
+	}
+
+	pairs, err := cache.GetLookupsByLangPair()
+	if err != nil {
+		t.Fatalf("GetLookupsByLangPair failed: %v", err)
+	}
+	if len(pairs) != 2 || pairs[0].LangPair != "en->pt-br" || pairs[1].Lookups != 5 {
+		t.Errorf("unexpected language pairs: %+v", pairs)
+	}
+
+	jobs, err := cache.GetRecentJobLookups(10)
+	if err != nil {
+		t.Fatalf("GetRecentJobLookups failed: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2", len(jobs))
+	}
+	if jobs[0].JobID != "job-2" || jobs[0].LastAt.IsZero() {
+		t.Errorf("newest job = %+v, want job-2 with a timestamp", jobs[0])
+	}
+
+	stats, err := cache.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if stats.Lookups != 25 || stats.ExactHits != 10 || stats.FuzzyHits != 1 || stats.Misses != 14 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if math.Abs(stats.HitRate-44) > 1e-9 {
+		t.Errorf("HitRate = %.1f, want 44", stats.HitRate)
+	}
+	if math.Abs(stats.SavedCost-0.005) > 1e-9 {
+		t.Errorf("SavedCost = %f, want 0.005", stats.SavedCost)
+	}
+}
+
+// TestGetLookupTrend tests daily buckets including empty days
+func TestGetLookupTrend(t *testing.T) {
+	cache := openScopeTestCache(t)
+
+	now := time.Date(2024, 6, 15, 18, 0, 0, 0, time.Local)
+	cache.RecordLookups(LookupRecord{Lookups: 4, ExactHits: 3, Misses: 1, CreatedAt: now.Add(-time.Hour)})
+	cache.RecordLookups(LookupRecord{Lookups: 2, Misses: 2, CreatedAt: now.AddDate(0, 0, -2)})
+	cache.RecordLookups(LookupRecord{Lookups: 9, Misses: 9, CreatedAt: now.AddDate(0, 0, -30)})
+
+	points, err := cache.GetLookupTrend(7, now)
+	if err != nil {
+		t.Fatalf("GetLookupTrend failed: %v", err)
+	}
+	if len(points) != 7 {
+		t.Fatalf("got %d points, want 7", len(points))
+	}
+
+	if !points[6].Day.Equal(time.Date(2024, 6, 15, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("last day = %v, want 2024-06-15", points[6].Day)
+	}
+	if points[6].Lookups != 4 || math.Abs(points[6].HitRate()-75) > 1e-9 {
+		t.Errorf("today = %+v, want 4 lookups at 75%%", points[6].LookupSummary)
+	}
+	if points[4].Lookups != 2 {
+		t.Errorf("two days ago = %d lookups, want 2", points[4].Lookups)
+	}
+	if points[5].Lookups != 0 {
+		t.Errorf("yesterday = %d lookups, want 0", points[5].Lookups)
+	}
+}
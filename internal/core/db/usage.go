@@ -0,0 +1,122 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// UsageRecord represents the token usage of a single translation batch
+type UsageRecord struct {
+	JobID            string
+	FilePath         string
+	Provider         string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	CachedTokens     int
+	Cost             float64 // USD
+	CostReported     bool    // Whether Cost came from the provider (false = estimated)
+	CreatedAt        time.Time
+}
+
+// UsageSummary aggregates usage records over a period
+type UsageSummary struct {
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	CachedTokens     int
+	Cost             float64
+}
+
+// sqliteTimeFormat matches the format produced by CURRENT_TIMESTAMP
+const sqliteTimeFormat = "2006-01-02 15:04:05"
+
+// usageSchema creates the usage log table
+const usageSchema = `
+	CREATE TABLE IF NOT EXISTS usage_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_id TEXT NOT NULL DEFAULT '',
+		file_path TEXT NOT NULL DEFAULT '',
+		provider TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		prompt_tokens INTEGER NOT NULL DEFAULT 0,
+		completion_tokens INTEGER NOT NULL DEFAULT 0,
+		cached_tokens INTEGER NOT NULL DEFAULT 0,
+		cost REAL NOT NULL DEFAULT 0,
+		cost_reported INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_usage_created_at ON usage_log(created_at);
+	CREATE INDEX IF NOT EXISTS idx_usage_job_id ON usage_log(job_id);
+	`
+
+// RecordUsage persists the usage of a translation request
+func (c *Cache) RecordUsage(rec UsageRecord) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	createdAt := rec.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	_, err := c.db.Exec(`
+		INSERT INTO usage_log (job_id, file_path, provider, model, prompt_tokens, completion_tokens, cached_tokens, cost, cost_reported, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, rec.JobID, rec.FilePath, rec.Provider, rec.Model, rec.PromptTokens, rec.CompletionTokens,
+		rec.CachedTokens, rec.Cost, rec.CostReported, createdAt.UTC().Format(sqliteTimeFormat))
+
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsageSummary aggregates usage recorded in [since, until)
+func (c *Cache) GetUsageSummary(since, until time.Time) (*UsageSummary, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var summary UsageSummary
+	err := c.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0),
+			COALESCE(SUM(cached_tokens), 0), COALESCE(SUM(cost), 0)
+		FROM usage_log
+		WHERE created_at >= ? AND created_at < ?
+	`, since.UTC().Format(sqliteTimeFormat), until.UTC().Format(sqliteTimeFormat)).Scan(
+		&summary.Requests, &summary.PromptTokens, &summary.CompletionTokens, &summary.CachedTokens, &summary.Cost)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// GetMonthlyUsage aggregates usage for the calendar month containing t (local time)
+func (c *Cache) GetMonthlyUsage(t time.Time) (*UsageSummary, error) {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return c.GetUsageSummary(start, start.AddDate(0, 1, 0))
+}
+
+// GetJobUsage aggregates usage recorded for a job
+func (c *Cache) GetJobUsage(jobID string) (*UsageSummary, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var summary UsageSummary
+	err := c.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0),
+			COALESCE(SUM(cached_tokens), 0), COALESCE(SUM(cost), 0)
+		FROM usage_log
+		WHERE job_id = ?
+	`, jobID).Scan(&summary.Requests, &summary.PromptTokens, &summary.CompletionTokens, &summary.CachedTokens, &summary.Cost)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job usage: %w", err)
+	}
+
+	return &summary, nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRecordAndSummarizeUsage tests usage persistence and aggregation
+func TestRecordAndSummarizeUsage(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "bakasub-test-usage-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cache, err := Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer cache.Close()
+
+	now := time.Now()
+	records := []UsageRecord{
+		{JobID: "job-1", Model: "gpt-4o", PromptTokens: 100, CompletionTokens: 50, Cost: 0.01, CreatedAt: now},
+		{JobID: "job-1", Model: "gpt-4o", PromptTokens: 200, CompletionTokens: 60, CachedTokens: 20, Cost: 0.02, CreatedAt: now},
+		{JobID: "job-0", Model: "gpt-4o", PromptTokens: 999, Cost: 1.0, CreatedAt: now.AddDate(0, -2, 0)},
+	}
+	for _, rec := range records {
+		if err := cache.RecordUsage(rec); err != nil {
+			t.Fatalf("RecordUsage failed: %v", err)
+		}
+	}
+
+	job, err := cache.GetJobUsage("job-1")
+	if err != nil {
+		t.Fatalf("GetJobUsage failed: %v", err)
+	}
+	if job.Requests != 2 || job.PromptTokens != 300 || job.CompletionTokens != 110 || job.CachedTokens != 20 {
+		t.Errorf("unexpected job summary: %+v", job)
+	}
+
+	month, err := cache.GetMonthlyUsage(now)
+	if err != nil {
+		t.Fatalf("GetMonthlyUsage failed: %v", err)
+	}
+	if month.Requests != 2 {
+		t.Errorf("monthly Requests = %d, want 2 (old record excluded)", month.Requests)
+	}
+	if month.Cost < 0.0299 || month.Cost > 0.0301 {
+		t.Errorf("monthly Cost = %f, want 0.03", month.Cost)
+	}
+}
@@ -0,0 +1,131 @@
+// Package exchange holds what the translation memory and glossary imports
+// and exports share: file formats, conflict policies, import results and
+// CSV/TSV header parsing.
+package exchange
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Format identifies an exchange file format
+type Format string
+
+const (
+	FormatTMX Format = "tmx"
+	FormatTBX Format = "tbx"
+	FormatCSV Format = "csv"
+	FormatTSV Format = "tsv" // Also reads headerless Anki exports
+)
+
+// formatAliases maps other extensions to the format they hold
+var formatAliases = map[string]Format{"tab": FormatTSV, "txt": FormatTSV, "xml": FormatTBX}
+
+// ParseFormat converts a user value or file extension to one of the supported formats
+func ParseFormat(s string, supported ...Format) (Format, error) {
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "."))
+	format, ok := formatAliases[name]
+	if !ok {
+		format = Format(name)
+	}
+	if slices.Contains(supported, format) {
+		return format, nil
+	}
+
+	names := make([]string, len(supported))
+	for i, f := range supported {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown format %q (use %s)", s, strings.Join(names, ", "))
+}
+
+// FormatForPath guesses the format from a file extension
+func FormatForPath(path string, supported ...Format) (Format, error) {
+	return ParseFormat(filepath.Ext(path), supported...)
+}
+
+// Resolve returns format, or the format of path's extension when empty
+func Resolve(path string, format Format, supported ...Format) (Format, error) {
+	if format != "" {
+		return ParseFormat(string(format), supported...)
+	}
+	return FormatForPath(path, supported...)
+}
+
+// ConflictPolicy decides what an import does with entries that already exist
+type ConflictPolicy string
+
+const (
+	// ConflictKeep leaves existing entries untouched
+	ConflictKeep ConflictPolicy = "keep"
+	// ConflictOverwrite replaces existing entries
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictPreferApproved replaces existing entries unless that would
+	// drop a human-approved translation for an unapproved one
+	ConflictPreferApproved ConflictPolicy = "prefer_approved"
+)
+
+// ParseConflictPolicy converts a user value to a policy, defaulting to prefer_approved
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch ConflictPolicy(strings.ToLower(strings.TrimSpace(s))) {
+	case ConflictKeep:
+		return ConflictKeep, nil
+	case ConflictOverwrite:
+		return ConflictOverwrite, nil
+	case ConflictPreferApproved, "":
+		return ConflictPreferApproved, nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy %q (use keep, overwrite or prefer_approved)", s)
+	}
+}
+
+// Next cycles prefer_approved, keep and overwrite
+func (p ConflictPolicy) Next() ConflictPolicy {
+	switch p {
+	case ConflictPreferApproved:
+		return ConflictKeep
+	case ConflictKeep:
+		return ConflictOverwrite
+	}
+	return ConflictPreferApproved
+}
+
+// Replaces reports whether an incoming entry should replace an existing one,
+// given whether each side is approved
+func (p ConflictPolicy) Replaces(existingApproved, incomingApproved bool) bool {
+	switch p {
+	case ConflictKeep:
+		return false
+	case ConflictOverwrite:
+		return true
+	default:
+		return incomingApproved || !existingApproved
+	}
+}
+
+// ImportResult counts what an import did
+type ImportResult struct {
+	Added     int
+	Updated   int
+	Skipped   int      // Unchanged entries and conflicts resolved in favour of the existing entry
+	Conflicts []string // Entries whose translation differed, whichever side won
+}
+
+// Columns maps the names of a CSV/TSV header row to their index. Names are
+// matched case-insensitively without a byte order mark, aliases are renamed
+// to the column they fill, and the first of duplicated columns wins.
+func Columns(header []string, aliases map[string]string) map[string]int {
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		if _, dup := col[name]; !dup {
+			col[name] = i
+		}
+	}
+	return col
+}
@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseFormat tests format names, aliases and the supported set
+func TestParseFormat(t *testing.T) {
+	supported := []Format{FormatCSV, FormatTSV, FormatTBX}
+	for input, want := range map[string]Format{"csv": FormatCSV, ".TSV": FormatTSV, "txt": FormatTSV, "xml": FormatTBX} {
+		if got, err := ParseFormat(input, supported...); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("tmx", supported...); err == nil {
+		t.Error("expected error for an unsupported format")
+	}
+
+	if got, err := Resolve("memory.csv", "tmx", FormatTMX, FormatCSV); err != nil || got != FormatTMX {
+		t.Errorf("Resolve = %q, %v; want the explicit format", got, err)
+	}
+}
+
+// TestParseConflictPolicy tests conflict policy parsing
+func TestParseConflictPolicy(t *testing.T) {
+	for input, want := range map[string]ConflictPolicy{
+		"":                ConflictPreferApproved,
+		"keep":            ConflictKeep,
+		"OVERWRITE":       ConflictOverwrite,
+		"prefer_approved": ConflictPreferApproved,
+	} {
+		got, err := ParseConflictPolicy(input)
+		if err != nil || got != want {
+			t.Errorf("ParseConflictPolicy(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := ParseConflictPolicy("merge"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+// TestConflictPolicyNext tests cycling through the conflict policies
+func TestConflictPolicyNext(t *testing.T) {
+	tests := map[ConflictPolicy]ConflictPolicy{
+		ConflictPreferApproved: ConflictKeep,
+		ConflictKeep:           ConflictOverwrite,
+		ConflictOverwrite:      ConflictPreferApproved,
+		"":                     ConflictPreferApproved,
+	}
+
+	for current, want := range tests {
+		if got := current.Next(); got != want {
+			t.Errorf("%q.Next() = %q, want %q", current, got, want)
+		}
+	}
+}
+
+// TestReplaces tests which side of a conflict each policy keeps
+func TestReplaces(t *testing.T) {
+	tests := []struct {
+		policy             ConflictPolicy
+		existing, incoming bool
+		want               bool
+	}{
+		{ConflictKeep, false, true, false},
+		{ConflictOverwrite, true, false, true},
+		{ConflictPreferApproved, true, false, false},
+		{ConflictPreferApproved, false, true, true},
+		{ConflictPreferApproved, false, false, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.Replaces(tt.existing, tt.incoming); got != tt.want {
+			t.Errorf("%q.Replaces(%v, %v) = %v, want %v", tt.policy, tt.existing, tt.incoming, got, tt.want)
+		}
+	}
+}
+
+// TestColumns tests header parsing
+func TestColumns(t *testing.T) {
+	got := Columns([]string{"\uFEFFSource", " Target ", "term"}, map[string]string{"term": "source"})
+	want := map[string]int{"source": 0, "target": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns = %v, want %v", got, want)
+	}
+}
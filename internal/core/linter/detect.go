@@ -0,0 +1,99 @@
+package linter
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// AutoLang is the source language of jobs that leave detection to the linter
+const AutoLang = "auto"
+
+// fallbackSourceLang is assumed when the source language cannot be detected
+const fallbackSourceLang = "en"
+
+// minDetectHits is the number of profile words needed to detect a Latin-script language
+const minDetectHits = 3
+
+// DetectLanguage guesses the language of texts: from the script for Japanese,
+// Korean, Chinese and Russian, otherwise from the word profile with the most
+// hits. It returns "" when there is not enough evidence.
+func DetectLanguage(texts []string) string {
+	var kana, han, hangul, cyrillic, latin int
+	var tokens []string
+	for _, text := range texts {
+		text = tagBlockPattern.ReplaceAllString(text, "")
+		text = lineBreakPattern.ReplaceAllString(text, " ")
+		for _, r := range text {
+			switch {
+			case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+				kana++
+			case unicode.Is(unicode.Han, r):
+				han++
+			case unicode.Is(unicode.Hangul, r):
+				hangul++
+			case unicode.Is(unicode.Cyrillic, r):
+				cyrillic++
+			case unicode.Is(unicode.Latin, r):
+				latin++
+			}
+		}
+		tokens = append(tokens, residueTokens(strings.ToLower(text))...)
+	}
+
+	// Kana only appears in Japanese, and Japanese lines mix it with kanji
+	switch {
+	case kana > 0 && kana+han >= latin:
+		return "ja"
+	case hangul > 0 && hangul >= latin:
+		return "ko"
+	case han > 0 && han >= latin:
+		return "zh"
+	case cyrillic > 0 && cyrillic >= latin:
+		return "ru"
+	}
+
+	profileFor("") // Load the embedded profiles
+	langs := make([]string, 0, len(profiles))
+	for lang := range profiles {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	best, bestHits := "", 0
+	for _, lang := range langs {
+		hits := 0
+		for i := range tokens {
+			if profiles[lang].match(tokens, i) != "" {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = lang, hits
+		}
+	}
+	if bestHits < minDetectHits {
+		return ""
+	}
+	return best
+}
+
+// resolveSourceLang returns the source language to lint with: lang itself,
+// or for "auto" and "" the language detected from source, falling back to
+// English, which every translation was checked against before detection
+func resolveSourceLang(lang string, source []parser.SubtitleLine) string {
+	if lang != "" && !strings.EqualFold(lang, AutoLang) {
+		return lang
+	}
+
+	texts := make([]string, len(source))
+	for i, line := range source {
+		texts[i] = line.Text
+	}
+	if detected := DetectLanguage(texts); detected != "" {
+		return detected
+	}
+	return fallbackSourceLang
+}
@@ -0,0 +1,312 @@
+package linter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// Term is a glossary entry enforced after translation
+type Term struct {
+	Source    string   // Term as written in the original
+	Target    string   // Required rendering; "" keeps the source form
+	Keep      bool     // Do not translate: the source form must be kept
+	Force     bool     // The target rendering must appear whenever the original has the term
+	Forbidden []string // Renderings that must never be used
+	Inflect   bool     // The translation may inflect the rendering (plural, gender)
+}
+
+// Want returns the rendering the translation must use
+func (t Term) Want() string {
+	if t.Keep || t.Target == "" {
+		return t.Source
+	}
+	return t.Target
+}
+
+// Required reports whether the rendering must appear when the original has the term
+func (t Term) Required() bool {
+	return t.Keep || t.Force
+}
+
+// replaceable returns the renderings a fix replaces with the wanted one: the
+// forbidden ones and, for forced translations of a term of the original, the
+// untranslated source form
+func (t Term) replaceable(source *parser.SubtitleLine) []string {
+	var forms []string
+	for _, f := range t.Forbidden {
+		if strings.TrimSpace(f) != "" && !sameTerm(f, t.Want()) {
+			forms = append(forms, f)
+		}
+	}
+	if t.Force && !t.Keep && !sameTerm(t.Source, t.Want()) && (source == nil || containsTerm(source.Text, t.Source, false)) {
+		forms = append(forms, t.Source)
+	}
+	return forms
+}
+
+// misuses returns where the translation text uses form outside the wanted
+// rendering, so "Naruto" is not flagged inside a required "Naruto Uzumaki".
+// The untranslated source form always matches exactly.
+func (t Term) misuses(text, form string) [][2]int {
+	wanted := findTerm(text, t.Want(), t.Inflect)
+	var spans [][2]int
+	for _, s := range findTerm(text, form, t.Inflect && !sameTerm(form, t.Source)) {
+		if !slices.ContainsFunc(wanted, func(w [2]int) bool { return s[0] < w[1] && w[0] < s[1] }) {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+// termWord is a word of a text with its byte offsets
+type termWord struct {
+	start, end int
+	folded     string
+}
+
+// diacritics folds accented Latin letters to their base letter
+var diacritics = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "ā", "a",
+	"ç", "c",
+	"è", "e", "é", "e", "ê", "e", "ë", "e", "ē", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ī", "i",
+	"ñ", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ō", "o",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ū", "u",
+	"ý", "y", "ÿ", "y",
+)
+
+// foldTerm lowercases a word and removes its diacritics
+func foldTerm(s string) string {
+	return diacritics.Replace(strings.ToLower(s))
+}
+
+// sameTerm reports whether two renderings are equal ignoring case and diacritics
+func sameTerm(a, b string) bool {
+	return foldTerm(strings.TrimSpace(a)) == foldTerm(strings.TrimSpace(b))
+}
+
+// termWords splits a text into words, skipping ASS tag blocks and line breaks
+func termWords(text string) []termWord {
+	var words []termWord
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			words = append(words, termWord{start: start, end: end, folded: foldTerm(text[start:end])})
+			start = -1
+		}
+	}
+
+	for i := 0; i < len(text); {
+		if text[i] == '{' {
+			if end := strings.IndexByte(text[i:], '}'); end > 0 {
+				flush(i)
+				i += end + 1
+				continue
+			}
+		}
+		if text[i] == '\\' && i+1 < len(text) && strings.IndexByte("Nnh", text[i+1]) >= 0 {
+			flush(i)
+			i += 2
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) {
+			if start < 0 {
+				start = i
+			}
+		} else {
+			flush(i)
+		}
+		i += size
+	}
+	flush(len(text))
+	return words
+}
+
+// spaceless reports whether a term is written in a script without spaces,
+// where it can only be matched as a substring
+func spaceless(term string) bool {
+	return strings.ContainsFunc(term, func(r rune) bool {
+		return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai)
+	})
+}
+
+// inflectionEndings are the endings an inflected rendering may add to its
+// stem: plural and gender forms (companheiro, companheiros, companheira)
+var inflectionEndings = []string{"", "s", "es", "a", "as", "o", "os", "e", "en", "i"}
+
+// inflectedWord reports whether word is term or an inflection of it. Only
+// terms of four letters or more inflect: the term itself, or the term
+// without its final vowel, must be followed by one of inflectionEndings.
+func inflectedWord(word, term string) bool {
+	if word == term {
+		return true
+	}
+	if utf8.RuneCountInString(term) < 4 {
+		return false
+	}
+
+	stems := []string{term}
+	if last, size := utf8.DecodeLastRuneInString(term); strings.ContainsRune("aeiou", last) {
+		stems = append(stems, term[:len(term)-size])
+	}
+	for _, stem := range stems {
+		if ending, ok := strings.CutPrefix(word, stem); ok && slices.Contains(inflectionEndings, ending) {
+			return true
+		}
+	}
+	return false
+}
+
+// findTerm returns the byte ranges of the occurrences of term in text,
+// ignoring case and diacritics. Words must match exactly unless inflect
+// allows the inflected endings of the rendering.
+func findTerm(text, term string, inflect bool) [][2]int {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil
+	}
+
+	var spans [][2]int
+	if spaceless(term) {
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], term)
+			if i < 0 {
+				return spans
+			}
+			spans = append(spans, [2]int{offset + i, offset + i + len(term)})
+			offset += i + len(term)
+		}
+	}
+
+	want := termWords(term)
+	if len(want) == 0 {
+		return nil
+	}
+	words := termWords(text)
+	for i := 0; i+len(want) <= len(words); i++ {
+		match := true
+		for j, w := range want {
+			word := words[i+j].folded
+			if word != w.folded && (!inflect || !inflectedWord(word, w.folded)) {
+				match = false
+				break
+			}
+		}
+		if match {
+			spans = append(spans, [2]int{words[i].start, words[i+len(want)-1].end})
+			i += len(want) - 1
+		}
+	}
+	return spans
+}
+
+// containsTerm reports whether text uses term (or, with inflect, an inflection of it)
+func containsTerm(text, term string, inflect bool) bool {
+	return len(findTerm(text, term, inflect)) > 0
+}
+
+// matchCase renders want in the case of the text it replaces: all caps or
+// capitalized
+func matchCase(found, want string) string {
+	hasLetters := strings.ContainsFunc(found, unicode.IsLetter)
+	if hasLetters && utf8.RuneCountInString(found) > 1 && found == strings.ToUpper(found) && found != strings.ToLower(found) {
+		return strings.ToUpper(want)
+	}
+	first, _ := utf8.DecodeRuneInString(found)
+	wantFirst, size := utf8.DecodeRuneInString(want)
+	if unicode.IsUpper(first) && unicode.IsLower(wantFirst) {
+		return string(unicode.ToUpper(wantFirst)) + want[size:]
+	}
+	return want
+}
+
+// replaceSpans replaces the given ranges of text with want, keeping their case
+func replaceSpans(text string, spans [][2]int, want string) string {
+	for i := len(spans) - 1; i >= 0; i-- {
+		s := spans[i]
+		text = text[:s[0]] + matchCase(text[s[0]:s[1]], want) + text[s[1]:]
+	}
+	return text
+}
+
+// checkGlossaryTerms detects forbidden renderings and enforced terms of the
+// original missing from the translation
+func checkGlossaryTerms(ctx RuleContext) *Issue {
+	for _, term := range ctx.Check.Terms {
+		for _, f := range term.replaceable(ctx.Source) {
+			if len(term.misuses(ctx.Text, f)) == 0 {
+				continue
+			}
+			return &Issue{
+				Content:     truncate(ctx.Text, 50),
+				Suggestion:  fmt.Sprintf("Use '%s' instead of '%s' for '%s'", term.Want(), f, term.Source),
+				AutoFixable: true,
+			}
+		}
+
+		if !term.Required() || ctx.Source == nil || !containsTerm(ctx.Source.Text, term.Source, false) || containsTerm(ctx.Text, term.Want(), term.Inflect) {
+			continue
+		}
+		return &Issue{
+			Content:    truncate(ctx.Text, 50),
+			Suggestion: fmt.Sprintf("Expected '%s' for '%s'", term.Want(), term.Source),
+		}
+	}
+	return nil
+}
+
+// fixGlossaryTerms replaces forbidden renderings and untranslated forced
+// terms with the required rendering
+func fixGlossaryTerms(ctx RuleContext) string {
+	text := ctx.Text
+	for _, term := range ctx.Check.Terms {
+		for _, f := range term.replaceable(ctx.Source) {
+			text = replaceSpans(text, term.misuses(text, f), term.Want())
+		}
+	}
+	return text
+}
+
+// termPlaceholder marks a protected term in a request; %d numbers it within the line
+const termPlaceholder = "⟦%d⟧"
+
+// ProtectTerms replaces the required terms of a source text with numbered
+// placeholders. It returns the protected text and the rendering of each
+// placeholder, to be put back by RestoreTerms.
+func ProtectTerms(text string, terms []Term) (string, []string) {
+	// Longer terms first, so "Konoha Village" is protected before "Konoha"
+	terms = slices.Clone(terms)
+	slices.SortStableFunc(terms, func(a, b Term) int { return len(b.Source) - len(a.Source) })
+
+	var renderings []string
+	for _, term := range terms {
+		if !term.Required() {
+			continue
+		}
+		spans := findTerm(text, term.Source, false)
+		placeholders := make([]string, len(spans))
+		for i, s := range spans {
+			placeholders[i] = fmt.Sprintf(termPlaceholder, len(renderings))
+			renderings = append(renderings, matchCase(text[s[0]:s[1]], term.Want()))
+		}
+		for i := len(spans) - 1; i >= 0; i-- {
+			text = text[:spans[i][0]] + placeholders[i] + text[spans[i][1]:]
+		}
+	}
+	return text, renderings
+}
+
+// RestoreTerms puts the renderings of ProtectTerms back in a translation
+func RestoreTerms(text string, renderings []string) string {
+	for i, rendering := range renderings {
+		text = strings.ReplaceAll(text, fmt.Sprintf(termPlaceholder, i), rendering)
+	}
+	return text
+}
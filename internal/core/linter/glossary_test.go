@@ -0,0 +1,124 @@
+package linter
+
+import (
+	"testing"
+)
+
+// checkTerms lints one translated line against its original with enforced terms
+func checkTerms(source, text string, terms []Term) []Issue {
+	opts := CheckOptions{Terms: terms, Source: TextLines([]string{source}), Only: []string{RuleGlossaryTerms}}
+	return Check(TextLines([]string{text}), opts).Issues
+}
+
+// TestFindTerm tests case and diacritic tolerant matching, with inflections
+// only where allowed
+func TestFindTerm(t *testing.T) {
+	tests := []struct {
+		text, term string
+		inflect    bool
+		want       bool
+	}{
+		{"Back to KONOHA!", "Konoha", false, true},
+		{"Konoha's gate", "Konoha", false, true},
+		{"Naruto-kun!", "Naruto", false, true},
+		{"A Vila da Folha", "vila da folha", false, true},
+		{"Tōkyō at night", "Tokyo", false, true},
+		{`{\i1}Konoha{\i0}`, "Konoha", false, true},
+		{`Go\NKonoha`, "Konoha", false, true},
+		{"木ノ葉に帰る", "木ノ葉", false, true},
+		{"Meus companheiros!", "companheiro", true, true},
+		{"Minha companheira.", "companheiro", true, true},
+		{"As vilas da folha", "vila da folha", true, true},
+		// Inflections only match when allowed
+		{"Meus companheiros!", "companheiro", false, false},
+		// Whole words only, and only inflectional endings
+		{"Konohamaru", "Konoha", true, false},
+		{"Kirito", "Kira", true, false},
+		{"Kirito", "Kira", false, false},
+		{"Acompanheiro", "companheiro", true, false},
+		{"Kais", "Kai", true, false},
+	}
+
+	for _, tt := range tests {
+		if got := containsTerm(tt.text, tt.term, tt.inflect); got != tt.want {
+			t.Errorf("containsTerm(%q, %q, %v) = %v, want %v", tt.text, tt.term, tt.inflect, got, tt.want)
+		}
+	}
+}
+
+// TestGlossaryTerms tests the enforcement flags and their fixes
+func TestGlossaryTerms(t *testing.T) {
+	forced := Term{Source: "Konoha", Target: "Vila da Folha", Force: true}
+	kept := Term{Source: "Konoha", Target: "Vila da Folha", Keep: true, Forbidden: []string{"Vila da Folha", "Aldeia da Folha"}}
+	inflected := kept
+	inflected.Inflect = true
+	kira := Term{Source: "Kira", Keep: true}
+
+	tests := []struct {
+		name, source, text string
+		term               Term
+		issue, fixable     bool
+		fixed              string
+	}{
+		{"forced translation used", "Back to Konoha!", "De volta à Vila da Folha!", forced, false, false, ""},
+		{"forced term left untranslated", "Back to Konoha!", "De volta a KONOHA!", forced, true, true, "De volta a VILA DA FOLHA!"},
+		{"forced term missing", "Back to Konoha!", "De volta para casa!", forced, true, false, ""},
+		{"term not in the original", "Back home!", "De volta para casa!", forced, false, false, ""},
+		{"kept term", "Back to Konoha!", "De volta a Konoha!", kept, false, false, ""},
+		{"forbidden rendering", "Back to Konoha!", "De volta à Aldeia da Folha!", kept, true, true, "De volta à Konoha!"},
+		{"forbidden inflection", "Leaf villages", "vilas da folha", inflected, true, true, "Konoha"},
+		{"inflection not allowed", "Leaf villages", "vilas da folha", kept, false, false, ""},
+		{"other name sharing a prefix", "Kirito left.", "Kirito saiu.", kira, false, false, ""},
+		{"kept name missing", "Kira left.", "Kirito saiu.", kira, true, false, ""},
+		{"target containing the source", "Naruto!", "Naruto Uzumaki!", Term{Source: "Naruto", Target: "Naruto Uzumaki", Force: true}, false, false, ""},
+	}
+
+	for _, tt := range tests {
+		issues := checkTerms(tt.source, tt.text, []Term{tt.term})
+		if (len(issues) > 0) != tt.issue {
+			t.Errorf("%s: got issues %+v", tt.name, issues)
+			continue
+		}
+		if !tt.issue {
+			continue
+		}
+		if issues[0].AutoFixable != tt.fixable || issues[0].Severity != SeverityHigh {
+			t.Errorf("%s: fixable = %v, severity %s", tt.name, issues[0].AutoFixable, issues[0].Severity)
+		}
+		if tt.fixable {
+			if got := AutoFix(TextLines([]string{tt.text}), issues)[0].Text; got != tt.fixed {
+				t.Errorf("%s: fixed to %q, want %q", tt.name, got, tt.fixed)
+			}
+		}
+	}
+}
+
+// TestProtectTermsExactNames tests that names sharing a prefix are left alone
+func TestProtectTermsExactNames(t *testing.T) {
+	terms := []Term{{Source: "Kira", Keep: true}, {Source: "Hana", Keep: true}}
+
+	protected, renderings := ProtectTerms("Kirito and Hanako met Kira.", terms)
+	if protected != "Kirito and Hanako met ⟦0⟧." {
+		t.Fatalf("got %q", protected)
+	}
+	if got := RestoreTerms(protected, renderings); got != "Kirito and Hanako met Kira." {
+		t.Errorf("got %q", got)
+	}
+}
+
+// TestProtectTerms tests placeholders around required terms
+func TestProtectTerms(t *testing.T) {
+	terms := []Term{
+		{Source: "Konoha", Target: "Vila da Folha", Force: true},
+		{Source: "Konoha Village", Keep: true},
+		{Source: "Naruto"},
+	}
+
+	protected, renderings := ProtectTerms("NARUTO left Konoha for KONOHA VILLAGE.", terms)
+	if protected != "NARUTO left ⟦1⟧ for ⟦0⟧." {
+		t.Fatalf("got %q", protected)
+	}
+	if got := RestoreTerms("NARUTO saiu da ⟦1⟧ para ⟦0⟧.", renderings); got != "NARUTO saiu da Vila da Folha para KONOHA VILLAGE." {
+		t.Errorf("got %q", got)
+	}
+}
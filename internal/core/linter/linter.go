@@ -3,7 +3,10 @@ package linter
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/core/parser"
 )
 
 type Severity string
@@ -16,11 +19,14 @@ const (
 
 type Issue struct {
 	LineID      int
+	RuleID      string
 	Severity    Severity
 	IssueType   string
 	Content     string
 	Suggestion  string
 	AutoFixable bool
+
+	fixCtx RuleContext // Context the rule ran with, reused by AutoFix
 }
 
 type Result struct {
@@ -30,50 +36,94 @@ type Result struct {
 
 // CheckOptions configures the linter behavior
 type CheckOptions struct {
-	SourceLang string            // Source language ISO code
-	TargetLang string            // Target language ISO code
-	Glossary   map[string]string // Project glossary for mismatch detection
+	SourceLang string                // Source language ISO code
+	TargetLang string                // Target language ISO code, selects reading-speed and line-length defaults
+	Format     string                // Subtitle format ("ass" or "srt"), selects the line break inserted by fixes
+	Glossary   map[string]string     // Project glossary for mismatch detection
+	Terms      []Term                // Glossary terms enforced by the glossary-terms rule
+	Source     []parser.SubtitleLine // Original lines, by position; enables the parity rules
+	Rules      map[string]RuleConfig // Per-rule overrides by rule ID
+	Only       []string              // Restricts the run to these rule IDs when set
+
+	residueLang string // SourceLang with "auto" resolved, see resolveSourceLang
+}
+
+// activeRule is a registered rule with its configuration resolved
+type activeRule struct {
+	rule     *Rule
+	severity Severity
+	options  RuleOptions
+}
+
+// activeRules resolves the configured severity and options of every enabled rule
+func activeRules(overrides map[string]RuleConfig, only []string) []activeRule {
+	rules := make([]activeRule, 0, len(registry))
+	for _, r := range registry {
+		if len(only) > 0 && !slices.Contains(only, r.ID) {
+			continue
+		}
+		active := activeRule{rule: r, severity: r.Severity, options: r.Options}
+		if cfg, ok := overrides[r.ID]; ok {
+			if cfg.Severity == SeverityOff {
+				continue
+			}
+			if cfg.Severity != "" {
+				active.severity = cfg.Severity
+			}
+			if len(cfg.Options) > 0 {
+				active.options = r.Options.merge(cfg.Options)
+			}
+		}
+		rules = append(rules, active)
+	}
+	return rules
 }
 
-// Check runs all quality checks on translated subtitle lines
-func Check(lines []string, opts CheckOptions) Result {
+// Check runs all enabled rules on translated subtitle lines. Issues refer to
+// lines by position (1-based). Timing rules skip lines without valid timestamps.
+// A line can opt out with an inline {lint-disable} or {lint-disable: rule-id, ...} comment.
+func Check(lines []parser.SubtitleLine, opts CheckOptions) Result {
 	result := Result{
 		Issues:    []Issue{},
 		PassedAll: true,
 	}
 
-	for i, line := range lines {
-		// Check 1: ASS tag validation
-		if tagIssue := checkASSTags(i+1, line); tagIssue != nil {
-			result.Issues = append(result.Issues, *tagIssue)
-			result.PassedAll = false
-		}
+	opts.residueLang = resolveSourceLang(opts.SourceLang, opts.Source)
 
-		// Check 2: Bracket matching
-		if bracketIssue := checkBrackets(i+1, line); bracketIssue != nil {
-			result.Issues = append(result.Issues, *bracketIssue)
-			result.PassedAll = false
+	rules := activeRules(opts.Rules, opts.Only)
+	for i, line := range lines {
+		all, suppressed := suppressions(line.Text)
+		if all {
+			continue
 		}
 
-		// Check 3: Source language residue (English detection)
-		if opts.SourceLang != "" && opts.SourceLang != opts.TargetLang {
-			if residueIssue := checkSourceResidue(i+1, line, opts.SourceLang); residueIssue != nil {
-				result.Issues = append(result.Issues, *residueIssue)
-				result.PassedAll = false
+		for _, active := range rules {
+			if suppressed[active.rule.ID] {
+				continue
+			}
+			ctx := RuleContext{LineID: i + 1, Line: line, Text: line.Text, Options: active.options, Check: opts}
+			if i < len(opts.Source) {
+				ctx.Source = &opts.Source[i]
+			}
+			if active.rule.NeedsSource && ctx.Source == nil {
+				continue
+			}
+			issue := active.rule.Check(ctx)
+			if issue == nil {
+				continue
 			}
-		}
 
-		// Check 4: Excessive punctuation
-		if punctIssue := checkPunctuation(i+1, line); punctIssue != nil {
-			result.Issues = append(result.Issues, *punctIssue)
-			result.PassedAll = false
-		}
+			issue.LineID = i + 1
+			issue.RuleID = active.rule.ID
+			issue.Severity = active.severity
+			issue.IssueType = active.rule.IssueType
+			issue.AutoFixable = issue.AutoFixable && active.rule.Fix != nil
+			issue.fixCtx = ctx
+			result.Issues = append(result.Issues, *issue)
 
-		// Check 5: Glossary mismatch
-		if len(opts.Glossary) > 0 {
-			if glossaryIssue := checkGlossaryMismatch(i+1, line, opts.Glossary); glossaryIssue != nil {
-				result.Issues = append(result.Issues, *glossaryIssue)
-				// Glossary mismatches are warnings, not failures
+			// Advisory rules (glossary) warn unless raised to HIGH
+			if !active.rule.Advisory || active.severity == SeverityHigh {
+				result.PassedAll = false
 			}
 		}
 	}
@@ -81,14 +131,23 @@ func Check(lines []string, opts CheckOptions) Result {
 	return result
 }
 
-// CheckSimple runs quality checks with minimal options (backwards compatibility)
+// CheckSimple runs the text checks on plain lines (backwards compatibility)
 func CheckSimple(lines []string, sourceLang string) Result {
-	return Check(lines, CheckOptions{SourceLang: sourceLang})
+	return Check(TextLines(lines), CheckOptions{SourceLang: sourceLang})
 }
 
-// AutoFix attempts to fix all auto-fixable issues
-func AutoFix(lines []string, issues []Issue) []string {
-	fixed := make([]string, len(lines))
+// TextLines wraps plain texts as untimed subtitle lines
+func TextLines(texts []string) []parser.SubtitleLine {
+	lines := make([]parser.SubtitleLine, len(texts))
+	for i, text := range texts {
+		lines[i] = parser.SubtitleLine{Index: i + 1, Text: text}
+	}
+	return lines
+}
+
+// AutoFix attempts to fix all auto-fixable issues, returning fixed copies of the lines
+func AutoFix(lines []parser.SubtitleLine, issues []Issue) []parser.SubtitleLine {
+	fixed := make([]parser.SubtitleLine, len(lines))
 	copy(fixed, lines)
 
 	for _, issue := range issues {
@@ -101,36 +160,51 @@ func AutoFix(lines []string, issues []Issue) []string {
 			continue
 		}
 
-		switch issue.IssueType {
-		case "Broken ASS Tags":
-			fixed[idx] = fixASSTags(fixed[idx])
-		case "Bracket Mismatch":
-			fixed[idx] = fixBrackets(fixed[idx])
-		case "Excessive Punctuation":
-			fixed[idx] = fixPunctuation(fixed[idx])
+		rule := ruleForIssue(issue)
+		if rule == nil || rule.Fix == nil {
+			continue
 		}
+		// Fixes build on each other, so each one sees the current text
+		ctx := issue.fixCtx
+		if ctx.Options == nil {
+			ctx.Options = rule.Options
+		}
+		ctx.LineID = issue.LineID
+		ctx.Line = fixed[idx]
+		ctx.Text = fixed[idx].Text
+		fixed[idx].Text = rule.Fix(ctx)
 	}
 
 	return fixed
 }
 
+// ruleForIssue finds the rule that raised an issue, by ID or by issue type
+func ruleForIssue(issue Issue) *Rule {
+	if r, ok := registryIDs[issue.RuleID]; ok {
+		return r
+	}
+	for _, r := range registry {
+		if r.IssueType == issue.IssueType {
+			return r
+		}
+	}
+	return nil
+}
+
+// unclosedTagPattern matches an override block missing its closing brace
+var unclosedTagPattern = regexp.MustCompile(`\{[^{}]*(?:\{|$)`)
+
 // checkASSTags validates ASS subtitle tags
 func checkASSTags(lineID int, text string) *Issue {
-	// Pattern: {\tag} or {\tag...}
-	tagPattern := regexp.MustCompile(`\{[^}]*`)
-
-	// Find unclosed tags
-	if tagPattern.MatchString(text) {
-		match := tagPattern.FindString(text)
-		if !strings.Contains(match, "}") {
-			return &Issue{
-				LineID:      lineID,
-				Severity:    SeverityHigh,
-				IssueType:   "Broken ASS Tags",
-				Content:     truncate(text, 50),
-				Suggestion:  "Add closing '}' to ASS tags",
-				AutoFixable: true,
-			}
+	// A '{' not closed before the next '{' or the end of the line
+	if unclosedTagPattern.MatchString(text) {
+		return &Issue{
+			LineID:      lineID,
+			Severity:    SeverityHigh,
+			IssueType:   "Broken ASS Tags",
+			Content:     truncate(text, 50),
+			Suggestion:  "Add closing '}' to ASS tags",
+			AutoFixable: true,
 		}
 	}
 
@@ -178,37 +252,35 @@ func checkBrackets(lineID int, text string) *Issue {
 	return nil
 }
 
-// checkSourceResidue detects English words in non-English translations
-func checkSourceResidue(lineID int, text string, targetLang string) *Issue {
-	// Simple English word detector (common words)
-	englishWords := []string{
-		"the", "is", "are", "was", "were", "have", "has", "had",
-		"hello", "goodbye", "yes", "no", "what", "where", "when",
-	}
-
-	lowerText := strings.ToLower(text)
-	for _, word := range englishWords {
-		pattern := regexp.MustCompile(`\b` + word + `\b`)
-		if pattern.MatchString(lowerText) {
-			return &Issue{
-				LineID:      lineID,
-				Severity:    SeverityMedium,
-				IssueType:   "English Residual",
-				Content:     truncate(text, 50),
-				Suggestion:  fmt.Sprintf("English word detected: '%s'", word),
-				AutoFixable: false,
-			}
+// excessivePunctuation returns the runs of more than maxRepeat punctuation
+// marks in text; a three-period ellipsis is not one
+func excessivePunctuation(text string, maxRepeat int) [][]int {
+	var runs [][]int
+	for start := 0; start < len(text); {
+		if !isRepeatedPunct(text[start]) {
+			start++
+			continue
 		}
+		end := start + 1
+		for end < len(text) && isRepeatedPunct(text[end]) {
+			end++
+		}
+		if end-start > maxRepeat && text[start:end] != "..." {
+			runs = append(runs, []int{start, end})
+		}
+		start = end
 	}
+	return runs
+}
 
-	return nil
+// isRepeatedPunct reports whether b is a mark counted by excessivePunctuation
+func isRepeatedPunct(b byte) bool {
+	return b == '!' || b == '?' || b == '.'
 }
 
-// checkPunctuation detects excessive punctuation
-func checkPunctuation(lineID int, text string) *Issue {
-	// Check for 3+ consecutive punctuation marks
-	pattern := regexp.MustCompile(`[!?.]{3,}`)
-	if pattern.MatchString(text) {
+// checkPunctuation detects more than maxRepeat consecutive punctuation marks
+func checkPunctuation(lineID int, text string, maxRepeat int) *Issue {
+	if len(excessivePunctuation(text, maxRepeat)) > 0 {
 		return &Issue{
 			LineID:      lineID,
 			Severity:    SeverityLow,
@@ -247,11 +319,13 @@ func checkGlossaryMismatch(lineID int, translatedText string, glossary map[strin
 	return nil
 }
 
+// trailingTagPattern matches an ASS tag left open at the end of a line
+var trailingTagPattern = regexp.MustCompile(`(\{[^}]*)$`)
+
 // fixASSTags attempts to close unclosed ASS tags
 func fixASSTags(text string) string {
 	// Add closing brace to unclosed tags
-	pattern := regexp.MustCompile(`(\{[^}]*)$`)
-	return pattern.ReplaceAllString(text, "$1}")
+	return trailingTagPattern.ReplaceAllString(text, "$1}")
 }
 
 // fixBrackets attempts to balance brackets
@@ -279,11 +353,14 @@ func fixBrackets(text string) string {
 	return string(result)
 }
 
-// fixPunctuation reduces excessive punctuation
-func fixPunctuation(text string) string {
-	// Replace 3+ consecutive punctuation with 2
-	pattern := regexp.MustCompile(`([!?.]){3,}`)
-	return pattern.ReplaceAllString(text, "$1$1")
+// fixPunctuation reduces runs of punctuation to maxRepeat of the last mark
+func fixPunctuation(text string, maxRepeat int) string {
+	runs := excessivePunctuation(text, maxRepeat)
+	for i := len(runs) - 1; i >= 0; i-- {
+		start, end := runs[i][0], runs[i][1]
+		text = text[:start] + strings.Repeat(text[end-1:end], maxRepeat) + text[end:]
+	}
+	return text
 }
 
 // truncate limits text length for display
@@ -1,6 +1,7 @@
 package linter
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -109,7 +110,7 @@ func TestCheckWithASSTags(t *testing.T) {
 		`Normal line`,
 	}
 
-	result := Check(lines, CheckOptions{})
+	result := Check(TextLines(lines), CheckOptions{})
 
 	// Should pass - ASS tags are valid
 	if len(result.Issues) > 0 {
@@ -140,7 +141,7 @@ func TestCheckWithBrackets(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := Check(tt.lines, CheckOptions{})
+			result := Check(TextLines(tt.lines), CheckOptions{})
 			if tt.shouldPass && !result.PassedAll {
 				t.Errorf("Expected PassedAll=true for %q", tt.name)
 			}
@@ -157,7 +158,7 @@ func TestCheckWithExcessivePunctuation(t *testing.T) {
 		"What???????????????????",
 	}
 
-	result := Check(lines, CheckOptions{})
+	result := Check(TextLines(lines), CheckOptions{})
 
 	// Should detect excessive punctuation
 	hasIssue := len(result.Issues) > 0
@@ -166,6 +167,27 @@ func TestCheckWithExcessivePunctuation(t *testing.T) {
 	}
 }
 
+// TestExcessivePunctuation tests the byte ranges of repeated punctuation runs
+func TestExcessivePunctuation(t *testing.T) {
+	tests := []struct {
+		text      string
+		maxRepeat int
+		want      [][]int
+	}{
+		{"Wait... what?!", 3, nil},
+		{"Wait... what?!", 2, nil},
+		{"No!!!! Stop?!?!", 3, [][]int{{2, 6}, {11, 15}}},
+		{"Hmm....", 3, [][]int{{3, 7}}},
+		{"Ah...", 2, nil},
+		{"Ah..", 1, [][]int{{2, 4}}},
+	}
+	for _, tt := range tests {
+		if got := excessivePunctuation(tt.text, tt.maxRepeat); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("excessivePunctuation(%q, %d) = %v, want %v", tt.text, tt.maxRepeat, got, tt.want)
+		}
+	}
+}
+
 // TestCheckWithGlossary tests glossary mismatch detection
 func TestCheckWithGlossary(t *testing.T) {
 	lines := []string{
@@ -178,7 +200,7 @@ func TestCheckWithGlossary(t *testing.T) {
 		},
 	}
 
-	result := Check(lines, opts)
+	result := Check(TextLines(lines), opts)
 
 	// This tests that the function runs without error
 	// The specific behavior depends on implementation
@@ -200,7 +222,7 @@ func TestAutoFix(t *testing.T) {
 		},
 	}
 
-	fixed := AutoFix(lines, issues)
+	fixed := AutoFix(TextLines(lines), issues)
 
 	if len(fixed) != len(lines) {
 		t.Errorf("AutoFix returned %d lines, want %d", len(fixed), len(lines))
@@ -221,16 +243,16 @@ func TestAutoFixNoOp(t *testing.T) {
 		},
 	}
 
-	fixed := AutoFix(lines, issues)
+	fixed := AutoFix(TextLines(lines), issues)
 
-	if fixed[0] != lines[0] {
+	if fixed[0].Text != lines[0] {
 		t.Errorf("AutoFix modified line when AutoFixable=false")
 	}
 }
 
 // TestCheckEmptyLines tests Check with empty input
 func TestCheckEmptyLines(t *testing.T) {
-	result := Check([]string{}, CheckOptions{})
+	result := Check(nil, CheckOptions{})
 
 	if !result.PassedAll {
 		t.Error("Empty lines should pass")
@@ -240,3 +262,20 @@ func TestCheckEmptyLines(t *testing.T) {
 		t.Error("Empty lines should have no issues")
 	}
 }
+
+// TestCheckASSTagsClosed tests that only unclosed override blocks are flagged
+func TestCheckASSTagsClosed(t *testing.T) {
+	tests := map[string]bool{
+		`{\an8}Hello world`:  false,
+		`{\i1}Hi{\i0} there`: false,
+		`{\an8 Hello world`:  true,
+		`{\i1 Hi{\i0} there`: true,
+		`No tags (at all)`:   false,
+	}
+
+	for line, broken := range tests {
+		if got := checkASSTags(1, line) != nil; got != broken {
+			t.Errorf("checkASSTags(%q) flagged = %v, want %v", line, got, broken)
+		}
+	}
+}
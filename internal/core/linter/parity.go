@@ -0,0 +1,204 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// overrideTagPattern matches ASS override blocks; comments have no backslash
+	overrideTagPattern = regexp.MustCompile(`\{[^}]*\\[^}]*\}`)
+
+	// leadingTagsPattern matches the override blocks that open a line
+	leadingTagsPattern = regexp.MustCompile(`^(?:\{[^}]*\\[^}]*\})+`)
+
+	// Tokens that must survive a translation unchanged
+	urlPattern    = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s{}]+`)
+	timePattern   = regexp.MustCompile(`\b\d{1,2}:\d{2}(?::\d{2})?\b`)
+	numberPattern = regexp.MustCompile(`\d+(?:[.,]\d+)*`)
+
+	// Leftovers of the JSON payload or markdown formatting of a model reply
+	jsonArtifactPattern     = regexp.MustCompile(`^\s*[\[{]\s*"|"(?:id|text|translation)"\s*:`)
+	markdownArtifactPattern = regexp.MustCompile("```|\\*\\*[^*]+\\*\\*|^\\s*#{1,6} |^\\s*(?:Translation|Tradução|Traducción)\\s*:")
+	markdownFixPattern      = regexp.MustCompile("```[a-z]*|\\*\\*")
+)
+
+// overrideTags returns the override blocks of a line in order, whitespace removed
+func overrideTags(text string) []string {
+	blocks := overrideTagPattern.FindAllString(text, -1)
+	for i, b := range blocks {
+		blocks[i] = strings.Join(strings.Fields(b), "")
+	}
+	return blocks
+}
+
+// checkTagParity flags translations that dropped, added or reordered override tags
+func checkTagParity(ctx RuleContext) *Issue {
+	source := overrideTags(ctx.Source.Text)
+	target := overrideTags(ctx.Text)
+	if strings.Join(source, "") == strings.Join(target, "") {
+		return nil
+	}
+
+	// Leading tags (positioning, fades) are restored when the translation lost all of them
+	leading := leadingTagsPattern.FindString(ctx.Source.Text)
+	fixable := leading != "" && len(target) == 0 && len(overrideTags(leading)) == len(source)
+
+	return &Issue{
+		Content:     truncate(ctx.Text, 50),
+		Suggestion:  fmt.Sprintf("Override tags changed: source has %d, translation has %d", len(source), len(target)),
+		AutoFixable: fixable,
+	}
+}
+
+// fixTagParity restores the source's leading override tags
+func fixTagParity(ctx RuleContext) string {
+	if ctx.Source == nil || len(overrideTags(ctx.Text)) > 0 {
+		return ctx.Text
+	}
+	return leadingTagsPattern.FindString(ctx.Source.Text) + ctx.Text
+}
+
+// checkBreakParity flags translations with a different number of line breaks
+func checkBreakParity(ctx RuleContext) *Issue {
+	source := len(displayLines(ctx.Source.Text)) - 1
+	target := len(displayLines(ctx.Text)) - 1
+	if source == target {
+		return nil
+	}
+
+	return &Issue{
+		Content:    truncate(ctx.Text, 50),
+		Suggestion: fmt.Sprintf("Line breaks changed: source has %d, translation has %d", source, target),
+	}
+}
+
+// checkTokenParity flags URLs, times and numbers of the source missing from the translation
+func checkTokenParity(ctx RuleContext) *Issue {
+	source := tagBlockPattern.ReplaceAllString(ctx.Source.Text, "")
+	target := tagBlockPattern.ReplaceAllString(ctx.Text, "")
+
+	var missing []string
+	for _, url := range urlPattern.FindAllString(source, -1) {
+		if !strings.Contains(target, url) {
+			missing = append(missing, url)
+		}
+	}
+	source = urlPattern.ReplaceAllString(source, "")
+	target = urlPattern.ReplaceAllString(target, "")
+
+	for _, t := range timePattern.FindAllString(source, -1) {
+		if !strings.Contains(target, t) {
+			missing = append(missing, t)
+		}
+	}
+	source = timePattern.ReplaceAllString(source, "")
+	target = timePattern.ReplaceAllString(target, "")
+
+	// Digit separators differ between languages (1,000 vs 1.000), so only the digits are compared
+	targetNumbers := make(map[string]int)
+	for _, n := range numberPattern.FindAllString(target, -1) {
+		targetNumbers[digitsOnly(n)]++
+	}
+	for _, n := range numberPattern.FindAllString(source, -1) {
+		if targetNumbers[digitsOnly(n)] > 0 {
+			targetNumbers[digitsOnly(n)]--
+			continue
+		}
+		missing = append(missing, n)
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	return &Issue{
+		Content:    truncate(ctx.Text, 50),
+		Suggestion: "Missing from the translation: " + strings.Join(missing, ", "),
+	}
+}
+
+// digitsOnly strips the separators of a number
+func digitsOnly(n string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, n)
+}
+
+// plainText returns the visible text of a line, lowercased with breaks and extra spaces removed
+func plainText(text string) string {
+	text = tagBlockPattern.ReplaceAllString(text, "")
+	text = lineBreakPattern.ReplaceAllString(text, " ")
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// checkUntranslated flags translations identical to a source of at least minWords words
+func checkUntranslated(ctx RuleContext, minWords int) *Issue {
+	if ctx.Check.SourceLang != "" && strings.EqualFold(ctx.Check.SourceLang, ctx.Check.TargetLang) {
+		return nil
+	}
+
+	source := plainText(ctx.Source.Text)
+	if source != plainText(ctx.Text) || len(strings.Fields(source)) < minWords {
+		return nil
+	}
+	if !strings.ContainsFunc(source, unicode.IsLetter) {
+		return nil
+	}
+
+	return &Issue{
+		Content:    truncate(ctx.Text, 50),
+		Suggestion: "Line was left untranslated",
+	}
+}
+
+// checkLengthRatio flags translations much shorter or longer than sources of at least minChars
+func checkLengthRatio(ctx RuleContext, minRatio, maxRatio float64, minChars int) *Issue {
+	source := len([]rune(plainText(ctx.Source.Text)))
+	if source < minChars {
+		return nil
+	}
+
+	ratio := float64(len([]rune(plainText(ctx.Text)))) / float64(source)
+	if ratio >= minRatio && ratio <= maxRatio {
+		return nil
+	}
+
+	return &Issue{
+		Content:    truncate(ctx.Text, 50),
+		Suggestion: fmt.Sprintf("Translation is %.1fx the source length (expected %.1f-%.1fx)", ratio, minRatio, maxRatio),
+	}
+}
+
+// checkModelArtifacts flags JSON or markdown leftovers not present in the source
+func checkModelArtifacts(ctx RuleContext) *Issue {
+	source := ""
+	if ctx.Source != nil {
+		source = ctx.Source.Text
+	}
+
+	for _, pattern := range []*regexp.Regexp{jsonArtifactPattern, markdownArtifactPattern} {
+		match := pattern.FindString(ctx.Text)
+		if match == "" || pattern.MatchString(source) {
+			continue
+		}
+		return &Issue{
+			Content:     truncate(ctx.Text, 50),
+			Suggestion:  fmt.Sprintf("Model formatting left in the text: %q", strings.TrimSpace(match)),
+			AutoFixable: pattern == markdownArtifactPattern && fixModelArtifacts(ctx) != ctx.Text,
+		}
+	}
+	return nil
+}
+
+// fixModelArtifacts strips markdown emphasis and code fences
+func fixModelArtifacts(ctx RuleContext) string {
+	if ctx.Source != nil && markdownFixPattern.MatchString(ctx.Source.Text) {
+		return ctx.Text
+	}
+	return strings.TrimSpace(markdownFixPattern.ReplaceAllString(ctx.Text, ""))
+}
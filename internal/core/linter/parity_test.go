@@ -0,0 +1,160 @@
+package linter
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// parityOpts compares one translation with its source, English to Portuguese
+func parityOpts(source string) CheckOptions {
+	return CheckOptions{SourceLang: "en", TargetLang: "pt-br", Format: "ass", Source: TextLines([]string{source})}
+}
+
+// TestParityNeedsSource tests that parity rules are skipped without sources
+func TestParityNeedsSource(t *testing.T) {
+	ids := issuesOf(TextLines([]string{"I will be there at 10:30"}), CheckOptions{SourceLang: "en", TargetLang: "pt-br"})
+	for _, id := range []string{RuleTagParity, RuleTokenParity, RuleUntranslated, RuleLengthRatio} {
+		if slices.Contains(ids, id) {
+			t.Errorf("%s should need a source, got %v", id, ids)
+		}
+	}
+}
+
+// TestTagParity tests override tag preservation and restoring leading tags
+func TestTagParity(t *testing.T) {
+	source := `{\an8\pos(320,50)}Where are you {\i1}going{\i0}?`
+
+	lines := TextLines([]string{`{\an8\pos(320,50)}Aonde você {\i1}vai{\i0}?`})
+	if ids := issuesOf(lines, parityOpts(source)); slices.Contains(ids, RuleTagParity) {
+		t.Errorf("same tags should pass, got %v", ids)
+	}
+
+	lines = TextLines([]string{`{\an8\pos(320,50)}Aonde você vai?`})
+	result := Check(lines, parityOpts(source))
+	if len(result.Issues) != 1 || result.Issues[0].RuleID != RuleTagParity || result.Issues[0].AutoFixable {
+		t.Errorf("dropped inline tags should be a non-fixable mismatch, got %+v", result.Issues)
+	}
+
+	source = `{\an8\fad(200,200)}Where are you going?`
+	lines = TextLines([]string{"Aonde você vai?"})
+	opts := parityOpts(source)
+	result = Check(lines, opts)
+	if len(result.Issues) != 1 || !result.Issues[0].AutoFixable {
+		t.Fatalf("lost leading tags should be fixable, got %+v", result.Issues)
+	}
+	if fixed := AutoFix(lines, result.Issues); fixed[0].Text != `{\an8\fad(200,200)}Aonde você vai?` {
+		t.Errorf("fixed text = %q", fixed[0].Text)
+	}
+
+	// Comments carry no override tags and may be dropped
+	lines = TextLines([]string{"Aonde você vai?"})
+	if ids := issuesOf(lines, parityOpts("{TL note: slang}Where are you going?")); len(ids) != 0 {
+		t.Errorf("comments should not count as tags, got %v", ids)
+	}
+}
+
+// TestBreakParity tests the line break count rule
+func TestBreakParity(t *testing.T) {
+	opts := parityOpts(`Where are you going?\NWait for me!`)
+	if ids := issuesOf(TextLines([]string{"Aonde você vai? Espere por mim!"}), opts); !slices.Contains(ids, RuleBreakParity) {
+		t.Errorf("a lost break should be flagged, got %v", ids)
+	}
+	if ids := issuesOf(TextLines([]string{`Aonde você vai?\NEspere por mim!`}), opts); len(ids) != 0 {
+		t.Errorf("same breaks should pass, got %v", ids)
+	}
+}
+
+// TestTokenParity tests numbers, times and URLs
+func TestTokenParity(t *testing.T) {
+	tests := []struct {
+		source, target string
+		flagged        bool
+	}{
+		{"Meet me at 10:30 on platform 9", "Me encontre às 10:30 na plataforma 9", false},
+		{"Meet me at 10:30 on platform 9", "Me encontre às 10h30 na plataforma 9", true},
+		{"It costs 1,000.50 yen", "Custa 1.000,50 ienes", false},
+		{"Room 204 and 205", "Sala 204", true},
+		{"See https://example.com/a for details", "Veja https://example.com/a para detalhes", false},
+		{"See https://example.com/a for details", "Veja o site para detalhes", true},
+	}
+
+	for _, tt := range tests {
+		ids := issuesOf(TextLines([]string{tt.target}), parityOpts(tt.source))
+		if got := slices.Contains(ids, RuleTokenParity); got != tt.flagged {
+			t.Errorf("%q -> %q: flagged = %v, want %v (%v)", tt.source, tt.target, got, tt.flagged, ids)
+		}
+	}
+}
+
+// TestUntranslated tests identical source and target lines
+func TestUntranslated(t *testing.T) {
+	opts := parityOpts(`{\i1}Get out of here!{\i0}`)
+	if ids := issuesOf(TextLines([]string{"Get out of  here!"}), opts); !slices.Contains(ids, RuleUntranslated) {
+		t.Errorf("a copied line should be flagged, got %v", ids)
+	}
+
+	// Names and interjections shorter than min_words are often kept
+	if ids := issuesOf(TextLines([]string{"Naruto!"}), parityOpts("Naruto!")); slices.Contains(ids, RuleUntranslated) {
+		t.Errorf("single words should pass, got %v", ids)
+	}
+
+	opts.TargetLang = "EN"
+	if ids := issuesOf(TextLines([]string{"Get out of here!"}), opts); slices.Contains(ids, RuleUntranslated) {
+		t.Errorf("same-language jobs should pass, got %v", ids)
+	}
+}
+
+// TestLengthRatio tests suspicious translation lengths
+func TestLengthRatio(t *testing.T) {
+	opts := parityOpts("I told you a hundred times not to touch my things!")
+	if ids := issuesOf(TextLines([]string{"Não!"}), opts); !slices.Contains(ids, RuleLengthRatio) {
+		t.Errorf("a truncated line should be flagged, got %v", ids)
+	}
+	if ids := issuesOf(TextLines([]string{"Eu te disse cem vezes para não mexer nas minhas coisas!"}), opts); slices.Contains(ids, RuleLengthRatio) {
+		t.Errorf("a normal translation should pass, got %v", ids)
+	}
+
+	// Short sources are exempt
+	if ids := issuesOf(TextLines([]string{"Sim, senhor, imediatamente!"}), parityOpts("Yes!")); slices.Contains(ids, RuleLengthRatio) {
+		t.Errorf("short sources should pass, got %v", ids)
+	}
+}
+
+// TestModelArtifacts tests JSON and markdown leftovers
+func TestModelArtifacts(t *testing.T) {
+	tests := []struct {
+		target  string
+		fixable bool
+		fixed   string
+	}{
+		{`{"id": 3, "text": "Olá"}`, false, ""},
+		{"**Cuidado!** Atrás de você!", true, "Cuidado! Atrás de você!"},
+		{"```Olá```", true, "Olá"},
+		{"Translation: Olá", false, ""},
+	}
+
+	for _, tt := range tests {
+		lines := []parser.SubtitleLine{{Index: 1, Text: tt.target}}
+		result := Check(lines, CheckOptions{TargetLang: "pt-br"})
+		if len(result.Issues) != 1 || result.Issues[0].RuleID != RuleArtifacts {
+			t.Errorf("%q should be flagged, got %+v", tt.target, result.Issues)
+			continue
+		}
+		if result.Issues[0].AutoFixable != tt.fixable {
+			t.Errorf("%q fixable = %v, want %v", tt.target, result.Issues[0].AutoFixable, tt.fixable)
+		}
+		if tt.fixable {
+			if got := AutoFix(lines, result.Issues)[0].Text; got != tt.fixed {
+				t.Errorf("fixed %q = %q, want %q", tt.target, got, tt.fixed)
+			}
+		}
+	}
+
+	// Formatting already present in the source is kept
+	opts := parityOpts("**Chapter 1**")
+	if ids := issuesOf(TextLines([]string{"**Capítulo 1**"}), opts); slices.Contains(ids, RuleArtifacts) {
+		t.Errorf("source formatting should pass, got %v", ids)
+	}
+}
@@ -0,0 +1,262 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ReadingLimits are the readability limits of a subtitle language
+type ReadingLimits struct {
+	MaxCPS      float64       // Characters per second, spaces and punctuation included
+	MaxChars    int           // Characters per line
+	MaxLines    int           // Lines per event
+	MinDuration time.Duration // Time on screen
+}
+
+// defaultLimits apply to languages without their own entry (most Latin and Cyrillic scripts)
+var defaultLimits = ReadingLimits{MaxCPS: 17, MaxChars: 42, MaxLines: 2, MinDuration: 833 * time.Millisecond}
+
+// languageLimits follow the Netflix timed text style guides for adult content
+var languageLimits = map[string]ReadingLimits{
+	"en": {MaxCPS: 20, MaxChars: 42, MaxLines: 2, MinDuration: 833 * time.Millisecond},
+	"ja": {MaxCPS: 4, MaxChars: 13, MaxLines: 2, MinDuration: 833 * time.Millisecond},
+	"zh": {MaxCPS: 9, MaxChars: 16, MaxLines: 2, MinDuration: 833 * time.Millisecond},
+	"ko": {MaxCPS: 12, MaxChars: 16, MaxLines: 2, MinDuration: 833 * time.Millisecond},
+	"th": {MaxCPS: 15, MaxChars: 35, MaxLines: 2, MinDuration: 833 * time.Millisecond},
+}
+
+// LimitsFor returns the readability limits for a language code such as "pt-br" or "ja"
+func LimitsFor(lang string) ReadingLimits {
+	if limits, ok := languageLimits[baseLang(lang)]; ok {
+		return limits
+	}
+	return defaultLimits
+}
+
+// baseLang returns the lowercase base code of a language ("pt" for "pt-BR")
+func baseLang(lang string) string {
+	base := strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
+
+var (
+	// tagBlockPattern matches ASS override blocks and comments
+	tagBlockPattern = regexp.MustCompile(`\{[^}]*\}`)
+
+	// lineBreakPattern matches ASS hard (\N) and soft (\n) breaks and SRT newlines
+	lineBreakPattern = regexp.MustCompile(`\\[Nn]|\r?\n`)
+)
+
+// visibleLength counts the characters shown on screen, without override tags
+func visibleLength(text string) int {
+	return utf8.RuneCountInString(tagBlockPattern.ReplaceAllString(text, ""))
+}
+
+// displayLines splits a subtitle text at its line breaks
+func displayLines(text string) []string {
+	return lineBreakPattern.Split(text, -1)
+}
+
+// lineBreakFor returns the break used by the text, or the format's break when it has none
+func lineBreakFor(text, format string) string {
+	switch {
+	case strings.Contains(text, "\n"):
+		return "\n"
+	case strings.Contains(text, `\N`):
+		return `\N`
+	case strings.EqualFold(format, "srt"):
+		return "\n"
+	}
+	return `\N`
+}
+
+// checkReadingSpeed flags lines with more characters per second than maxCPS
+func checkReadingSpeed(ctx RuleContext, maxCPS float64) *Issue {
+	duration, ok := ctx.Duration()
+	if !ok || maxCPS <= 0 {
+		return nil
+	}
+
+	chars := 0
+	for _, line := range displayLines(ctx.Text) {
+		chars += visibleLength(line)
+	}
+	cps := float64(chars) / duration.Seconds()
+	if cps <= maxCPS {
+		return nil
+	}
+
+	return &Issue{
+		Content: truncate(ctx.Text, 50),
+		Suggestion: fmt.Sprintf("%.1f characters/second over %.2fs (max %.0f); shorten the line",
+			cps, duration.Seconds(), maxCPS),
+	}
+}
+
+// checkLineLength flags lines wider than maxChars; fixable when a rebalanced
+// layout within maxLines fits
+func checkLineLength(ctx RuleContext, maxChars, maxLines int) *Issue {
+	if maxChars <= 0 {
+		return nil
+	}
+
+	for i, line := range displayLines(ctx.Text) {
+		if n := visibleLength(line); n > maxChars {
+			_, fixable := rebalance(ctx.Text, maxChars, maxLines, ctx.Check.Format)
+			return &Issue{
+				Content:     truncate(ctx.Text, 50),
+				Suggestion:  fmt.Sprintf("Line %d has %d characters (max %d)", i+1, n, maxChars),
+				AutoFixable: fixable,
+			}
+		}
+	}
+	return nil
+}
+
+// checkLineCount flags events with more than maxLines lines
+func checkLineCount(ctx RuleContext, maxChars, maxLines int) *Issue {
+	if maxLines <= 0 {
+		return nil
+	}
+
+	n := len(displayLines(ctx.Text))
+	if n <= maxLines {
+		return nil
+	}
+
+	_, fixable := rebalance(ctx.Text, maxChars, maxLines, ctx.Check.Format)
+	return &Issue{
+		Content:     truncate(ctx.Text, 50),
+		Suggestion:  fmt.Sprintf("%d lines (max %d)", n, maxLines),
+		AutoFixable: fixable,
+	}
+}
+
+// checkMinDuration flags events shown for less than minDuration
+func checkMinDuration(ctx RuleContext, minDuration time.Duration) *Issue {
+	duration, ok := ctx.Duration()
+	if !ok || duration >= minDuration {
+		return nil
+	}
+
+	return &Issue{
+		Content:    truncate(ctx.Text, 50),
+		Suggestion: fmt.Sprintf("On screen for %.2fs (min %.2fs); extend or merge the event", duration.Seconds(), minDuration.Seconds()),
+	}
+}
+
+// rebalance redistributes the words of a subtitle over as few lines as fit
+// maxChars, at most maxLines, keeping the lines as even as possible. It
+// reports false, returning the text unchanged, when no such layout exists or
+// the event is a dialogue with one speaker per line.
+func rebalance(text string, maxChars, maxLines int, format string) (string, bool) {
+	if maxChars <= 0 || maxLines <= 0 {
+		return text, false
+	}
+
+	lines := displayLines(text)
+	if isDialogue(lines) {
+		return text, false
+	}
+
+	var words []string
+	for _, line := range lines {
+		words = append(words, splitWords(line)...)
+	}
+	if len(words) == 0 {
+		return text, false
+	}
+
+	lengths := make([]int, len(words))
+	for i, w := range words {
+		lengths[i] = visibleLength(w)
+	}
+
+	for n := 1; n <= maxLines && n <= len(words); n++ {
+		widest, breaks := bestBreaks(lengths, 0, n)
+		if widest > maxChars {
+			continue
+		}
+
+		out := make([]string, 0, n)
+		start := 0
+		for _, end := range append(breaks, len(words)) {
+			out = append(out, strings.Join(words[start:end], " "))
+			start = end
+		}
+		return strings.Join(out, lineBreakFor(text, format)), true
+	}
+	return text, false
+}
+
+// bestBreaks splits words[start:] into n lines, minimizing the widest line.
+// It returns that width and the word indexes where lines 2..n begin. Ties
+// keep the earlier break, so upper lines are never longer than they need be.
+func bestBreaks(lengths []int, start, n int) (int, []int) {
+	if n == 1 {
+		width := len(lengths[start:]) - 1
+		for _, l := range lengths[start:] {
+			width += l
+		}
+		return width, nil
+	}
+
+	bestWidth, bestCut := -1, []int(nil)
+	width := -1
+	for end := start + 1; end <= len(lengths)-(n-1); end++ {
+		width += lengths[end-1] + 1
+		rest, cuts := bestBreaks(lengths, end, n-1)
+		widest := max(width, rest)
+		if bestWidth < 0 || widest < bestWidth {
+			bestWidth = widest
+			bestCut = append([]int{end}, cuts...)
+		}
+	}
+	return bestWidth, bestCut
+}
+
+// isDialogue reports whether every line starts with a speaker dash (-, – or —)
+func isDialogue(lines []string) bool {
+	if len(lines) < 2 {
+		return false
+	}
+	for _, line := range lines {
+		visible := strings.TrimSpace(tagBlockPattern.ReplaceAllString(line, ""))
+		if !strings.HasPrefix(visible, "-") && !strings.HasPrefix(visible, "–") && !strings.HasPrefix(visible, "—") {
+			return false
+		}
+	}
+	return true
+}
+
+// splitWords splits a line on spaces outside override blocks
+func splitWords(line string) []string {
+	var words []string
+	var current strings.Builder
+	depth := 0
+	for _, r := range line {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}' && depth > 0:
+			depth--
+		case r == ' ' && depth == 0:
+			if current.Len() > 0 {
+				words = append(words, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		words = append(words, current.String())
+	}
+	return words
+}
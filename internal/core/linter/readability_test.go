@@ -0,0 +1,166 @@
+package linter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// timedLine builds an ASS line shown from 0:00:01.00 for the given duration
+func timedLine(text string, duration time.Duration) parser.SubtitleLine {
+	end := time.Second + duration
+	return parser.SubtitleLine{
+		Index:     1,
+		StartTime: "0:00:01.00",
+		EndTime:   "0:00:" + time.Time{}.Add(end).Format("05.00"),
+		Text:      text,
+	}
+}
+
+// issuesOf returns the IDs of the rules that flagged the lines
+func issuesOf(lines []parser.SubtitleLine, opts CheckOptions) []string {
+	var ids []string
+	for _, issue := range Check(lines, opts).Issues {
+		ids = append(ids, issue.RuleID)
+	}
+	return ids
+}
+
+// TestLimitsFor tests language-aware defaults
+func TestLimitsFor(t *testing.T) {
+	tests := map[string]ReadingLimits{
+		"pt-br": defaultLimits,
+		"EN-US": languageLimits["en"],
+		"ja":    languageLimits["ja"],
+		"zh_CN": languageLimits["zh"],
+		"":      defaultLimits,
+	}
+	for lang, want := range tests {
+		if got := LimitsFor(lang); got != want {
+			t.Errorf("LimitsFor(%q) = %+v, want %+v", lang, got, want)
+		}
+	}
+}
+
+// TestReadingSpeed tests the characters-per-second rule
+func TestReadingSpeed(t *testing.T) {
+	text := `{\i1}Eu nunca pensei que você viria sozinho.{\i0}` // 39 visible characters
+	opts := CheckOptions{TargetLang: "pt-br"}
+
+	if ids := issuesOf([]parser.SubtitleLine{timedLine(text, 1200*time.Millisecond)}, opts); len(ids) != 1 || ids[0] != RuleReadingSpeed {
+		t.Errorf("39 characters in 1.2s should exceed 17 CPS, got %v", ids)
+	}
+	if ids := issuesOf([]parser.SubtitleLine{timedLine(text, 3*time.Second)}, opts); len(ids) != 0 {
+		t.Errorf("39 characters in 3s should pass, got %v", ids)
+	}
+
+	// English allows 20 CPS: 39 / 2.1s = 18.6
+	opts.TargetLang = "en"
+	if ids := issuesOf([]parser.SubtitleLine{timedLine(text, 2100*time.Millisecond)}, opts); len(ids) != 0 {
+		t.Errorf("18.6 CPS should pass in English, got %v", ids)
+	}
+
+	// Untimed lines are skipped
+	if ids := issuesOf(TextLines([]string{text}), CheckOptions{TargetLang: "pt-br"}); len(ids) != 0 {
+		t.Errorf("untimed line should not be checked for timing, got %v", ids)
+	}
+}
+
+// TestMinDuration tests the minimum duration rule and its option
+func TestMinDuration(t *testing.T) {
+	lines := []parser.SubtitleLine{timedLine("Oi", 500*time.Millisecond)}
+
+	if ids := issuesOf(lines, CheckOptions{}); len(ids) != 1 || ids[0] != RuleMinDuration {
+		t.Errorf("0.5s should be too short, got %v", ids)
+	}
+
+	opts := CheckOptions{Rules: map[string]RuleConfig{RuleMinDuration: {Options: map[string]any{"min_ms": float64(400)}}}}
+	if ids := issuesOf(lines, opts); len(ids) != 0 {
+		t.Errorf("0.5s should pass a 400ms minimum, got %v", ids)
+	}
+}
+
+// TestLineLengthRebalances tests flagging a long line and fixing its breaks
+func TestLineLengthRebalances(t *testing.T) {
+	tests := []struct {
+		name, format, text, want string
+	}{
+		{"ass single line", "ass",
+			"Eu nunca pensei que você viria até aqui sozinho no meio da noite",
+			`Eu nunca pensei que você viria\Naté aqui sozinho no meio da noite`},
+		{"ass unbalanced break", "ass",
+			`Eu nunca pensei que você viria até aqui sozinho no\Nmeio da noite`,
+			`Eu nunca pensei que você viria\Naté aqui sozinho no meio da noite`},
+		{"srt single line", "srt",
+			"Eu nunca pensei que você viria até aqui sozinho no meio da noite",
+			"Eu nunca pensei que você viria\naté aqui sozinho no meio da noite"},
+		{"tags kept", "ass",
+			`{\an8}Eu nunca pensei que você viria até aqui sozinho no meio da noite`,
+			`{\an8}Eu nunca pensei que você viria\Naté aqui sozinho no meio da noite`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := TextLines([]string{tt.text})
+			result := Check(lines, CheckOptions{TargetLang: "pt-br", Format: tt.format})
+			if len(result.Issues) != 1 || result.Issues[0].RuleID != RuleLineLength || !result.Issues[0].AutoFixable {
+				t.Fatalf("expected a fixable line-length issue, got %+v", result.Issues)
+			}
+
+			fixed := AutoFix(lines, result.Issues)
+			if fixed[0].Text != tt.want {
+				t.Errorf("AutoFix = %q, want %q", fixed[0].Text, tt.want)
+			}
+			if after := Check(fixed, CheckOptions{TargetLang: "pt-br", Format: tt.format}); len(after.Issues) != 0 {
+				t.Errorf("fixed line should pass, got %+v", after.Issues)
+			}
+		})
+	}
+}
+
+// TestLineLengthUnfixable tests lines that can't be rebalanced
+func TestLineLengthUnfixable(t *testing.T) {
+	tests := map[string]string{
+		"too long for two lines": "Eu nunca pensei que você viria até aqui sozinho no meio da noite fria e escura sem avisar ninguém",
+		"dialogue":               `- Eu nunca pensei que você viria até aqui sozinho\N- Nem eu.`,
+	}
+
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			lines := TextLines([]string{text})
+			result := Check(lines, CheckOptions{TargetLang: "pt-br"})
+			if len(result.Issues) != 1 || result.Issues[0].AutoFixable {
+				t.Fatalf("expected an unfixable issue, got %+v", result.Issues)
+			}
+			if fixed := AutoFix(lines, result.Issues); fixed[0].Text != text {
+				t.Errorf("AutoFix changed an unfixable line: %q", fixed[0].Text)
+			}
+		})
+	}
+}
+
+// TestMaxLines tests the line count rule
+func TestMaxLines(t *testing.T) {
+	lines := TextLines([]string{`Espera,\Naonde você\Nvai?`})
+	result := Check(lines, CheckOptions{TargetLang: "pt-br"})
+	if len(result.Issues) != 1 || result.Issues[0].RuleID != RuleMaxLines {
+		t.Fatalf("expected a max-lines issue, got %+v", result.Issues)
+	}
+
+	if fixed := AutoFix(lines, result.Issues); fixed[0].Text != "Espera, aonde você vai?" {
+		t.Errorf("short text should be joined on one line, got %q", fixed[0].Text)
+	}
+}
+
+// TestRebalanceJapanese tests the narrower Japanese limit
+func TestRebalanceJapanese(t *testing.T) {
+	lines := TextLines([]string{"ちょっと待って ここはどこなの"}) // 15 characters
+	result := Check(lines, CheckOptions{TargetLang: "ja"})
+	if len(result.Issues) != 1 || result.Issues[0].RuleID != RuleLineLength {
+		t.Fatalf("expected a line-length issue over 13 characters, got %+v", result.Issues)
+	}
+	if fixed := AutoFix(lines, result.Issues); fixed[0].Text != `ちょっと待って\Nここはどこなの` {
+		t.Errorf("AutoFix = %q", fixed[0].Text)
+	}
+}
@@ -0,0 +1,239 @@
+package linter
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// profileFiles holds one word list per language, named by base code (en.txt)
+//
+//go:embed profiles/*.txt
+var profileFiles embed.FS
+
+// languageProfile holds words and word n-grams typical of a language
+type languageProfile struct {
+	entries map[string]bool
+	maxN    int // Words in the longest entry
+}
+
+// newProfile builds a profile from lowercase entries
+func newProfile(entries []string) *languageProfile {
+	p := &languageProfile{entries: make(map[string]bool, len(entries))}
+	for _, entry := range entries {
+		words := strings.Fields(strings.ToLower(entry))
+		if len(words) == 0 {
+			continue
+		}
+		p.entries[strings.Join(words, " ")] = true
+		p.maxN = max(p.maxN, len(words))
+	}
+	return p
+}
+
+// match returns the entry starting at tokens[i], preferring the longest
+func (p *languageProfile) match(tokens []string, i int) string {
+	for n := min(p.maxN, len(tokens)-i); n > 0; n-- {
+		if key := strings.Join(tokens[i:i+n], " "); p.entries[key] {
+			return key
+		}
+	}
+	return ""
+}
+
+var (
+	profilesOnce sync.Once
+	profiles     map[string]*languageProfile
+)
+
+// profileFor returns the embedded profile of a language, nil when there is none
+func profileFor(lang string) *languageProfile {
+	profilesOnce.Do(func() {
+		profiles = make(map[string]*languageProfile)
+		files, _ := profileFiles.ReadDir("profiles")
+		for _, f := range files {
+			data, err := profileFiles.ReadFile(path.Join("profiles", f.Name()))
+			if err != nil {
+				continue
+			}
+			var entries []string
+			scanner := bufio.NewScanner(strings.NewReader(string(data)))
+			for scanner.Scan() {
+				if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+					entries = append(entries, line)
+				}
+			}
+			profiles[strings.TrimSuffix(f.Name(), ".txt")] = newProfile(entries)
+		}
+	})
+	return profiles[baseLang(lang)]
+}
+
+// residueScript is a writing system whose letters in a target written
+// otherwise mean part of the line was left untranslated
+type residueScript struct {
+	name   string
+	tables []*unicode.RangeTable
+	langs  []string // Targets written in this script
+}
+
+var residueScripts = []residueScript{
+	{"kana", []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana}, []string{"ja"}},
+	{"kanji", []*unicode.RangeTable{unicode.Han}, []string{"ja", "zh", "ko"}},
+	{"hangul", []*unicode.RangeTable{unicode.Hangul}, []string{"ko"}},
+	{"cyrillic", []*unicode.RangeTable{unicode.Cyrillic}, []string{"ru", "uk", "bg", "sr", "be", "mk", "kk"}},
+}
+
+// honorifics are kept on purpose by many translations and never count as residue
+var honorifics = []string{
+	"san", "kun", "chan", "sama", "sensei", "senpai", "dono", "tan", "han", "shi",
+	"さん", "くん", "君", "ちゃん", "さま", "様", "先生", "先輩", "殿",
+}
+
+// removeTerms blanks out allowlisted terms; Latin terms only match whole words
+func removeTerms(text string, terms []string) string {
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" {
+			continue
+		}
+		latin := !strings.ContainsFunc(term, func(r rune) bool {
+			return unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r)
+		})
+
+		var out strings.Builder
+		rest := text
+		for {
+			i := strings.Index(rest, term)
+			if i < 0 {
+				break
+			}
+			end := i + len(term)
+			before, _ := utf8.DecodeLastRuneInString(rest[:i])
+			after, _ := utf8.DecodeRuneInString(rest[end:])
+			if latin && (isWordRune(before) || isWordRune(after)) {
+				out.WriteString(rest[:end])
+			} else {
+				out.WriteString(rest[:i])
+				out.WriteByte(' ')
+			}
+			rest = rest[end:]
+		}
+		out.WriteString(rest)
+		text = out.String()
+	}
+	return text
+}
+
+// isWordRune reports whether r continues a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\''
+}
+
+// residueTokens splits lowercased text into words, keeping contractions
+func residueTokens(text string) []string {
+	text = strings.ReplaceAll(text, "’", "'")
+	words := strings.FieldsFunc(text, func(r rune) bool { return !isWordRune(r) })
+	tokens := words[:0]
+	for _, w := range words {
+		if w = strings.Trim(w, "'"); w != "" {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
+
+// foreignScript returns the first run of letters in a script the target
+// language is not written in
+func foreignScript(text, targetLang string) (string, string) {
+	target := baseLang(targetLang)
+	runes := []rune(text)
+	for i, r := range runes {
+		for _, script := range residueScripts {
+			if !unicode.In(r, script.tables...) || slices.Contains(script.langs, target) {
+				continue
+			}
+			end := i
+			for end < len(runes) && unicode.In(runes[end], script.tables...) {
+				end++
+			}
+			return script.name, string(runes[i:end])
+		}
+	}
+	return "", ""
+}
+
+// checkSourceResidue detects source-language text left in a translation: letters
+// of a script the target does not use, words typical of the source language
+// but not of the target, and the configured extra words. Glossary terms,
+// honorifics and the allow list never count.
+func checkSourceResidue(ctx RuleContext, words, allow []string) *Issue {
+	if ctx.Check.TargetLang == "" {
+		return nil
+	}
+
+	text := tagBlockPattern.ReplaceAllString(ctx.Text, "")
+	text = lineBreakPattern.ReplaceAllString(text, " ")
+	text = urlPattern.ReplaceAllString(strings.ToLower(text), " ")
+
+	terms := append(append([]string{}, honorifics...), allow...)
+	for original, translation := range ctx.Check.Glossary {
+		terms = append(terms, original, translation)
+	}
+	text = removeTerms(text, terms)
+
+	if script, run := foreignScript(text, ctx.Check.TargetLang); run != "" {
+		return residueIssue(ctx, fmt.Sprintf("Untranslated %s detected: '%s'", script, run))
+	}
+
+	tokens := residueTokens(text)
+	extra := newProfile(words)
+	for i := range tokens {
+		if word := extra.match(tokens, i); word != "" {
+			return residueIssue(ctx, fmt.Sprintf("Source word detected: '%s'", word))
+		}
+	}
+
+	sourceLang := ctx.Check.residueLang
+	if sourceLang == "" {
+		sourceLang = resolveSourceLang(ctx.Check.SourceLang, nil)
+	}
+	source := profileFor(sourceLang)
+	if source == nil || baseLang(sourceLang) == baseLang(ctx.Check.TargetLang) {
+		return nil
+	}
+
+	// Without a target profile shared words cannot be ruled out, so one hit is not enough
+	target := profileFor(ctx.Check.TargetLang)
+	needed := 1
+	if target == nil {
+		needed = 2
+	}
+
+	var hits []string
+	for i := range tokens {
+		word := source.match(tokens, i)
+		if word == "" || (target != nil && target.entries[word]) || slices.Contains(hits, word) {
+			continue
+		}
+		if hits = append(hits, word); len(hits) >= needed {
+			return residueIssue(ctx, fmt.Sprintf("%s word detected: '%s'",
+				strings.ToUpper(baseLang(sourceLang)), strings.Join(hits, "', '")))
+		}
+	}
+	return nil
+}
+
+// residueIssue builds a source residue issue
+func residueIssue(ctx RuleContext, suggestion string) *Issue {
+	return &Issue{
+		Content:    truncate(ctx.Text, 50),
+		Suggestion: suggestion,
+	}
+}
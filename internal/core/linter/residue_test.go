@@ -0,0 +1,164 @@
+package linter
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// residueOf returns the source residue suggestion for one line, or "" when clean
+func residueOf(text string, opts CheckOptions) string {
+	for _, issue := range Check(TextLines([]string{text}), opts).Issues {
+		if issue.RuleID == RuleSourceResidue {
+			return issue.Suggestion
+		}
+	}
+	return ""
+}
+
+// TestProfilesEmbedded tests that every profile loads
+func TestProfilesEmbedded(t *testing.T) {
+	for _, lang := range []string{"en", "pt-BR", "es", "fr", "de", "it", "ja-jp"} {
+		if p := profileFor(lang); p == nil || len(p.entries) < 15 {
+			t.Errorf("profile for %q missing or too small", lang)
+		}
+	}
+	if profileFor("en").maxN < 2 {
+		t.Error("profiles should include word n-grams")
+	}
+}
+
+// TestScriptResidue tests kana, kanji, hangul and cyrillic in foreign targets
+func TestScriptResidue(t *testing.T) {
+	tests := []struct {
+		text, target string
+		want         string
+	}{
+		{"Muito obrigado, ありがとう!", "pt-br", "kana"},
+		{"Ele usou o 螺旋丸 de novo", "pt-br", "kanji"},
+		{"Eu te amo, 사랑해", "en", "hangul"},
+		{"Da, конечно", "es", "cyrillic"},
+		{"ありがとう、先生", "ja", ""},
+		{"Спасибо", "ru", ""},
+		{"他说ありがとう", "zh", "kana"},
+	}
+
+	for _, tt := range tests {
+		got := residueOf(tt.text, CheckOptions{SourceLang: "ja", TargetLang: tt.target})
+		if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Errorf("%q -> %s: got %q, want %q", tt.text, tt.target, got, tt.want)
+		}
+	}
+}
+
+// TestWordResidue tests source-language words for several language pairs
+func TestWordResidue(t *testing.T) {
+	tests := []struct {
+		text, source, target string
+		flagged              bool
+	}{
+		{"Onde está the Nakama?", "en", "pt-br", true},
+		{"Onde está o Nakama?", "en", "pt-br", false},
+		{"Where is el dinero?", "es", "en", true},
+		{"Where is the money?", "es", "en", false},
+		{"Ich weiß nicht, mon ami", "fr", "de", true},
+		{"Wait, nani?", "ja", "en", true},
+		// Words shared by both languages are not residue
+		{"Não, nada disso", "es", "pt-br", false},
+		{"No, no lo sé", "en", "es", false},
+		// Without a target profile a single word may be legitimate (Dutch "is")
+		{"Het is goed", "en", "nl", false},
+		{"Het is the end", "en", "nl", true},
+		// Same language is never residue; sources without a profile have no words
+		{"What is this?", "en", "en-us", false},
+		{"What is this?", "nl", "pt-br", false},
+		// Undetected sources fall back to English
+		{"What is this?", "", "pt-br", true},
+		{"What is this?", "auto", "pt-br", true},
+	}
+
+	for _, tt := range tests {
+		got := residueOf(tt.text, CheckOptions{SourceLang: tt.source, TargetLang: tt.target})
+		if (got != "") != tt.flagged {
+			t.Errorf("%q (%s -> %s): got %q, flagged want %v", tt.text, tt.source, tt.target, got, tt.flagged)
+		}
+	}
+}
+
+// TestDetectLanguage tests guessing the source language by script and word profile
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		texts []string
+		want  string
+	}{
+		{[]string{"ありがとう、先生", "{\\i1}行くぞ！{\\i0}"}, "ja"},
+		{[]string{"他说什么？"}, "zh"},
+		{[]string{"감사합니다"}, "ko"},
+		{[]string{"Спасибо, друг"}, "ru"},
+		{[]string{"What is this?", "I don't know what they were doing."}, "en"},
+		{[]string{"¿Dónde está el dinero?", "No lo sé, pero es para los niños."}, "es"},
+		{[]string{"Hm.", "Ok!"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := DetectLanguage(tt.texts); got != tt.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", tt.texts, got, tt.want)
+		}
+	}
+}
+
+// TestAutoSourceResidue tests that jobs with an "auto" source lint against the detected language
+func TestAutoSourceResidue(t *testing.T) {
+	source := TextLines([]string{"¿Dónde está el dinero?", "No lo sé, pero es para los niños.", "Hola"})
+	translated := TextLines([]string{"Where is the money?", "I don't know, but it's for los niños.", "Hello"})
+
+	result := Check(translated, CheckOptions{SourceLang: AutoLang, TargetLang: "en", Source: source, Only: []string{RuleSourceResidue}})
+	if len(result.Issues) != 1 || result.Issues[0].LineID != 2 || !strings.Contains(result.Issues[0].Suggestion, "ES word") {
+		t.Errorf("expected Spanish residue on line 2, got %+v", result.Issues)
+	}
+}
+
+// TestResidueAllowlists tests glossary terms, honorifics and the allow option
+func TestResidueAllowlists(t *testing.T) {
+	opts := CheckOptions{SourceLang: "ja", TargetLang: "pt-br"}
+	if got := residueOf("Obrigado, Naruto-kun e Sakura-chan", opts); got != "" {
+		t.Errorf("romanized honorifics should pass, got %q", got)
+	}
+	if got := residueOf("Obrigado, Iruka先生", opts); got != "" {
+		t.Errorf("kanji honorifics should pass, got %q", got)
+	}
+
+	opts.Glossary = map[string]string{"螺旋丸": "Rasengan"}
+	if got := residueOf("Ele usou o 螺旋丸!", opts); got != "" {
+		t.Errorf("glossary terms should pass, got %q", got)
+	}
+
+	opts = CheckOptions{SourceLang: "en", TargetLang: "pt-br", Rules: map[string]RuleConfig{
+		RuleSourceResidue: {Options: map[string]any{"allow": []any{"The Flash"}}},
+	}}
+	if got := residueOf("Você viu The Flash?", opts); got != "" {
+		t.Errorf("allowed phrases should pass, got %q", got)
+	}
+	if got := residueOf("Você viu the carro?", opts); got == "" {
+		t.Error("allowing a phrase should not allow its words elsewhere")
+	}
+
+	// Glossary terms only match whole words
+	opts = CheckOptions{SourceLang: "en", TargetLang: "pt-br", Glossary: map[string]string{"he": "ele"}}
+	if got := residueOf("Onde está the carro?", opts); !strings.Contains(got, "the") {
+		t.Errorf("glossary term inside a word should not hide residue, got %q", got)
+	}
+}
+
+// TestResidueTokens tests contractions, curly apostrophes and n-grams
+func TestResidueTokens(t *testing.T) {
+	tokens := residueTokens("i don’t know, 'okay'")
+	if !slices.Equal(tokens, []string{"i", "don't", "know", "okay"}) {
+		t.Errorf("residueTokens = %v", tokens)
+	}
+
+	opts := CheckOptions{SourceLang: "fr", TargetLang: "pt-br"}
+	if got := residueOf("Aqui, il y a um problema", opts); !strings.Contains(got, "il y a") {
+		t.Errorf("n-grams should be preferred over single words, got %q", got)
+	}
+}
@@ -0,0 +1,428 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// Rule IDs of the built-in checks
+const (
+	RuleASSTags       = "ass-tags"
+	RuleBrackets      = "brackets"
+	RuleSourceResidue = "source-residue"
+	RulePunctuation   = "punctuation"
+	RuleGlossary      = "glossary"
+	RuleGlossaryTerms = "glossary-terms"
+	RuleReadingSpeed  = "reading-speed"
+	RuleLineLength    = "line-length"
+	RuleMaxLines      = "max-lines"
+	RuleMinDuration   = "min-duration"
+	RuleTagParity     = "tag-parity"
+	RuleBreakParity   = "break-parity"
+	RuleTokenParity   = "token-parity"
+	RuleUntranslated  = "untranslated"
+	RuleLengthRatio   = "length-ratio"
+	RuleArtifacts     = "model-artifacts"
+	RuleQuotes        = "quotes"
+	RuleEllipsis      = "ellipsis"
+	RuleDialogueDash  = "dialogue-dash"
+	RuleFrenchSpacing = "french-spacing"
+	RuleInvertedMarks = "inverted-marks"
+)
+
+// SeverityOff disables a rule when used in a RuleConfig
+const SeverityOff Severity = "OFF"
+
+// ParseSeverity converts a config value ("high", "med", "medium", "low", "off")
+// to a severity. An empty string returns "" so the rule default applies.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "":
+		return "", nil
+	case "HIGH":
+		return SeverityHigh, nil
+	case "MED", "MEDIUM":
+		return SeverityMedium, nil
+	case "LOW":
+		return SeverityLow, nil
+	case "OFF":
+		return SeverityOff, nil
+	}
+	return "", fmt.Errorf("unknown severity %q (want high, med, low or off)", s)
+}
+
+// RuleOptions holds rule-specific settings. Values come from JSON config, so
+// numbers may be float64 or int and lists []any or []string.
+type RuleOptions map[string]any
+
+// Int returns an integer option, or def when unset or not a number
+func (o RuleOptions) Int(key string, def int) int {
+	switch v := o[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return def
+}
+
+// Float returns a numeric option, or def when unset or not a number
+func (o RuleOptions) Float(key string, def float64) float64 {
+	switch v := o[key].(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return def
+}
+
+// Strings returns a list option, or def when unset or not a list
+func (o RuleOptions) Strings(key string, def []string) []string {
+	switch v := o[key].(type) {
+	case []string:
+		return v
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return def
+}
+
+// merge returns the options with overrides applied over them
+func (o RuleOptions) merge(overrides map[string]any) RuleOptions {
+	merged := make(RuleOptions, len(o)+len(overrides))
+	for k, v := range o {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+// RuleContext is what a rule sees while checking or fixing one line
+type RuleContext struct {
+	LineID  int
+	Line    parser.SubtitleLine
+	Source  *parser.SubtitleLine // Original of the line, nil when not provided
+	Text    string               // Text to check or fix; during AutoFix it includes earlier fixes
+	Options RuleOptions          // Rule defaults with configured overrides applied
+	Check   CheckOptions         // Options of the whole run (languages, glossary)
+}
+
+// Duration returns how long the line is on screen, if its timestamps are valid
+func (ctx RuleContext) Duration() (time.Duration, bool) {
+	start, ok := parser.ParseTimestamp(ctx.Line.StartTime)
+	if !ok {
+		return 0, false
+	}
+	end, ok := parser.ParseTimestamp(ctx.Line.EndTime)
+	if !ok || end <= start {
+		return 0, false
+	}
+	return end - start, true
+}
+
+// Rule is a quality check registered with the linter
+type Rule struct {
+	ID          string
+	IssueType   string // Name shown in reports
+	Description string
+	Severity    Severity    // Default severity
+	Options     RuleOptions // Default options
+	Advisory    bool        // Issues only fail the check when raised to HIGH
+	NeedsSource bool        // Skipped unless the original line is known (parity rules)
+
+	// Check returns an issue for the line, or nil. LineID, Severity,
+	// IssueType and RuleID of the issue are filled in by the linter; the
+	// issue should set AutoFixable when Fix can repair this line.
+	Check func(ctx RuleContext) *Issue
+
+	// Fix returns ctx.Text repaired; nil when the rule can't auto-fix
+	Fix func(ctx RuleContext) string
+}
+
+// RuleConfig overrides a rule's default severity and options
+type RuleConfig struct {
+	Severity Severity // "" = rule default, SeverityOff disables the rule
+	Options  map[string]any
+}
+
+var (
+	registry    []*Rule
+	registryIDs = map[string]*Rule{}
+)
+
+// Register adds a rule to the linter. Rules run in registration order.
+// It panics if the ID is empty or already registered.
+func Register(rule Rule) {
+	if rule.ID == "" || rule.Check == nil {
+		panic("linter: rule needs an ID and a Check function")
+	}
+	if _, dup := registryIDs[rule.ID]; dup {
+		panic("linter: rule registered twice: " + rule.ID)
+	}
+	r := &rule
+	registry = append(registry, r)
+	registryIDs[rule.ID] = r
+}
+
+// Rules returns the registered rules in the order they run
+func Rules() []Rule {
+	rules := make([]Rule, len(registry))
+	for i, r := range registry {
+		rules[i] = *r
+	}
+	return rules
+}
+
+// LookupRule returns the registered rule with the given ID
+func LookupRule(id string) (Rule, bool) {
+	r, ok := registryIDs[id]
+	if !ok {
+		return Rule{}, false
+	}
+	return *r, true
+}
+
+// suppressPattern matches inline suppression comments: an ASS comment block
+// such as {lint-disable} or {lint-disable: source-residue, punctuation}
+var suppressPattern = regexp.MustCompile(`\{\s*lint-disable(?:[\s:]+([^}]*))?\}`)
+
+// suppressions returns the rule IDs disabled on a line, and whether all rules are
+func suppressions(text string) (all bool, ids map[string]bool) {
+	for _, m := range suppressPattern.FindAllStringSubmatch(text, -1) {
+		list := strings.TrimSpace(m[1])
+		if list == "" {
+			return true, nil
+		}
+		for _, id := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' }) {
+			if ids == nil {
+				ids = make(map[string]bool)
+			}
+			ids[id] = true
+		}
+	}
+	return false, ids
+}
+
+func init() {
+	Register(Rule{
+		ID:          RuleASSTags,
+		IssueType:   "Broken ASS Tags",
+		Description: "Override tags opened with '{' but never closed",
+		Severity:    SeverityHigh,
+		Check: func(ctx RuleContext) *Issue {
+			return checkASSTags(ctx.LineID, ctx.Text)
+		},
+		Fix: func(ctx RuleContext) string { return fixASSTags(ctx.Text) },
+	})
+
+	Register(Rule{
+		ID:          RuleBrackets,
+		IssueType:   "Bracket Mismatch",
+		Description: "Unbalanced (), [] or {} brackets",
+		Severity:    SeverityMedium,
+		Check: func(ctx RuleContext) *Issue {
+			return checkBrackets(ctx.LineID, ctx.Text)
+		},
+		Fix: func(ctx RuleContext) string { return fixBrackets(ctx.Text) },
+	})
+
+	Register(Rule{
+		ID:          RuleSourceResidue,
+		IssueType:   "Source Residue",
+		Description: "Source-language script or words left in the translation; words are always flagged, allow never",
+		Severity:    SeverityMedium,
+		Options:     RuleOptions{"words": []string{}, "allow": []string{}},
+		Check: func(ctx RuleContext) *Issue {
+			return checkSourceResidue(ctx, ctx.Options.Strings("words", nil), ctx.Options.Strings("allow", nil))
+		},
+	})
+
+	Register(Rule{
+		ID:          RulePunctuation,
+		IssueType:   "Excessive Punctuation",
+		Description: "The same punctuation mark repeated more than max_repeat times",
+		Severity:    SeverityLow,
+		Options:     RuleOptions{"max_repeat": 2},
+		Check: func(ctx RuleContext) *Issue {
+			return checkPunctuation(ctx.LineID, ctx.Text, ctx.Options.Int("max_repeat", 2))
+		},
+		Fix: func(ctx RuleContext) string {
+			return fixPunctuation(ctx.Text, ctx.Options.Int("max_repeat", 2))
+		},
+	})
+
+	Register(Rule{
+		ID:          RuleGlossary,
+		IssueType:   "Glossary Mismatch",
+		Description: "A glossary term kept in the source form without its translation",
+		Severity:    SeverityLow,
+		Advisory:    true,
+		Check: func(ctx RuleContext) *Issue {
+			if len(ctx.Check.Glossary) == 0 {
+				return nil
+			}
+			return checkGlossaryMismatch(ctx.LineID, ctx.Text, ctx.Check.Glossary)
+		},
+	})
+
+	Register(Rule{
+		ID:          RuleGlossaryTerms,
+		IssueType:   "Glossary Term",
+		Description: "An enforced glossary term missing, left untranslated or rendered in a forbidden form",
+		Severity:    SeverityHigh,
+		Check: func(ctx RuleContext) *Issue {
+			if len(ctx.Check.Terms) == 0 {
+				return nil
+			}
+			return checkGlossaryTerms(ctx)
+		},
+		Fix: fixGlossaryTerms,
+	})
+
+	// Readability rules default to the target language's limits (see LimitsFor)
+	Register(Rule{
+		ID:          RuleReadingSpeed,
+		IssueType:   "Reading Speed",
+		Description: "More characters per second than max_cps",
+		Severity:    SeverityMedium,
+		Check: func(ctx RuleContext) *Issue {
+			return checkReadingSpeed(ctx, ctx.Options.Float("max_cps", ctx.limits().MaxCPS))
+		},
+	})
+
+	Register(Rule{
+		ID:          RuleLineLength,
+		IssueType:   "Line Too Long",
+		Description: "A line wider than max_chars; fixed by rebalancing the line breaks",
+		Severity:    SeverityMedium,
+		Check: func(ctx RuleContext) *Issue {
+			return checkLineLength(ctx, ctx.maxChars(), ctx.maxLines())
+		},
+		Fix: func(ctx RuleContext) string {
+			fixed, _ := rebalance(ctx.Text, ctx.maxChars(), ctx.maxLines(), ctx.Check.Format)
+			return fixed
+		},
+	})
+
+	Register(Rule{
+		ID:          RuleMaxLines,
+		IssueType:   "Too Many Lines",
+		Description: "More than max_lines lines in one event; fixed by rebalancing the line breaks",
+		Severity:    SeverityMedium,
+		Check: func(ctx RuleContext) *Issue {
+			return checkLineCount(ctx, ctx.maxChars(), ctx.maxLines())
+		},
+		Fix: func(ctx RuleContext) string {
+			fixed, _ := rebalance(ctx.Text, ctx.maxChars(), ctx.maxLines(), ctx.Check.Format)
+			return fixed
+		},
+	})
+
+	Register(Rule{
+		ID:          RuleMinDuration,
+		IssueType:   "Short Duration",
+		Description: "An event shown for less than min_ms milliseconds",
+		Severity:    SeverityLow,
+		Check: func(ctx RuleContext) *Issue {
+			minMS := ctx.Options.Int("min_ms", int(ctx.limits().MinDuration/time.Millisecond))
+			return checkMinDuration(ctx, time.Duration(minMS)*time.Millisecond)
+		},
+	})
+}
+
+// Parity rules compare each translation with its original
+func init() {
+	Register(Rule{
+		ID:          RuleTagParity,
+		IssueType:   "Tag Mismatch",
+		Description: "ASS override tags dropped, added or reordered by the translation",
+		Severity:    SeverityHigh,
+		NeedsSource: true,
+		Check:       checkTagParity,
+		Fix:         fixTagParity,
+	})
+
+	Register(Rule{
+		ID:          RuleBreakParity,
+		IssueType:   "Line Break Mismatch",
+		Description: "A different number of line breaks than the original",
+		Severity:    SeverityLow,
+		NeedsSource: true,
+		Check:       checkBreakParity,
+	})
+
+	Register(Rule{
+		ID:          RuleTokenParity,
+		IssueType:   "Missing Number/URL",
+		Description: "Numbers, times or URLs of the original missing from the translation",
+		Severity:    SeverityMedium,
+		NeedsSource: true,
+		Check:       checkTokenParity,
+	})
+
+	Register(Rule{
+		ID:          RuleUntranslated,
+		IssueType:   "Untranslated Line",
+		Description: "A line of at least min_words words identical to the original",
+		Severity:    SeverityHigh,
+		Options:     RuleOptions{"min_words": 2},
+		NeedsSource: true,
+		Check: func(ctx RuleContext) *Issue {
+			return checkUntranslated(ctx, ctx.Options.Int("min_words", 2))
+		},
+	})
+
+	Register(Rule{
+		ID:          RuleLengthRatio,
+		IssueType:   "Suspicious Length",
+		Description: "A translation outside min_ratio-max_ratio times the length of an original of at least min_chars",
+		Severity:    SeverityLow,
+		Options:     RuleOptions{"min_ratio": 0.3, "max_ratio": 3.0, "min_chars": 10},
+		NeedsSource: true,
+		Check: func(ctx RuleContext) *Issue {
+			return checkLengthRatio(ctx, ctx.Options.Float("min_ratio", 0.3), ctx.Options.Float("max_ratio", 3.0),
+				ctx.Options.Int("min_chars", 10))
+		},
+	})
+
+	Register(Rule{
+		ID:          RuleArtifacts,
+		IssueType:   "Model Artifacts",
+		Description: "JSON or markdown from the model reply left in the text",
+		Severity:    SeverityHigh,
+		Check:       checkModelArtifacts,
+		Fix:         fixModelArtifacts,
+	})
+}
+
+// limits returns the readability limits of the target language
+func (ctx RuleContext) limits() ReadingLimits {
+	return LimitsFor(ctx.Check.TargetLang)
+}
+
+// maxChars returns the configured or language default characters per line
+func (ctx RuleContext) maxChars() int {
+	return ctx.Options.Int("max_chars", ctx.limits().MaxChars)
+}
+
+// maxLines returns the configured or language default lines per event
+func (ctx RuleContext) maxLines() int {
+	return ctx.Options.Int("max_lines", ctx.limits().MaxLines)
+}
@@ -0,0 +1,138 @@
+package linter
+
+import (
+	"testing"
+)
+
+// TestBuiltinRules tests that the built-in rules are registered in order
+func TestBuiltinRules(t *testing.T) {
+	want := []string{RuleASSTags, RuleBrackets, RuleSourceResidue, RulePunctuation, RuleGlossary}
+	rules := Rules()
+	if len(rules) < len(want) {
+		t.Fatalf("got %d rules, want at least %d", len(rules), len(want))
+	}
+	for i, id := range want {
+		if rules[i].ID != id {
+			t.Errorf("rule %d = %q, want %q", i, rules[i].ID, id)
+		}
+	}
+
+	if _, ok := LookupRule(RulePunctuation); !ok {
+		t.Error("LookupRule should find built-in rules")
+	}
+	if _, ok := LookupRule("no-such-rule"); ok {
+		t.Error("LookupRule should not find unknown rules")
+	}
+}
+
+// TestRegisterDuplicatePanics tests that rule IDs are unique
+func TestRegisterDuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a duplicate ID should panic")
+		}
+	}()
+	Register(Rule{ID: RuleBrackets, Check: func(RuleContext) *Issue { return nil }})
+}
+
+// TestParseSeverity tests config severity parsing
+func TestParseSeverity(t *testing.T) {
+	for input, want := range map[string]Severity{"high": SeverityHigh, "MED": SeverityMedium, "medium": SeverityMedium, " low ": SeverityLow, "off": SeverityOff, "": ""} {
+		got, err := ParseSeverity(input)
+		if err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseSeverity("critical"); err == nil {
+		t.Error("ParseSeverity should reject unknown severities")
+	}
+}
+
+// TestRuleConfigDisables tests turning a rule off
+func TestRuleConfigDisables(t *testing.T) {
+	lines := []string{"Onde está the Nakama?"}
+	opts := CheckOptions{SourceLang: "en", TargetLang: "pt-br"}
+
+	if result := Check(TextLines(lines), opts); len(result.Issues) != 1 || result.Issues[0].RuleID != RuleSourceResidue {
+		t.Fatalf("expected a source residue issue, got %+v", result.Issues)
+	}
+
+	opts.Rules = map[string]RuleConfig{RuleSourceResidue: {Severity: SeverityOff}}
+	if result := Check(TextLines(lines), opts); len(result.Issues) != 0 || !result.PassedAll {
+		t.Errorf("disabled rule should not report, got %+v", result.Issues)
+	}
+}
+
+// TestRuleConfigSeverity tests promoting an advisory rule to HIGH
+func TestRuleConfigSeverity(t *testing.T) {
+	lines := []string{"O Nakama chegou"}
+	opts := CheckOptions{Glossary: map[string]string{"Nakama": "Companheiro"}}
+
+	result := Check(TextLines(lines), opts)
+	if len(result.Issues) != 1 || result.Issues[0].Severity != SeverityLow || !result.PassedAll {
+		t.Fatalf("glossary mismatch should be a LOW warning by default: %+v", result)
+	}
+
+	opts.Rules = map[string]RuleConfig{RuleGlossary: {Severity: SeverityHigh}}
+	result = Check(TextLines(lines), opts)
+	if len(result.Issues) != 1 || result.Issues[0].Severity != SeverityHigh || result.PassedAll {
+		t.Errorf("promoted glossary mismatch should fail as HIGH: %+v", result)
+	}
+}
+
+// TestRuleConfigOptions tests overriding rule options
+func TestRuleConfigOptions(t *testing.T) {
+	lines := []string{"Sério?!", "Kawaii desu"}
+	opts := CheckOptions{SourceLang: "en", TargetLang: "pt-br", Rules: map[string]RuleConfig{
+		RulePunctuation:   {Options: map[string]any{"max_repeat": float64(1)}},
+		RuleSourceResidue: {Options: map[string]any{"words": []any{"kawaii"}}},
+	}}
+
+	result := Check(TextLines(lines), opts)
+	if len(result.Issues) != 2 {
+		t.Fatalf("got %d issues, want punctuation and residue: %+v", len(result.Issues), result.Issues)
+	}
+
+	fixed := AutoFix(TextLines(lines), result.Issues)
+	if fixed[0].Text != "Sério!" {
+		t.Errorf("AutoFix should use the configured max_repeat, got %q", fixed[0].Text)
+	}
+}
+
+// TestInlineSuppression tests {lint-disable} comments
+func TestInlineSuppression(t *testing.T) {
+	opts := CheckOptions{SourceLang: "en", TargetLang: "pt-br"}
+	tests := []struct {
+		line string
+		want int
+	}{
+		{"{lint-disable}What is this?!!!", 0},
+		{"{lint-disable: source-residue}What is this?!!!", 1},
+		{"{lint-disable source-residue, punctuation}What is this?!!!", 0},
+		{"What is this?!!!", 2},
+	}
+
+	for _, tt := range tests {
+		if result := Check(TextLines([]string{tt.line}), opts); len(result.Issues) != tt.want {
+			t.Errorf("Check(%q) found %d issues, want %d: %+v", tt.line, len(result.Issues), tt.want, result.Issues)
+		}
+	}
+}
+
+// TestRuleOptions tests option accessors with JSON-decoded values
+func TestRuleOptions(t *testing.T) {
+	opts := RuleOptions{"n": float64(3), "i": 4, "list": []any{"a", 1, "b"}}
+
+	if got := opts.Int("n", 0); got != 3 {
+		t.Errorf("Int(n) = %d, want 3", got)
+	}
+	if got := opts.Float("i", 0); got != 4 {
+		t.Errorf("Float(i) = %v, want 4", got)
+	}
+	if got := opts.Int("missing", 7); got != 7 {
+		t.Errorf("Int(missing) = %d, want default 7", got)
+	}
+	if got := opts.Strings("list", nil); len(got) != 2 || got[1] != "b" {
+		t.Errorf("Strings(list) = %v, want [a b]", got)
+	}
+}
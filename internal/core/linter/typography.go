@@ -0,0 +1,397 @@
+package linter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// nbsp is the non-breaking space of French punctuation and quotes
+const nbsp = "\u00a0"
+
+// Typography holds the punctuation conventions of a target language
+type Typography struct {
+	Quotes        []string // Opening and closing double quotes, spacing included
+	Ellipsis      string   // "…" or "..."
+	DialogueDash  string   // Prefix of each speaker's line in a dialogue event
+	FrenchSpacing bool     // Non-breaking space before ? ! : ;
+	InvertedMarks bool     // Opening ¿ and ¡ for questions and exclamations
+}
+
+// languageTypography follows common subtitling conventions; languages
+// without an entry are left untouched
+var languageTypography = map[string]Typography{
+	"en": {Quotes: []string{"“", "”"}, Ellipsis: "...", DialogueDash: "- "},
+	"pt": {Quotes: []string{"“", "”"}, Ellipsis: "...", DialogueDash: "- "},
+	"es": {Quotes: []string{"«", "»"}, Ellipsis: "...", DialogueDash: "-", InvertedMarks: true},
+	"fr": {Quotes: []string{"«" + nbsp, nbsp + "»"}, Ellipsis: "…", DialogueDash: "- ", FrenchSpacing: true},
+	"de": {Quotes: []string{"„", "“"}, Ellipsis: "...", DialogueDash: "- "},
+	"it": {Quotes: []string{"«", "»"}, Ellipsis: "...", DialogueDash: "- "},
+}
+
+// TypographyFor returns the typography conventions of a language code
+func TypographyFor(lang string) (Typography, bool) {
+	t, ok := languageTypography[baseLang(lang)]
+	return t, ok
+}
+
+// TypographyRules are the IDs of the rules run by NormalizeTypography
+var TypographyRules = []string{RuleQuotes, RuleEllipsis, RuleDialogueDash, RuleFrenchSpacing, RuleInvertedMarks}
+
+// NormalizeTypography applies the enabled typography rules of the target
+// language to every line, returning the fixed lines and the issues fixed
+func NormalizeTypography(lines []parser.SubtitleLine, opts CheckOptions) ([]parser.SubtitleLine, []Issue) {
+	opts.Only = TypographyRules
+	result := Check(lines, opts)
+	if len(result.Issues) == 0 {
+		return lines, nil
+	}
+	return AutoFix(lines, result.Issues), result.Issues
+}
+
+// typographyRule builds the check of a typography rule from its fix: the
+// line is flagged, auto-fixable, when the fix would change it
+func typographyRule(suggestion string, fix func(RuleContext) string) func(RuleContext) *Issue {
+	return func(ctx RuleContext) *Issue {
+		if fix(ctx) == ctx.Text {
+			return nil
+		}
+		return &Issue{
+			Content:     truncate(ctx.Text, 50),
+			Suggestion:  suggestion,
+			AutoFixable: true,
+		}
+	}
+}
+
+// mapVisible applies f to the text between override blocks and comments
+func mapVisible(text string, f func(string) string) string {
+	var out strings.Builder
+	last := 0
+	for _, loc := range tagBlockPattern.FindAllStringIndex(text, -1) {
+		out.WriteString(f(text[last:loc[0]]))
+		out.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	out.WriteString(f(text[last:]))
+	return out.String()
+}
+
+// quoteMarks are the double quotes recognized in any style
+const quoteMarks = `"“”«»„`
+
+// fixQuotes rewrites double quotes as alternating opening and closing quotes
+// of the given style. Lines with an odd number of quotes are left alone.
+func fixQuotes(text string, quotes []string) string {
+	if len(quotes) != 2 {
+		return text
+	}
+	count := 0
+	mapVisible(text, func(s string) string {
+		for _, r := range s {
+			if strings.ContainsRune(quoteMarks, r) {
+				count++
+			}
+		}
+		return s
+	})
+	if count == 0 || count%2 != 0 {
+		return text
+	}
+
+	open, afterOpening := false, false
+	return mapVisible(text, func(s string) string {
+		var out strings.Builder
+		for _, r := range s {
+			if !strings.ContainsRune(quoteMarks, r) {
+				// Spacing inside the quotes belongs to the style
+				if !afterOpening || (r != ' ' && r != '\u00a0') {
+					out.WriteRune(r)
+					afterOpening = false
+				}
+				continue
+			}
+			open = !open
+			if open {
+				out.WriteString(quotes[0])
+				afterOpening = true
+				continue
+			}
+			trimmed := strings.TrimRight(out.String(), " "+nbsp)
+			out.Reset()
+			out.WriteString(trimmed)
+			out.WriteString(quotes[1])
+		}
+		return out.String()
+	})
+}
+
+// threeDots matches an ellipsis typed as periods
+var threeDots = regexp.MustCompile(`\.{3}`)
+
+// fixEllipsis converts ellipses to the given style
+func fixEllipsis(text, style string) string {
+	return mapVisible(text, func(s string) string {
+		if style == "…" {
+			return threeDots.ReplaceAllString(s, "…")
+		}
+		return strings.ReplaceAll(s, "…", style)
+	})
+}
+
+// dialogueDashPattern matches the speaker dash opening a line, after its override tags
+var dialogueDashPattern = regexp.MustCompile(`^((?:\{[^}]*\})*)[-–—][ \x{00a0}]*`)
+
+// fixDialogueDash rewrites the speaker dashes of a dialogue event
+func fixDialogueDash(text, dash string) string {
+	lines := displayLines(text)
+	dialogue := isDialogue(lines)
+	if !dialogue && len(lines) == 1 {
+		// A lone line is a dialogue when its dash can't be a minus sign
+		visible := strings.TrimSpace(tagBlockPattern.ReplaceAllString(text, ""))
+		dialogue = strings.HasPrefix(visible, "- ") || strings.HasPrefix(visible, "–") || strings.HasPrefix(visible, "—")
+	}
+	if !dialogue {
+		return text
+	}
+
+	breaks := lineBreakPattern.FindAllString(text, -1)
+	var out strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			out.WriteString(breaks[i-1])
+		}
+		out.WriteString(dialogueDashPattern.ReplaceAllString(line, "${1}"+strings.ReplaceAll(dash, "$", "$$")))
+	}
+	return out.String()
+}
+
+// fixFrenchSpacing puts a non-breaking space before ? ! : ; replacing a plain
+// space. Colons of times and URLs (10:30, https://) are left alone.
+func fixFrenchSpacing(text string) string {
+	return mapVisible(text, func(s string) string {
+		runes := []rune(s)
+		var out []rune
+		for i, r := range runes {
+			if !strings.ContainsRune("?!:;", r) || i == 0 {
+				out = append(out, r)
+				continue
+			}
+			prev := runes[i-1]
+			next := rune(0)
+			if i+1 < len(runes) {
+				next = runes[i+1]
+			}
+			switch {
+			case strings.ContainsRune("?!:;\u00a0\u202f", prev):
+			case r == ':' && (unicode.IsDigit(next) || next == '/'):
+			case prev == ' ':
+				out[len(out)-1] = '\u00a0'
+			default:
+				out = append(out, '\u00a0')
+			}
+			out = append(out, r)
+		}
+		return string(out)
+	})
+}
+
+// visibleRune is a rune shown on screen and its byte offset in the text
+type visibleRune struct {
+	r   rune
+	pos int
+}
+
+// visibleRunes lists the on-screen runes of a text; line breaks become spaces
+func visibleRunes(text string) []visibleRune {
+	var runes []visibleRune
+	for i := 0; i < len(text); {
+		if text[i] == '{' {
+			if end := strings.IndexByte(text[i:], '}'); end >= 0 {
+				i += end + 1
+				continue
+			}
+		}
+		if size := lineBreakSize(text[i:]); size > 0 {
+			runes = append(runes, visibleRune{' ', i})
+			i += size
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(text[i:])
+		runes = append(runes, visibleRune{r, i})
+		i += size
+	}
+	return runes
+}
+
+// lineBreakSize returns the length of the line break opening text, 0 if none
+func lineBreakSize(text string) int {
+	switch {
+	case strings.HasPrefix(text, `\N`), strings.HasPrefix(text, `\n`), strings.HasPrefix(text, "\r\n"):
+		return 2
+	case strings.HasPrefix(text, "\n"):
+		return 1
+	}
+	return 0
+}
+
+// invertedMarks maps Spanish closing marks to their opening marks
+var invertedMarks = map[rune]rune{'?': '¿', '!': '¡'}
+
+// fixInvertedMarks opens Spanish questions and exclamations with ¿ and ¡
+func fixInvertedMarks(text string) string {
+	runes := visibleRunes(text)
+	type insertion struct {
+		pos  int
+		mark string
+	}
+	var inserts []insertion
+
+	start := 0
+	for i := 0; i < len(runes); {
+		if !strings.ContainsRune(".?!…", runes[i].r) {
+			i++
+			continue
+		}
+		end := i
+		for end < len(runes) && strings.ContainsRune(".?!…", runes[end].r) {
+			end++
+		}
+
+		// Skip the spacing, dashes and quotes that open the sentence
+		first := start
+		for first < i && strings.ContainsRune(" \u00a0-–—\"“«'", runes[first].r) {
+			first++
+		}
+		sentence := make([]rune, 0, i-first)
+		for _, v := range runes[first:i] {
+			sentence = append(sentence, v.r)
+		}
+
+		if slices.ContainsFunc(sentence, unicode.IsLetter) {
+			// Marks open in the reverse order they close: ¡¿Qué?!
+			var marks []rune
+			for k := end - 1; k >= i; k-- {
+				open, ok := invertedMarks[runes[k].r]
+				if ok && !slices.Contains(sentence, open) && !slices.Contains(marks, open) {
+					marks = append(marks, open)
+				}
+			}
+			if len(marks) > 0 {
+				inserts = append(inserts, insertion{runes[first].pos, string(marks)})
+			}
+		}
+		start = end
+		i = end
+	}
+
+	for i := len(inserts) - 1; i >= 0; i-- {
+		text = text[:inserts[i].pos] + inserts[i].mark + text[inserts[i].pos:]
+	}
+	return text
+}
+
+// Typography rules apply to target languages with conventions in languageTypography
+func init() {
+	Register(Rule{
+		ID:          RuleQuotes,
+		IssueType:   "Quote Style",
+		Description: "Double quotes not in the target language style; quotes overrides the opening and closing marks",
+		Severity:    SeverityLow,
+		Options:     RuleOptions{"quotes": []string{}},
+		Check:       typographyRule("Use the target language quotes", fixQuotesRule),
+		Fix:         fixQuotesRule,
+	})
+
+	Register(Rule{
+		ID:          RuleEllipsis,
+		IssueType:   "Ellipsis Style",
+		Description: "Ellipses typed as ... or … against the target language style (style option)",
+		Severity:    SeverityLow,
+		Options:     RuleOptions{"style": ""},
+		Check:       typographyRule("Use the target language ellipsis", fixEllipsisRule),
+		Fix:         fixEllipsisRule,
+	})
+
+	Register(Rule{
+		ID:          RuleDialogueDash,
+		IssueType:   "Dialogue Dash",
+		Description: "Speaker dashes of a dialogue not in the target language style (dash option)",
+		Severity:    SeverityLow,
+		Options:     RuleOptions{"dash": ""},
+		Check:       typographyRule("Use the target language dialogue dash", fixDialogueDashRule),
+		Fix:         fixDialogueDashRule,
+	})
+
+	Register(Rule{
+		ID:          RuleFrenchSpacing,
+		IssueType:   "French Spacing",
+		Description: "Missing non-breaking space before ? ! : ; in French",
+		Severity:    SeverityLow,
+		Check:       typographyRule("Add a non-breaking space before ? ! : ;", fixFrenchSpacingRule),
+		Fix:         fixFrenchSpacingRule,
+	})
+
+	Register(Rule{
+		ID:          RuleInvertedMarks,
+		IssueType:   "Inverted Marks",
+		Description: "Spanish questions and exclamations without an opening ¿ or ¡",
+		Severity:    SeverityLow,
+		Check:       typographyRule("Open questions with ¿ and exclamations with ¡", fixInvertedMarksRule),
+		Fix:         fixInvertedMarksRule,
+	})
+}
+
+func fixQuotesRule(ctx RuleContext) string {
+	t, ok := TypographyFor(ctx.Check.TargetLang)
+	quotes := ctx.Options.Strings("quotes", nil)
+	if len(quotes) != 2 {
+		if !ok {
+			return ctx.Text
+		}
+		quotes = t.Quotes
+	}
+	return fixQuotes(ctx.Text, quotes)
+}
+
+func fixEllipsisRule(ctx RuleContext) string {
+	t, ok := TypographyFor(ctx.Check.TargetLang)
+	style, _ := ctx.Options["style"].(string)
+	if style == "" {
+		if !ok {
+			return ctx.Text
+		}
+		style = t.Ellipsis
+	}
+	return fixEllipsis(ctx.Text, style)
+}
+
+func fixDialogueDashRule(ctx RuleContext) string {
+	t, ok := TypographyFor(ctx.Check.TargetLang)
+	dash, _ := ctx.Options["dash"].(string)
+	if dash == "" {
+		if !ok {
+			return ctx.Text
+		}
+		dash = t.DialogueDash
+	}
+	return fixDialogueDash(ctx.Text, dash)
+}
+
+func fixFrenchSpacingRule(ctx RuleContext) string {
+	if t, _ := TypographyFor(ctx.Check.TargetLang); !t.FrenchSpacing {
+		return ctx.Text
+	}
+	return fixFrenchSpacing(ctx.Text)
+}
+
+func fixInvertedMarksRule(ctx RuleContext) string {
+	if t, _ := TypographyFor(ctx.Check.TargetLang); !t.InvertedMarks {
+		return ctx.Text
+	}
+	return fixInvertedMarks(ctx.Text)
+}
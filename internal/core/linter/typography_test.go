@@ -0,0 +1,141 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// normalized runs the typography stage on one line
+func normalized(text, lang string, rules map[string]RuleConfig) (string, []Issue) {
+	fixed, issues := NormalizeTypography(TextLines([]string{text}), CheckOptions{TargetLang: lang, Rules: rules})
+	return fixed[0].Text, issues
+}
+
+// TestQuotes tests quote styles per language
+func TestQuotes(t *testing.T) {
+	tests := []struct {
+		text, lang, want string
+	}{
+		{`Ele disse "não" e saiu`, "pt-br", "Ele disse “não” e saiu"},
+		{`Dijo "no" y se fue`, "es", "Dijo «no» y se fue"},
+		{`Il a dit "non"`, "fr", "Il a dit «\u00a0non\u00a0»"},
+		{`Il a dit « non »`, "fr", "Il a dit «\u00a0non\u00a0»"},
+		{`Er sagte "nein"`, "de", "Er sagte „nein“"},
+		{`{\i1}"Olá"{\i0}, disse ele`, "pt-br", `{\i1}“Olá”{\i0}, disse ele`},
+		// Unbalanced quotes can't be paired safely
+		{`Ele disse "não`, "pt-br", `Ele disse "não`},
+		// Languages without conventions are left alone
+		{`Hij zei "nee"`, "nl", `Hij zei "nee"`},
+	}
+
+	for _, tt := range tests {
+		if got, _ := normalized(tt.text, tt.lang, nil); got != tt.want {
+			t.Errorf("%s: %q -> %q, want %q", tt.lang, tt.text, got, tt.want)
+		}
+	}
+
+	rules := map[string]RuleConfig{RuleQuotes: {Options: map[string]any{"quotes": []any{"«", "»"}}}}
+	if got, _ := normalized(`Ele disse "não"`, "pt-br", rules); got != "Ele disse «não»" {
+		t.Errorf("quotes option should override the language style, got %q", got)
+	}
+}
+
+// TestEllipsis tests ellipsis styles
+func TestEllipsis(t *testing.T) {
+	if got, _ := normalized("Bem… talvez", "pt-br", nil); got != "Bem... talvez" {
+		t.Errorf("pt-br should use three periods, got %q", got)
+	}
+	if got, _ := normalized("Eh bien... peut-être", "fr", nil); got != "Eh bien… peut-être" {
+		t.Errorf("fr should use the ellipsis character, got %q", got)
+	}
+
+	rules := map[string]RuleConfig{RuleEllipsis: {Options: map[string]any{"style": "…"}}}
+	if got, _ := normalized("Bem... talvez", "pt-br", rules); got != "Bem… talvez" {
+		t.Errorf("style option should override the language style, got %q", got)
+	}
+
+	// An ellipsis is not excessive punctuation
+	if result := Check(TextLines([]string{"Bem... talvez"}), CheckOptions{TargetLang: "pt-br"}); len(result.Issues) != 0 {
+		t.Errorf("three periods should pass, got %+v", result.Issues)
+	}
+}
+
+// TestDialogueDash tests speaker dash conventions
+func TestDialogueDash(t *testing.T) {
+	tests := []struct {
+		text, lang, want string
+	}{
+		{`–Vamos.\N—Agora?`, "pt-br", `- Vamos.\N- Agora?`},
+		{`- Vamos.\N- ¿Ahora?`, "es", `-Vamos.\N-¿Ahora?`},
+		{"{\\an8}-Allons-y.\n-Maintenant ?", "fr", "{\\an8}- Allons-y.\n- Maintenant\u00a0?"},
+		// A lone minus sign is not a speaker dash
+		{"-5 graus lá fora", "pt-br", "-5 graus lá fora"},
+	}
+
+	for _, tt := range tests {
+		if got, _ := normalized(tt.text, tt.lang, nil); got != tt.want {
+			t.Errorf("%s: %q -> %q, want %q", tt.lang, tt.text, got, tt.want)
+		}
+	}
+}
+
+// TestFrenchSpacing tests non-breaking spaces before high punctuation
+func TestFrenchSpacing(t *testing.T) {
+	tests := map[string]string{
+		"Quoi ? Vraiment!":             "Quoi\u00a0? Vraiment\u00a0!",
+		"Attention : il arrive ; vite": "Attention\u00a0: il arrive\u00a0; vite",
+		"Rendez-vous à 10:30 ?!":       "Rendez-vous à 10:30\u00a0?!",
+		"Voir https://example.com":     "Voir https://example.com",
+	}
+	for text, want := range tests {
+		if got, _ := normalized(text, "fr", nil); got != want {
+			t.Errorf("%q -> %q, want %q", text, got, want)
+		}
+	}
+
+	if got, _ := normalized("Quoi ? Vraiment!", "pt-br", nil); got != "Quoi ? Vraiment!" {
+		t.Errorf("French spacing should only apply to French, got %q", got)
+	}
+}
+
+// TestInvertedMarks tests Spanish opening question and exclamation marks
+func TestInvertedMarks(t *testing.T) {
+	tests := map[string]string{
+		"Qué haces?":               "¿Qué haces?",
+		"Ven aquí! Dónde estabas?": "¡Ven aquí! ¿Dónde estabas?",
+		"Pero, ¿qué haces?":        "Pero, ¿qué haces?",
+		"Qué?!":                    "¡¿Qué?!",
+		`{\i1}-Estás bien?\N-Sí.`:  `{\i1}-¿Estás bien?\N-Sí.`,
+		"Bueno... lo hacemos?":     "Bueno... ¿lo hacemos?",
+		"?!":                       "?!",
+	}
+	for text, want := range tests {
+		if got, _ := normalized(text, "es", nil); got != want {
+			t.Errorf("%q -> %q, want %q", text, got, want)
+		}
+	}
+}
+
+// TestTypographyToggle tests disabling a typography rule per profile
+func TestTypographyToggle(t *testing.T) {
+	rules := map[string]RuleConfig{RuleInvertedMarks: {Severity: SeverityOff}}
+	got, issues := normalized("Qué haces?", "es", rules)
+	if got != "Qué haces?" || len(issues) != 0 {
+		t.Errorf("disabled rule should not fix, got %q %+v", got, issues)
+	}
+
+	_, issues = normalized("Qué haces?", "es", nil)
+	if len(issues) != 1 || issues[0].RuleID != RuleInvertedMarks || !issues[0].AutoFixable || issues[0].Severity != SeverityLow {
+		t.Errorf("expected one auto-fixable LOW issue, got %+v", issues)
+	}
+}
+
+// TestNormalizeTypographyOnly tests that the stage runs no other rules
+func TestNormalizeTypographyOnly(t *testing.T) {
+	lines := []parser.SubtitleLine{{Index: 1, Text: "What is this?!!!"}}
+	fixed, issues := NormalizeTypography(lines, CheckOptions{SourceLang: "en", TargetLang: "pt-br"})
+	if len(issues) != 0 || fixed[0].Text != lines[0].Text {
+		t.Errorf("only typography rules should run, got %+v", issues)
+	}
+}
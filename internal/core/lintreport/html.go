@@ -0,0 +1,94 @@
+package lintreport
+
+import (
+	"html/template"
+	"io"
+	"strings"
+)
+
+// htmlLine groups the entries of one line for the side-by-side view
+type htmlLine struct {
+	Line    int
+	Start   string
+	End     string
+	Source  string
+	Target  string
+	Entries []Entry
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Lint report: {{.Report.File}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+h1 { font-size: 1.4rem; }
+.summary span { display: inline-block; margin-right: 1.5rem; }
+table { border-collapse: collapse; width: 100%; margin-top: 1rem; }
+th, td { border: 1px solid #ddd; padding: .4rem .6rem; vertical-align: top; text-align: left; }
+th { background: #f4f4f4; }
+td.text { white-space: pre-wrap; width: 30%; }
+td.time { white-space: nowrap; font-family: monospace; }
+ul { margin: 0; padding-left: 1.1rem; }
+.high { color: #b00020; font-weight: bold; }
+.med { color: #c66900; font-weight: bold; }
+.low { color: #555; }
+.fixed { color: #2e7d32; }
+del { color: #888; }
+</style>
+</head>
+<body>
+<h1>Lint report: {{.Report.File}}</h1>
+<p>{{.Report.SourceLang}} &rarr; {{.Report.TargetLang}} &middot; {{.Report.TotalLines}} lines &middot; {{.Report.CreatedAt.Format "2006-01-02 15:04"}}</p>
+<p class="summary">
+<span class="high">HIGH: {{.Summary.High}}</span>
+<span class="med">MED: {{.Summary.Medium}}</span>
+<span class="low">LOW: {{.Summary.Low}}</span>
+<span class="fixed">Fixed: {{.Summary.Fixed}}</span>
+<span>Lines flagged: {{.Summary.Lines}}</span>
+</p>
+{{if .Lines}}<table>
+<thead><tr><th>#</th><th>Time</th><th>Source</th><th>Target</th><th>Issues</th></tr></thead>
+<tbody>
+{{range .Lines}}<tr>
+<td>{{.Line}}</td>
+<td class="time">{{.Start}}<br>{{.End}}</td>
+<td class="text">{{.Source}}</td>
+<td class="text">{{.Target}}</td>
+<td><ul>{{range .Entries}}
+<li>{{if .Fixed}}<span class="fixed">FIXED</span>{{else}}<span class="{{lower .Severity}}">{{.Severity}}</span>{{end}} <code>{{.Rule}}</code> {{.Message}}{{if .Fixed}}<br><del>{{.Before}}</del>{{end}}</li>{{end}}
+</ul></td>
+</tr>
+{{end}}</tbody>
+</table>{{else}}<p>No issues found.</p>{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes a static page showing each flagged line next to its source
+func WriteHTML(w io.Writer, r *Report) error {
+	var lines []htmlLine
+	for _, e := range r.Entries {
+		if n := len(lines); n > 0 && lines[n-1].Line == e.Line {
+			lines[n-1].Entries = append(lines[n-1].Entries, e)
+			continue
+		}
+		lines = append(lines, htmlLine{
+			Line:    e.Line,
+			Start:   e.Start,
+			End:     e.End,
+			Source:  e.Source,
+			Target:  e.Target,
+			Entries: []Entry{e},
+		})
+	}
+
+	return htmlTemplate.Execute(w, struct {
+		Report  *Report
+		Summary Summary
+		Lines   []htmlLine
+	}{r, r.Summary(), lines})
+}
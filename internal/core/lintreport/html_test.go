@@ -0,0 +1,49 @@
+package lintreport
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+)
+
+// TestWriteHTML tests the side-by-side page and escaping
+func TestWriteHTML(t *testing.T) {
+	r := testReport()
+	r.Entries[0].Target = "<b>Where</b> are you going?"
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, r); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	page := buf.String()
+
+	for _, want := range []string{
+		"Lint report: episode.mkv",
+		"HIGH: 1",
+		"Fixed: 1",
+		"Where are you going?",
+		"&lt;b&gt;Where&lt;/b&gt;",
+		"<code>untranslated</code>",
+		"<del>**Vamos** embora.</del>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page should contain %q", want)
+		}
+	}
+	if strings.Contains(page, "<b>Where</b>") {
+		t.Error("subtitle text should be escaped")
+	}
+}
+
+// TestWriteHTMLEmpty tests a report without issues
+func TestWriteHTMLEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, New("clean.mkv", "en", "pt-br", nil, nil, []linter.Issue{})); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No issues found.") {
+		t.Error("an empty report should say so")
+	}
+}
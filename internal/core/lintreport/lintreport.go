@@ -0,0 +1,265 @@
+// Package lintreport builds per-file reports of the quality gate results and
+// exports them as JSON, CSV or a static HTML page.
+package lintreport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// Format identifies a report file format
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatHTML Format = "html"
+	FormatCSV  Format = "csv"
+)
+
+// Formats lists every report format
+var Formats = []Format{FormatJSON, FormatHTML, FormatCSV}
+
+// ParseFormat converts a user value to a format
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "."))) {
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatHTML, "htm":
+		return FormatHTML, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unknown lint report format %q (use json, html or csv)", s)
+	}
+}
+
+// ParseFormats converts a list of user values, skipping duplicates
+func ParseFormats(values []string) ([]Format, error) {
+	var formats []Format
+	for _, v := range values {
+		format, err := ParseFormat(v)
+		if err != nil {
+			return nil, err
+		}
+		if !containsFormat(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	return formats, nil
+}
+
+// containsFormat reports whether formats includes format
+func containsFormat(formats []Format, format Format) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Path returns where the report of a subtitle or video file is written:
+// next to it, as NAME.lint.FORMAT
+func Path(file string, format Format) string {
+	return strings.TrimSuffix(file, filepath.Ext(file)) + ".lint." + string(format)
+}
+
+// Entry is one issue found on a line, or one fix applied to it
+type Entry struct {
+	Line     int    `json:"line"` // 1-based position in the file
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Rule     string `json:"rule"`
+	Type     string `json:"type"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Fixed    bool   `json:"fixed"`            // The linter repaired the issue
+	Before   string `json:"before,omitempty"` // Target text before the fix
+}
+
+// Summary counts the entries of a report
+type Summary struct {
+	Lines  int `json:"lines"` // Lines with at least one entry
+	High   int `json:"high"`  // Open issues per severity
+	Medium int `json:"med"`
+	Low    int `json:"low"`
+	Fixed  int `json:"fixed"`
+}
+
+// Report holds the quality gate results of one subtitle file
+type Report struct {
+	File       string    `json:"file"`
+	SourceLang string    `json:"source_lang"`
+	TargetLang string    `json:"target_lang"`
+	CreatedAt  time.Time `json:"created_at"`
+	TotalLines int       `json:"total_lines"`
+	Entries    []Entry   `json:"entries"`
+
+	sources []parser.SubtitleLine
+	lines   []parser.SubtitleLine
+}
+
+// New creates a report with an entry per open issue. Issue LineIDs are the
+// 1-based positions in lines; sources may be nil.
+func New(file, sourceLang, targetLang string, sources, lines []parser.SubtitleLine, issues []linter.Issue) *Report {
+	r := &Report{
+		File:       file,
+		SourceLang: sourceLang,
+		TargetLang: targetLang,
+		CreatedAt:  time.Now(),
+		TotalLines: len(lines),
+		Entries:    []Entry{},
+		sources:    sources,
+		lines:      lines,
+	}
+	for _, issue := range issues {
+		r.Entries = append(r.Entries, r.entry(issue.LineID, issue))
+	}
+	r.sort()
+	return r
+}
+
+// AddFix records a fix applied to a line (1-based); before is the target
+// text the fix started from
+func (r *Report) AddFix(line int, issue linter.Issue, before string) {
+	entry := r.entry(line, issue)
+	entry.Fixed = true
+	entry.Before = before
+	r.Entries = append(r.Entries, entry)
+	r.sort()
+}
+
+// entry describes an issue of a line
+func (r *Report) entry(line int, issue linter.Issue) Entry {
+	e := Entry{
+		Line:     line,
+		Rule:     issue.RuleID,
+		Type:     issue.IssueType,
+		Severity: string(issue.Severity),
+		Message:  issue.Suggestion,
+	}
+	if i := line - 1; i >= 0 && i < len(r.lines) {
+		e.Start = r.lines[i].StartTime
+		e.End = r.lines[i].EndTime
+		e.Target = r.lines[i].Text
+	}
+	if i := line - 1; i >= 0 && i < len(r.sources) {
+		e.Source = r.sources[i].Text
+	}
+	return e
+}
+
+// sort orders entries by line, open issues first
+func (r *Report) sort() {
+	sort.SliceStable(r.Entries, func(i, j int) bool {
+		if r.Entries[i].Line != r.Entries[j].Line {
+			return r.Entries[i].Line < r.Entries[j].Line
+		}
+		return !r.Entries[i].Fixed && r.Entries[j].Fixed
+	})
+}
+
+// Summary counts open issues per severity and applied fixes
+func (r *Report) Summary() Summary {
+	var s Summary
+	lines := make(map[int]bool)
+	for _, e := range r.Entries {
+		lines[e.Line] = true
+		switch {
+		case e.Fixed:
+			s.Fixed++
+		case e.Severity == string(linter.SeverityHigh):
+			s.High++
+		case e.Severity == string(linter.SeverityMedium):
+			s.Medium++
+		default:
+			s.Low++
+		}
+	}
+	s.Lines = len(lines)
+	return s
+}
+
+// WriteJSON writes the report with its summary as indented JSON
+func WriteJSON(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		*Report
+		Summary Summary `json:"summary"`
+	}{r, r.Summary()})
+}
+
+// csvColumns is the header written to CSV reports
+var csvColumns = []string{
+	"line", "start", "end", "source", "target", "rule", "type", "severity", "message", "fixed", "before",
+}
+
+// WriteCSV writes one row per entry with a header row
+func WriteCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvColumns); err != nil {
+		return err
+	}
+
+	for _, e := range r.Entries {
+		if err := cw.Write([]string{
+			strconv.Itoa(e.Line), e.Start, e.End, e.Source, e.Target,
+			e.Rule, e.Type, e.Severity, e.Message, strconv.FormatBool(e.Fixed), e.Before,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Write writes the report in the given format
+func Write(w io.Writer, r *Report, format Format) error {
+	switch format {
+	case FormatHTML:
+		return WriteHTML(w, r)
+	case FormatCSV:
+		return WriteCSV(w, r)
+	default:
+		return WriteJSON(w, r)
+	}
+}
+
+// Export writes the report to path. An empty format is derived from the file extension.
+func Export(r *Report, path string, format Format) error {
+	if format == "" {
+		var err error
+		if format, err = ParseFormat(filepath.Ext(path)); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	err = Write(f, r, format)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package lintreport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// testReport builds a report with one open issue and one fix
+func testReport() *Report {
+	sources := []parser.SubtitleLine{
+		{Index: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Text: "Where are you going?"},
+		{Index: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Text: "Let's go."},
+	}
+	lines := []parser.SubtitleLine{
+		{Index: 1, StartTime: "00:00:01,000", EndTime: "00:00:02,000", Text: "Where are you going?"},
+		{Index: 2, StartTime: "00:00:03,000", EndTime: "00:00:04,000", Text: "Vamos embora."},
+	}
+	issues := []linter.Issue{{LineID: 1, RuleID: linter.RuleUntranslated, IssueType: "Untranslated", Severity: linter.SeverityHigh, Suggestion: "Line was left untranslated"}}
+
+	r := New("episode.mkv", "en", "pt-br", sources, lines, issues)
+	r.AddFix(2, linter.Issue{RuleID: linter.RuleArtifacts, IssueType: "Model Artifacts", Severity: linter.SeverityHigh, Suggestion: "Markdown left by the model"}, "**Vamos** embora.")
+	return r
+}
+
+// TestParseFormats tests format names and duplicates
+func TestParseFormats(t *testing.T) {
+	formats, err := ParseFormats([]string{"JSON", ".html", "csv", "json"})
+	if err != nil || len(formats) != 3 || formats[1] != FormatHTML {
+		t.Errorf("ParseFormats = %v, %v", formats, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if got := Path("/media/ep01.mkv", FormatHTML); got != "/media/ep01.lint.html" {
+		t.Errorf("Path = %q", got)
+	}
+}
+
+// TestReportEntries tests timing, texts, fixes and the summary
+func TestReportEntries(t *testing.T) {
+	r := testReport()
+	if len(r.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(r.Entries))
+	}
+
+	open := r.Entries[0]
+	if open.Line != 1 || open.Start != "00:00:01,000" || open.Source != "Where are you going?" || open.Fixed {
+		t.Errorf("unexpected open entry: %+v", open)
+	}
+	fixed := r.Entries[1]
+	if !fixed.Fixed || fixed.Before != "**Vamos** embora." || fixed.Target != "Vamos embora." {
+		t.Errorf("unexpected fix entry: %+v", fixed)
+	}
+
+	if s := r.Summary(); s.High != 1 || s.Fixed != 1 || s.Lines != 2 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+}
+
+// TestWriteJSON tests the JSON layout
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testReport()); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	var decoded struct {
+		File    string  `json:"file"`
+		Entries []Entry `json:"entries"`
+		Summary Summary `json:"summary"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded.File != "episode.mkv" || len(decoded.Entries) != 2 || decoded.Summary.High != 1 {
+		t.Errorf("unexpected JSON: %s", buf.String())
+	}
+}
+
+// TestWriteCSV tests the header and one row per entry
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testReport()); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 3 || records[0][0] != "line" || records[2][9] != "true" || records[2][10] != "**Vamos** embora." {
+		t.Errorf("unexpected CSV: %v", records)
+	}
+}
+
+// TestExport tests writing a report with the format taken from the extension
+func TestExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "episode.lint.csv")
+	if err := Export(testReport(), path, ""); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.HasPrefix(data, []byte("line,start,end")) {
+		t.Errorf("expected a CSV file, got %q", data)
+	}
+
+	if err := Export(testReport(), filepath.Join(t.TempDir(), "report.txt"), ""); err == nil {
+		t.Error("expected an error for an unknown extension")
+	}
+}
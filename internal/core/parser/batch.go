@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Batching strategies
+const (
+	BatchStrategyFixed  = "fixed"  // Every N lines
+	BatchStrategyTokens = "tokens" // Fill a token budget
+	BatchStrategyScene  = "scene"  // Fill a token budget, cutting at scene boundaries
+)
+
+// DefaultSceneGap is the silence between lines treated as a scene change
+const DefaultSceneGap = 3 * time.Second
+
+// Batcher groups subtitle lines into translation batches
+type Batcher interface {
+	Batch(lines []SubtitleLine) [][]SubtitleLine
+}
+
+// FixedBatcher cuts every Size lines
+type FixedBatcher struct {
+	Size int
+}
+
+// Batch implements Batcher
+func (b FixedBatcher) Batch(lines []SubtitleLine) [][]SubtitleLine {
+	return BatchLines(lines, b.Size)
+}
+
+// TokenBatcher fills each batch up to MaxTokens
+type TokenBatcher struct {
+	MaxTokens int
+	MaxLines  int // 0 = no line cap
+	Count     func(string) int
+}
+
+// Batch implements Batcher
+func (b TokenBatcher) Batch(lines []SubtitleLine) [][]SubtitleLine {
+	return BatchLinesByTokens(lines, b.MaxTokens, b.MaxLines, b.Count)
+}
+
+// SceneBatcher fills batches up to MaxTokens but, when a batch is full, cuts
+// it at the strongest scene boundary seen since it was at least MinFill full,
+// so conversations are not split across requests.
+type SceneBatcher struct {
+	MaxTokens int
+	MaxLines  int // 0 = no line cap
+	Count     func(string) int
+	SceneGap  time.Duration // 0 = DefaultSceneGap
+	MinFill   float64       // Fraction of MaxTokens a batch must reach before an early cut (0 = 0.5)
+}
+
+// Batch implements Batcher
+func (b SceneBatcher) Batch(lines []SubtitleLine) [][]SubtitleLine {
+	batches := [][]SubtitleLine{}
+	if len(lines) == 0 {
+		return batches
+	}
+
+	counts := make([]int, len(lines))
+	for i, line := range lines {
+		counts[i] = b.Count(line.Text)
+	}
+
+	start := 0
+	tokens := 0
+	for i := range lines {
+		if i > start && b.overflows(tokens+counts[i], i-start) {
+			cut := b.bestCut(lines, counts, start, i)
+			batches = append(batches, lines[start:cut])
+			start = cut
+
+			tokens = 0
+			for _, c := range counts[cut:i] {
+				tokens += c
+			}
+
+			// Carried-over lines plus this one may still not fit
+			if i > start && b.overflows(tokens+counts[i], i-start) {
+				batches = append(batches, lines[start:i])
+				start = i
+				tokens = 0
+			}
+		}
+		tokens += counts[i]
+	}
+
+	return append(batches, lines[start:])
+}
+
+// overflows reports whether a batch of n lines and tokens exceeds the limits
+func (b SceneBatcher) overflows(tokens, n int) bool {
+	return tokens > b.MaxTokens || (b.MaxLines > 0 && n >= b.MaxLines)
+}
+
+// bestCut picks where to end the batch lines[start:end].
+// Candidates are the boundaries before lines start+1..end once the batch
+// holds MinFill of the budget; the strongest boundary wins, later on ties.
+func (b SceneBatcher) bestCut(lines []SubtitleLine, counts []int, start, end int) int {
+	minFill := b.MinFill
+	if minFill <= 0 || minFill > 1 {
+		minFill = 0.5
+	}
+	minTokens := int(float64(b.MaxTokens) * minFill)
+
+	filled := 0
+	for _, c := range counts[start:end] {
+		filled += c
+	}
+
+	// Walk backwards so the latest boundary wins ties
+	best := end
+	bestStrength := b.boundaryStrength(lines[end-1], lines[end])
+	for k := end - 1; k > start; k-- {
+		filled -= counts[k]
+		if filled < minTokens {
+			break
+		}
+		if strength := b.boundaryStrength(lines[k-1], lines[k]); strength > bestStrength {
+			best = k
+			bestStrength = strength
+		}
+	}
+
+	return best
+}
+
+// boundaryStrength scores the boundary between two consecutive lines:
+// 2 for a timing gap of at least SceneGap, 1 for a style change, 0 otherwise
+func (b SceneBatcher) boundaryStrength(prev, next SubtitleLine) int {
+	gap := b.SceneGap
+	if gap <= 0 {
+		gap = DefaultSceneGap
+	}
+
+	prevEnd, okPrev := ParseTimestamp(prev.EndTime)
+	nextStart, okNext := ParseTimestamp(next.StartTime)
+	if okPrev && okNext && nextStart-prevEnd >= gap {
+		return 2
+	}
+
+	if prev.Style != "" && next.Style != "" && prev.Style != next.Style {
+		return 1
+	}
+
+	return 0
+}
+
+// ParseTimestamp parses ASS ("0:01:02.50") and SRT ("00:01:02,500") timestamps
+func ParseTimestamp(ts string) (time.Duration, bool) {
+	parts := strings.Split(strings.TrimSpace(strings.Replace(ts, ",", ".", 1)), ":")
+	if len(parts) != 3 {
+		return 0, false
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, false
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	total := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second))
+	return total, true
+}
@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// sceneLines builds lines of 10 tokens each, one second apart, with a long
+// gap before the given indices
+func sceneLines(n int, gapsBefore ...int) []SubtitleLine {
+	gap := map[int]bool{}
+	for _, i := range gapsBefore {
+		gap[i] = true
+	}
+
+	lines := make([]SubtitleLine, n)
+	offset := 0
+	for i := range lines {
+		if gap[i] {
+			offset += 10
+		}
+		start := i + offset
+		lines[i] = SubtitleLine{
+			Index:     i,
+			StartTime: fmt.Sprintf("0:00:%02d.00", start),
+			EndTime:   fmt.Sprintf("0:00:%02d.90", start),
+			Text:      "0123456789",
+			Style:     "Default",
+		}
+	}
+	return lines
+}
+
+func batchSizes(batches [][]SubtitleLine) []int {
+	sizes := make([]int, len(batches))
+	for i, b := range batches {
+		sizes[i] = len(b)
+	}
+	return sizes
+}
+
+func countChars(s string) int { return len(s) }
+
+func TestSceneBatcherCutsAtGap(t *testing.T) {
+	// Budget fits 8 lines; a scene change before line 6 should end the first batch there
+	lines := sceneLines(12, 6)
+	b := SceneBatcher{MaxTokens: 80, Count: countChars}
+
+	got := fmt.Sprint(batchSizes(b.Batch(lines)))
+	if got != "[6 6]" {
+		t.Errorf("expected batches [6 6], got %s", got)
+	}
+}
+
+func TestSceneBatcherIgnoresEarlyGap(t *testing.T) {
+	// A gap before line 2 leaves the batch under MinFill, so it is not used
+	lines := sceneLines(12, 2)
+	b := SceneBatcher{MaxTokens: 80, Count: countChars}
+
+	got := fmt.Sprint(batchSizes(b.Batch(lines)))
+	if got != "[8 4]" {
+		t.Errorf("expected batches [8 4], got %s", got)
+	}
+}
+
+func TestSceneBatcherStyleChange(t *testing.T) {
+	lines := sceneLines(12)
+	for i := 5; i < len(lines); i++ {
+		lines[i].Style = "Flashback"
+	}
+	b := SceneBatcher{MaxTokens: 80, Count: countChars}
+
+	got := fmt.Sprint(batchSizes(b.Batch(lines)))
+	if got != "[5 7]" {
+		t.Errorf("expected batches [5 7], got %s", got)
+	}
+}
+
+func TestSceneBatcherLineCap(t *testing.T) {
+	lines := sceneLines(10)
+	b := SceneBatcher{MaxTokens: 1000, MaxLines: 4, Count: countChars}
+
+	got := fmt.Sprint(batchSizes(b.Batch(lines)))
+	if got != "[4 4 2]" {
+		t.Errorf("expected batches [4 4 2], got %s", got)
+	}
+
+	if len(b.Batch(nil)) != 0 {
+		t.Error("expected no batches for empty input")
+	}
+}
+
+func TestBatchersPreserveLines(t *testing.T) {
+	lines := sceneLines(25, 7, 15)
+	batchers := map[string]Batcher{
+		BatchStrategyFixed:  FixedBatcher{Size: 10},
+		BatchStrategyTokens: TokenBatcher{MaxTokens: 60, Count: countChars},
+		BatchStrategyScene:  SceneBatcher{MaxTokens: 60, Count: countChars},
+	}
+
+	for name, b := range batchers {
+		next := 0
+		for _, batch := range b.Batch(lines) {
+			for _, line := range batch {
+				if line.Index != next {
+					t.Fatalf("%s: expected line %d, got %d", name, next, line.Index)
+				}
+				next++
+			}
+		}
+		if next != len(lines) {
+			t.Errorf("%s: expected %d lines, got %d", name, len(lines), next)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+		ok    bool
+	}{
+		{"0:01:02.50", time.Minute + 2500*time.Millisecond, true},
+		{"00:01:02,500", time.Minute + 2500*time.Millisecond, true},
+		{"1:00:00.00", time.Hour, true},
+		{"", 0, false},
+		{"garbage", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseTimestamp(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseTimestamp(%q) = %v, %v; want %v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -16,7 +16,9 @@ type SubtitleLine struct {
 	Text       string
 	Style      string
 	OriginalID int
+	Speaker    string // Who speaks: the ASS Name field or a detected NAME: label
 	// ASS-specific fields
+	Name     string // Name (actor) field
 	Layer    int
 	MarginL  int
 	MarginR  int
@@ -97,6 +99,7 @@ func parseASS(path string) (*SubtitleFile, error) {
 						StartTime:  strings.TrimSpace(parts[1]),
 						EndTime:    strings.TrimSpace(parts[2]),
 						Style:      strings.TrimSpace(parts[3]),
+						Name:       strings.TrimSpace(parts[4]),
 						Text:       strings.TrimSpace(parts[9]), // Text is last field
 						RawEvent:   line,
 						OriginalID: lineIndex,
@@ -235,6 +238,23 @@ func RemoveHearingImpairedTags(text string) string {
 	return strings.TrimSpace(text)
 }
 
+// speakerLabel matches a speaker label at the start of a line: "NARUTO:",
+// "- Dr. Smith:", up to three words
+var speakerLabel = regexp.MustCompile(`^(?:\{[^}]*\})*-?\s*(\p{Lu}[\p{L}'.]*(?:[ ]\p{Lu}[\p{L}'.]*){0,2})\s*:\s+\S`)
+
+// SpeakerLabel returns the speaker label the text starts with, or "".
+// Labels are matched on the first line only and include mixed-case words
+// ("Note:"), so callers decide which labels name a speaker.
+func SpeakerLabel(text string) string {
+	first, _, _ := strings.Cut(text, "\\N")
+	first, _, _ = strings.Cut(first, "\n")
+	if m := speakerLabel.FindStringSubmatch(first); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// BatchLines splits lines into batches of at most size lines
 func BatchLines(lines []SubtitleLine, size int) [][]SubtitleLine {
 	batches := [][]SubtitleLine{}
 	for i := 0; i < len(lines); i += size {
@@ -247,6 +267,31 @@ func BatchLines(lines []SubtitleLine, size int) [][]SubtitleLine {
 	return batches
 }
 
+// BatchLinesByTokens splits lines into batches whose text stays within
+// maxTokens as measured by count. A line larger than the budget gets a batch
+// of its own; maxLines > 0 additionally caps the number of lines per batch.
+func BatchLinesByTokens(lines []SubtitleLine, maxTokens, maxLines int, count func(string) int) [][]SubtitleLine {
+	batches := [][]SubtitleLine{}
+	start := 0
+	tokens := 0
+
+	for i, line := range lines {
+		lineTokens := count(line.Text)
+		full := maxLines > 0 && i-start >= maxLines
+		if i > start && (tokens+lineTokens > maxTokens || full) {
+			batches = append(batches, lines[start:i])
+			start = i
+			tokens = 0
+		}
+		tokens += lineTokens
+	}
+
+	if start < len(lines) {
+		batches = append(batches, lines[start:])
+	}
+	return batches
+}
+
 // ReassembleASS reconstructs an ASS file from header and translated lines
 func ReassembleASS(header string, lines []SubtitleLine) string {
 	var sb strings.Builder
@@ -256,11 +301,12 @@ func ReassembleASS(header string, lines []SubtitleLine) string {
 	for _, line := range lines {
 		// Reconstruct Dialogue line
 		// Format: Dialogue: Layer,Start,End,Style,Name,MarginL,MarginR,MarginV,Effect,Text
-		sb.WriteString(fmt.Sprintf("Dialogue: %d,%s,%s,%s,,%04d,%04d,%04d,%s,%s\n",
+		sb.WriteString(fmt.Sprintf("Dialogue: %d,%s,%s,%s,%s,%04d,%04d,%04d,%s,%s\n",
 			line.Layer,
 			line.StartTime,
 			line.EndTime,
 			line.Style,
+			line.Name,
 			line.MarginL,
 			line.MarginR,
 			line.MarginV,
@@ -74,7 +74,7 @@ Style: Default,Arial,48,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,
 [Events]
 Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
 Dialogue: 0,0:00:01.00,0:00:04.00,Default,,0000,0000,0000,,Hello, world!
-Dialogue: 0,0:00:05.00,0:00:08.00,Default,,0000,0000,0000,,{\an8}Top positioned text
+Dialogue: 0,0:00:05.00,0:00:08.00,Default,Naruto,0000,0000,0000,,{\an8}Top positioned text
 `
 
 	tmpDir := t.TempDir()
@@ -112,6 +112,10 @@ Dialogue: 0,0:00:05.00,0:00:08.00,Default,,0000,0000,0000,,{\an8}Top positioned
 	if !strings.Contains(sf.Lines[1].Text, "{\\an8}") {
 		t.Error("ASS tags should be preserved")
 	}
+
+	if sf.Lines[0].Name != "" || sf.Lines[1].Name != "Naruto" {
+		t.Errorf("unexpected names: %q, %q", sf.Lines[0].Name, sf.Lines[1].Name)
+	}
 }
 
 func TestParseFileNotFound(t *testing.T) {
@@ -184,6 +188,24 @@ func TestRemoveHearingImpairedTags(t *testing.T) {
 	}
 }
 
+func TestSpeakerLabel(t *testing.T) {
+	tests := map[string]string{
+		"NARUTO: Believe it!":                "NARUTO",
+		"- Dr. Smith: Sit down.":             "Dr. Smith",
+		"{\\an8}OLD MAN: Wait.\\N- Who?":     "OLD MAN",
+		"Note: this is it.":                  "Note",
+		"It's 10:30 already.":                "",
+		"Hello world: not a label here":      "",
+		"Naruto:":                            "",
+		"Hey!\\NSASUKE: Not the first line.": "",
+	}
+	for text, want := range tests {
+		if got := SpeakerLabel(text); got != want {
+			t.Errorf("SpeakerLabel(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
 func TestBatchLines(t *testing.T) {
 	lines := []SubtitleLine{
 		{Index: 0, Text: "Line 1"},
@@ -277,11 +299,16 @@ Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
 			MarginV:   0,
 			Effect:    "",
 			Text:      "Hello, world!",
+			Name:      "Naruto",
 		},
 	}
 
 	result := ReassembleASS(header, lines)
 
+	if !strings.Contains(result, ",Default,Naruto,0000,") {
+		t.Errorf("should keep the Name field: %s", result)
+	}
+
 	if !strings.Contains(result, "Dialogue:") {
 		t.Error("should contain Dialogue line")
 	}
@@ -421,3 +448,40 @@ func TestReassembleSRTRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestBatchLinesByTokens(t *testing.T) {
+	lines := []SubtitleLine{
+		{Index: 0, Text: "aaaa"},
+		{Index: 1, Text: "bbbb"},
+		{Index: 2, Text: "cc"},
+		{Index: 3, Text: "dddddddddddd"}, // Larger than the budget
+		{Index: 4, Text: "e"},
+	}
+	count := func(s string) int { return len(s) }
+
+	batches := BatchLinesByTokens(lines, 10, 0, count)
+	sizes := []int{}
+	for _, b := range batches {
+		sizes = append(sizes, len(b))
+	}
+
+	want := []int{3, 1, 1}
+	if len(sizes) != len(want) {
+		t.Fatalf("expected batch sizes %v, got %v", want, sizes)
+	}
+	for i := range want {
+		if sizes[i] != want[i] {
+			t.Fatalf("expected batch sizes %v, got %v", want, sizes)
+		}
+	}
+
+	// Line cap applies on top of the token budget
+	capped := BatchLinesByTokens(lines[:3], 100, 2, count)
+	if len(capped) != 2 || len(capped[0]) != 2 {
+		t.Errorf("expected line cap of 2, got %d batches", len(capped))
+	}
+
+	if len(BatchLinesByTokens(nil, 10, 0, count)) != 0 {
+		t.Error("expected no batches for empty input")
+	}
+}
@@ -0,0 +1,157 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+)
+
+// ErrBudgetExceeded is returned when a spending limit stops the pipeline
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Budget scopes
+const (
+	BudgetScopeJob   = "job"
+	BudgetScopeDay   = "day"
+	BudgetScopeMonth = "month"
+)
+
+// BudgetStatus describes the state of one spending limit
+type BudgetStatus struct {
+	Scope     string  // job, day or month
+	Limit     float64 // USD
+	Spent     float64 // USD already spent in this scope
+	Projected float64 // USD the next batch is expected to cost
+}
+
+// Total returns spent + projected cost
+func (s BudgetStatus) Total() float64 {
+	return s.Spent + s.Projected
+}
+
+// String formats the status for logs
+func (s BudgetStatus) String() string {
+	return fmt.Sprintf("%s limit $%.4f (spent $%.4f + next batch ~$%.4f)", s.Scope, s.Limit, s.Spent, s.Projected)
+}
+
+// BudgetGuard tracks spending against configured limits.
+// A single guard is shared by all files of a job. With a cache, the day and
+// month spending is re-read from the usage log before every check, so jobs
+// running concurrently or back to back (watch mode) share those limits.
+type BudgetGuard struct {
+	limits     config.BudgetLimits
+	cache      *db.Cache
+	jobSpent   float64
+	daySpent   float64 // Includes spending recorded by other jobs
+	monthSpent float64 // Includes spending recorded by other jobs
+	warned     map[string]bool
+	approved   map[string]bool // Scopes the user allowed to go over the limit
+	mu         sync.Mutex
+}
+
+// NewBudgetGuard creates a guard, loading today's and this month's spending from the cache
+func NewBudgetGuard(limits config.BudgetLimits, cache *db.Cache) *BudgetGuard {
+	g := &BudgetGuard{
+		limits:   limits,
+		cache:    cache,
+		warned:   make(map[string]bool),
+		approved: make(map[string]bool),
+	}
+	g.refresh()
+	return g
+}
+
+// refresh reloads today's and this month's spending from the usage log,
+// which includes this job's requests. Without a cache, or when the log
+// can't be read, the spending tracked in memory is kept.
+func (g *BudgetGuard) refresh() {
+	if g.cache == nil {
+		return
+	}
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if day, err := g.cache.GetUsageSummary(startOfDay, startOfDay.AddDate(0, 0, 1)); err == nil {
+		g.daySpent = day.Cost
+	}
+	if month, err := g.cache.GetMonthlyUsage(now); err == nil {
+		g.monthSpent = month.Cost
+	}
+}
+
+// Enabled reports whether any limit is configured
+func (g *BudgetGuard) Enabled() bool {
+	return g.limits.PerJob > 0 || g.limits.PerDay > 0 || g.limits.PerMonth > 0
+}
+
+// Add records spending in all scopes
+func (g *BudgetGuard) Add(cost float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.jobSpent += cost
+	g.daySpent += cost
+	g.monthSpent += cost
+}
+
+// JobSpent returns the spending recorded for the job so far
+func (g *BudgetGuard) JobSpent() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.jobSpent
+}
+
+// Approve allows the job to continue past the limit of the given scope
+func (g *BudgetGuard) Approve(scope string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.approved[scope] = true
+}
+
+// Check evaluates the limits for a batch expected to cost projected USD.
+// It returns scopes that just crossed the warning threshold (each reported once)
+// and the first scope that would be exceeded, if any.
+func (g *BudgetGuard) Check(projected float64) (warnings []BudgetStatus, exceeded *BudgetStatus) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.limits.PerDay > 0 || g.limits.PerMonth > 0 {
+		g.refresh()
+	}
+
+	threshold := g.limits.WarnThreshold
+	if threshold <= 0 || threshold > 1 {
+		threshold = 0.8
+	}
+
+	scopes := []BudgetStatus{
+		{Scope: BudgetScopeJob, Limit: g.limits.PerJob, Spent: g.jobSpent, Projected: projected},
+		{Scope: BudgetScopeDay, Limit: g.limits.PerDay, Spent: g.daySpent, Projected: projected},
+		{Scope: BudgetScopeMonth, Limit: g.limits.PerMonth, Spent: g.monthSpent, Projected: projected},
+	}
+
+	for _, s := range scopes {
+		if s.Limit <= 0 {
+			continue
+		}
+
+		if s.Total() > s.Limit {
+			if !g.approved[s.Scope] && exceeded == nil {
+				status := s
+				exceeded = &status
+			}
+			continue
+		}
+
+		if s.Total() >= s.Limit*threshold && !g.warned[s.Scope] {
+			g.warned[s.Scope] = true
+			warnings = append(warnings, s)
+		}
+	}
+
+	return warnings, exceeded
+}
@@ -0,0 +1,125 @@
+package pipeline
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// TestBudgetGuardCheck tests warning and limit detection
+func TestBudgetGuardCheck(t *testing.T) {
+	guard := NewBudgetGuard(config.BudgetLimits{PerJob: 1.0, WarnThreshold: 0.5}, nil)
+
+	warnings, exceeded := guard.Check(0.1)
+	if len(warnings) != 0 || exceeded != nil {
+		t.Fatalf("unexpected result below threshold: %v %v", warnings, exceeded)
+	}
+
+	guard.Add(0.5)
+	warnings, exceeded = guard.Check(0.1)
+	if len(warnings) != 1 || warnings[0].Scope != BudgetScopeJob {
+		t.Errorf("expected one job warning, got %v", warnings)
+	}
+	if exceeded != nil {
+		t.Errorf("should not be exceeded yet: %v", exceeded)
+	}
+
+	// Warnings are reported once
+	warnings, _ = guard.Check(0.1)
+	if len(warnings) != 0 {
+		t.Errorf("warning should only be reported once, got %v", warnings)
+	}
+
+	guard.Add(0.45)
+	_, exceeded = guard.Check(0.1)
+	if exceeded == nil || exceeded.Scope != BudgetScopeJob {
+		t.Fatalf("expected job limit to be exceeded, got %v", exceeded)
+	}
+
+	guard.Approve(BudgetScopeJob)
+	if _, exceeded = guard.Check(0.1); exceeded != nil {
+		t.Errorf("approved scope should not block: %v", exceeded)
+	}
+}
+
+// TestBudgetGuardSharedLimits tests that day and month limits count the
+// spending other jobs record after the guard was created
+func TestBudgetGuardSharedLimits(t *testing.T) {
+	cache := openTestCache(t)
+	first := NewBudgetGuard(config.BudgetLimits{PerDay: 1.0}, cache)
+	second := NewBudgetGuard(config.BudgetLimits{PerDay: 1.0}, cache)
+
+	// The first job spends most of the daily budget
+	first.Add(0.9)
+	if err := cache.RecordUsage(db.UsageRecord{JobID: "first", Model: "gpt-4o", Cost: 0.9, CostReported: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exceeded := second.Check(0.2); exceeded == nil || exceeded.Scope != BudgetScopeDay {
+		t.Fatalf("second job should see the first job's spending, got %v", exceeded)
+	}
+	if _, exceeded := first.Check(0.05); exceeded != nil {
+		t.Errorf("recorded spending should not be counted twice: %v", exceeded)
+	}
+}
+
+// TestBudgetGuardDisabled tests that zero limits disable the guard
+func TestBudgetGuardDisabled(t *testing.T) {
+	guard := NewBudgetGuard(config.BudgetLimits{}, nil)
+	if guard.Enabled() {
+		t.Error("guard without limits should be disabled")
+	}
+
+	guard.Add(100)
+	if _, exceeded := guard.Check(100); exceeded != nil {
+		t.Errorf("disabled guard should never be exceeded: %v", exceeded)
+	}
+}
+
+// TestCheckBudgetAbortsWithoutCallback tests headless behavior
+func TestCheckBudgetAbortsWithoutCallback(t *testing.T) {
+	p := New(nil, nil, &PipelineConfig{Model: "gpt-4o"})
+	p.Budget = NewBudgetGuard(config.BudgetLimits{PerJob: 0.01}, nil)
+	p.Budget.Add(0.02)
+
+	var logs []string
+	p.LogCallback = func(msg string) { logs = append(logs, msg) }
+
+	err := p.checkBudget([]parser.SubtitleLine{{Text: "Hello"}})
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+	if len(logs) == 0 {
+		t.Error("expected a log entry explaining the abort")
+	}
+}
+
+// TestCheckBudgetAsksCallback tests the interactive override
+func TestCheckBudgetAsksCallback(t *testing.T) {
+	p := New(nil, nil, &PipelineConfig{Model: "gpt-4o"})
+	p.Budget = NewBudgetGuard(config.BudgetLimits{PerJob: 0.01}, nil)
+	p.Budget.Add(0.02)
+
+	asked := 0
+	p.BudgetCallback = func(status BudgetStatus) bool {
+		asked++
+		if status.Scope != BudgetScopeJob {
+			t.Errorf("unexpected scope %q", status.Scope)
+		}
+		return true
+	}
+
+	lines := []parser.SubtitleLine{{Text: "Hello"}}
+	if err := p.checkBudget(lines); err != nil {
+		t.Fatalf("approved budget should continue, got %v", err)
+	}
+	if err := p.checkBudget(lines); err != nil {
+		t.Fatalf("second check should pass after approval, got %v", err)
+	}
+	if asked != 1 {
+		t.Errorf("callback called %d times, want 1", asked)
+	}
+}
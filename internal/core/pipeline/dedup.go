@@ -0,0 +1,175 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+	"github.com/lsilvatti/bakasub/internal/core/tokenizer"
+)
+
+// DedupStats summarizes how many lines of a file were resolved before batching
+type DedupStats struct {
+	TotalLines     int // Lines in the file
+	UniqueLines    int // Distinct source texts per speaker
+	CacheHits      int // Distinct texts served by the translation memory
+	DuplicateLines int // Repeats of texts sent to the AI, translated once
+	TokensSaved    int // Estimated prompt + completion tokens not spent on repeats
+}
+
+// Add accumulates the stats of another file
+func (s *DedupStats) Add(other DedupStats) {
+	s.TotalLines += other.TotalLines
+	s.UniqueLines += other.UniqueLines
+	s.CacheHits += other.CacheHits
+	s.DuplicateLines += other.DuplicateLines
+	s.TokensSaved += other.TokensSaved
+}
+
+// lineKey identifies the lines that share a translation: the same text said
+// by the same speaker. A line said by two characters is translated for each,
+// since gender agreement and formality depend on who speaks.
+type lineKey struct {
+	speaker string
+	text    string
+}
+
+// keyOf returns the translation key of a line
+func keyOf(line parser.SubtitleLine) lineKey {
+	return lineKey{speaker: line.Speaker, text: line.Text}
+}
+
+// cacheable reports whether a line may be served from and saved to the
+// translation memory. Its scope has no speaker, so a line with one would hand
+// its gendered or formal rendering to every other character.
+func cacheable(line parser.SubtitleLine) bool {
+	return line.Speaker == ""
+}
+
+// translationPlan is the outcome of resolving a file's lines before batching
+type translationPlan struct {
+	pending  []parser.SubtitleLine // First occurrence of every text left to translate
+	resolved map[lineKey]string    // Speaker and source text -> translation
+	stats    DedupStats
+}
+
+// planTranslation collapses lines with the same speaker and source text and
+// resolves them from done (translations carried over by a resume) and, for
+// lines without a speaker, the translation memory: exact hits in one query and
+// fuzzy hits per remaining text. Only the first occurrence of each unresolved
+// line is left to translate.
+func (p *Pipeline) planTranslation(lines []parser.SubtitleLine, done map[lineKey]string) translationPlan {
+	plan := translationPlan{
+		resolved: make(map[lineKey]string, len(lines)),
+		stats:    DedupStats{TotalLines: len(lines)},
+	}
+
+	occurrences := make(map[lineKey]int, len(lines))
+	var unique []parser.SubtitleLine
+	for _, line := range lines {
+		if occurrences[keyOf(line)] == 0 {
+			unique = append(unique, line)
+		}
+		occurrences[keyOf(line)]++
+	}
+	plan.stats.UniqueLines = len(unique)
+
+	var lookup []string
+	for _, line := range unique {
+		if translated, ok := done[keyOf(line)]; ok {
+			plan.resolved[keyOf(line)] = translated
+		} else if cacheable(line) {
+			lookup = append(lookup, line.Text)
+		}
+	}
+
+	estimator := tokenizer.NewEstimator().ForModel(p.Config.Model)
+
+	if p.Cache != nil && len(lookup) > 0 {
+		scope := p.cacheScope()
+		matches, err := p.Cache.GetScopedMatches(lookup, scope, p.Config.CachePolicy)
+		if err != nil {
+			p.log(fmt.Sprintf("Warning: Cache lookup failed: %v", err))
+		}
+
+		rec := db.LookupRecord{JobID: p.Config.JobID, LangPair: scope.LangPair, Model: p.Config.Model, Lookups: len(lookup)}
+		var savedInput, savedOutput int
+		for _, text := range lookup {
+			if entry, ok := matches[text]; ok {
+				plan.resolved[lineKey{text: text}] = entry.TranslatedText
+				rec.ExactHits++
+			} else if entry, ok := p.fuzzyMatch(text, scope); ok {
+				plan.resolved[lineKey{text: text}] = entry.TranslatedText
+				rec.FuzzyHits++
+			} else {
+				rec.Misses++
+				continue
+			}
+			plan.stats.CacheHits++
+
+			input, output := lineTokens(estimator, text)
+			savedInput += input
+			savedOutput += output
+		}
+
+		rec.SavedTokens = savedInput + savedOutput
+		rec.SavedCost = tokenizer.NewEstimator().WithCatalog(catalog.Shared(), p.Config.ProviderName).CalculateCost(savedInput, savedOutput, p.Config.Model)
+		if err := p.Cache.RecordLookups(rec); err != nil {
+			p.log(fmt.Sprintf("Warning: Failed to record cache lookups: %v", err))
+		}
+	}
+
+	for _, line := range unique {
+		if _, ok := plan.resolved[keyOf(line)]; ok {
+			continue
+		}
+		plan.pending = append(plan.pending, line)
+
+		if repeats := occurrences[keyOf(line)] - 1; repeats > 0 {
+			plan.stats.DuplicateLines += repeats
+			input, output := lineTokens(estimator, line.Text)
+			plan.stats.TokensSaved += repeats * (input + output)
+		}
+	}
+
+	return plan
+}
+
+// lineTokens estimates the tokens one line costs in a batch: its share of
+// the prompt and of the completion, estimated at ~80% of the input like
+// tokenizer.EstimateCost
+func lineTokens(estimator *tokenizer.Estimator, text string) (input, output int) {
+	input = estimator.EstimateTokens(text) + batchLineOverhead
+	return input, int(float64(input) * 0.8)
+}
+
+// resumedTranslations maps the speaker and source text of every line
+// translated by an interrupted run to its translation, matching lines by index
+func resumedTranslations(lines, translated []parser.SubtitleLine) map[lineKey]string {
+	source := make(map[int]lineKey, len(lines))
+	for _, line := range lines {
+		source[line.Index] = keyOf(line)
+	}
+
+	done := make(map[lineKey]string, len(translated))
+	for _, line := range translated {
+		if key, ok := source[line.Index]; ok {
+			done[key] = line.Text
+		}
+	}
+	return done
+}
+
+// fanOut copies lines with every text replaced by the translation of its
+// speaker and text. Lines without a translation are kept as-is.
+func fanOut(lines []parser.SubtitleLine, translations map[lineKey]string) []parser.SubtitleLine {
+	result := make([]parser.SubtitleLine, len(lines))
+	for i, line := range lines {
+		result[i] = line
+		if translated, ok := translations[keyOf(line)]; ok {
+			result[i].Text = translated
+		}
+	}
+	return result
+}
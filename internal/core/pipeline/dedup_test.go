@@ -0,0 +1,169 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// episodeLines returns lines with the repeats typical of an anime episode
+func episodeLines() []parser.SubtitleLine {
+	texts := []string{"Eh?", "Wait!", "Where are you going?", "Eh?", "Wait!", "Eh?"}
+	lines := make([]parser.SubtitleLine, len(texts))
+	for i, text := range texts {
+		lines[i] = parser.SubtitleLine{Index: i + 1, Text: text}
+	}
+	return lines
+}
+
+// TestPlanTranslationDeduplicates tests that each text is translated once and fanned out
+func TestPlanTranslationDeduplicates(t *testing.T) {
+	provider := &MockProvider{}
+	p := New(provider, openTestCache(t), &PipelineConfig{SourceLang: "en", TargetLang: "pt-br", Model: "gpt-4o"})
+
+	var reported DedupStats
+	p.DedupCallback = func(stats DedupStats) { reported = stats }
+
+	lines := episodeLines()
+	plan := p.planTranslation(lines, nil)
+	p.reportDedup(plan.stats)
+
+	if len(plan.pending) != 3 {
+		t.Fatalf("got %d pending lines, want 3", len(plan.pending))
+	}
+	if reported.TotalLines != 6 || reported.UniqueLines != 3 || reported.DuplicateLines != 3 || reported.CacheHits != 0 {
+		t.Errorf("unexpected stats: %+v", reported)
+	}
+	if reported.TokensSaved <= 0 {
+		t.Errorf("expected tokens saved, got %d", reported.TokensSaved)
+	}
+
+	batch := TranslationBatch{Lines: plan.pending, Prefetched: true}
+	translated, err := p.translateBatch(context.Background(), batch)
+	if err != nil {
+		t.Fatalf("translateBatch failed: %v", err)
+	}
+	if len(provider.LastPayload) != 3 {
+		t.Errorf("provider received %d lines, want 3 unique lines", len(provider.LastPayload))
+	}
+	for i, line := range translated {
+		plan.resolved[keyOf(plan.pending[i])] = line.Text
+	}
+
+	result := fanOut(lines, plan.resolved)
+	for i, line := range result {
+		if want := "Translated: " + lines[i].Text; line.Text != want {
+			t.Errorf("line %d = %q, want %q", i, line.Text, want)
+		}
+		if line.Index != lines[i].Index {
+			t.Errorf("line %d index = %d, want %d", i, line.Index, lines[i].Index)
+		}
+	}
+}
+
+// TestPlanTranslationResolvesCacheHits tests that cached texts are not batched
+func TestPlanTranslationResolvesCacheHits(t *testing.T) {
+	cache := openTestCache(t)
+	p := New(&MockProvider{}, cache, &PipelineConfig{SourceLang: "en", TargetLang: "pt-br", Model: "gpt-4o", JobID: "job-dedup"})
+	cache.SaveScopedTranslation("Eh?", "Hã?", p.cacheScope())
+
+	plan := p.planTranslation(episodeLines(), nil)
+
+	if got := plan.resolved[lineKey{text: "Eh?"}]; got != "Hã?" {
+		t.Errorf("Eh? resolved to %q, want cached translation", got)
+	}
+	if plan.stats.CacheHits != 1 {
+		t.Errorf("got %d cache hits, want 1", plan.stats.CacheHits)
+	}
+	// Only the repeated "Wait!" still needs translating; repeats of cached lines are free anyway
+	if len(plan.pending) != 2 || plan.stats.DuplicateLines != 1 {
+		t.Errorf("got %d pending and %d duplicate lines, want 2 and 1", len(plan.pending), plan.stats.DuplicateLines)
+	}
+
+	lookups, err := cache.GetJobLookups("job-dedup")
+	if err != nil {
+		t.Fatalf("GetJobLookups failed: %v", err)
+	}
+	if lookups.Lookups != 3 || lookups.ExactHits != 1 || lookups.Misses != 2 {
+		t.Errorf("unexpected recorded lookups: %+v", lookups)
+	}
+	if lookups.SavedTokens <= 0 || lookups.SavedCost <= 0 {
+		t.Errorf("expected saved tokens and cost priced for gpt-4o, got %+v", lookups)
+	}
+}
+
+// TestPlanTranslationResume tests that lines translated before an interruption are reused
+func TestPlanTranslationResume(t *testing.T) {
+	p := New(&MockProvider{}, openTestCache(t), &PipelineConfig{SourceLang: "en", TargetLang: "pt-br"})
+	lines := episodeLines()
+
+	done := resumedTranslations(lines, []parser.SubtitleLine{{Index: 2, Text: "Espera!"}})
+	plan := p.planTranslation(lines, done)
+
+	if got := plan.resolved[lineKey{text: "Wait!"}]; got != "Espera!" {
+		t.Errorf("Wait! resolved to %q, want resumed translation", got)
+	}
+	for _, line := range plan.pending {
+		if line.Text == "Wait!" {
+			t.Error("resumed line should not be translated again")
+		}
+	}
+}
+
+// genderedProvider thanks in the feminine for Asuna and in the masculine otherwise
+type genderedProvider struct{ MockProvider }
+
+func (g *genderedProvider) SendBatch(ctx context.Context, payload []ai.Line, systemPrompt string) ([]ai.Line, ai.Usage, error) {
+	g.CallCount++
+	g.LastPayload = payload
+	result := make([]ai.Line, len(payload))
+	for i, line := range payload {
+		result[i] = ai.Line{ID: line.ID, Text: "Obrigado."}
+		if line.Speaker == "Asuna" {
+			result[i].Text = "Obrigada."
+		}
+	}
+	return result, ai.Usage{}, nil
+}
+
+// TestPlanTranslationSpeakers tests that the same text said by two speakers is
+// translated for each, and that their renderings stay out of the translation memory
+func TestPlanTranslationSpeakers(t *testing.T) {
+	provider := &genderedProvider{}
+	cache := openTestCache(t)
+	p := New(provider, cache, &PipelineConfig{SourceLang: "en", TargetLang: "pt-br", Model: "gpt-4o"})
+
+	lines := []parser.SubtitleLine{
+		{Index: 1, Text: "Thank you.", Speaker: "Kirito"},
+		{Index: 2, Text: "Thank you."},
+		{Index: 3, Text: "Thank you.", Speaker: "Asuna"},
+		{Index: 4, Text: "Thank you.", Speaker: "Kirito"},
+	}
+	plan := p.planTranslation(lines, nil)
+	if len(plan.pending) != 3 || plan.stats.DuplicateLines != 1 {
+		t.Fatalf("got %d pending and %d duplicate lines, want 3 and 1", len(plan.pending), plan.stats.DuplicateLines)
+	}
+
+	translated, err := p.translateBatch(context.Background(), TranslationBatch{Lines: plan.pending, Prefetched: true})
+	if err != nil {
+		t.Fatalf("translateBatch failed: %v", err)
+	}
+	for i, line := range translated {
+		plan.resolved[keyOf(plan.pending[i])] = line.Text
+	}
+
+	result := fanOut(lines, plan.resolved)
+	for i, want := range []string{"Obrigado.", "Obrigado.", "Obrigada.", "Obrigado."} {
+		if result[i].Text != want {
+			t.Errorf("line %d = %q, want %q", i+1, result[i].Text, want)
+		}
+	}
+
+	entry, found := cache.GetScopedMatch("Thank you.", p.cacheScope(), db.PolicyStrict)
+	if !found || entry.TranslatedText != "Obrigado." {
+		t.Errorf("cached %+v, want only the translation of the line without a speaker", entry)
+	}
+}
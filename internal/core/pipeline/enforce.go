@@ -0,0 +1,47 @@
+package pipeline
+
+import (
+	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+)
+
+// placeholderInstruction tells the model how to handle protected glossary terms
+const placeholderInstruction = "\n\nTokens like ⟦0⟧ stand for glossary terms: copy them unchanged into the translation, where the sentence needs the term."
+
+// termGuard holds the renderings of the placeholders put in a request, by line ID
+type termGuard map[int][]string
+
+// protectTerms replaces the kept and forced glossary terms of the payload
+// with placeholders when placeholder enforcement is on
+func (p *Pipeline) protectTerms(payload []ai.Line) termGuard {
+	if !p.Config.TermPlaceholders || len(p.Config.GlossaryTerms) == 0 {
+		return nil
+	}
+	guard := make(termGuard)
+	for i := range payload {
+		text, renderings := linter.ProtectTerms(payload[i].Text, p.Config.GlossaryTerms)
+		if len(renderings) > 0 {
+			payload[i].Text = text
+			guard[payload[i].ID] = renderings
+		}
+	}
+	return guard
+}
+
+// prompt adds the placeholder instruction to a system prompt when terms were protected
+func (g termGuard) prompt(systemPrompt string) string {
+	if len(g) == 0 {
+		return systemPrompt
+	}
+	return systemPrompt + placeholderInstruction
+}
+
+// restore puts the protected terms back into the response. Placeholders the
+// model dropped leave the term missing, which the glossary-terms rule catches.
+func (g termGuard) restore(response []ai.Line) {
+	for i := range response {
+		if renderings, ok := g[response[i].ID]; ok {
+			response[i].Text = linter.RestoreTerms(response[i].Text, renderings)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
+)
+
+// enforcedTerms keeps Konoha and forces nakama to companheiro
+var enforcedTerms = []linter.Term{
+	{Source: "Konoha", Target: "Vila da Folha", Keep: true, Forbidden: []string{"Vila da Folha"}},
+	{Source: "nakama", Target: "companheiro", Force: true},
+}
+
+// TestTermPlaceholders tests that protected terms are sent as placeholders and restored before caching
+func TestTermPlaceholders(t *testing.T) {
+	provider := &replyProvider{Replies: map[string]string{"Back to ⟦0⟧, my ⟦1⟧!": "De volta a ⟦0⟧, meus ⟦1⟧!"}}
+	p := New(provider, openTestCache(t), &PipelineConfig{TargetLang: "pt-br", GlossaryTerms: enforcedTerms, TermPlaceholders: true})
+
+	batch := TranslationBatch{Lines: linter.TextLines([]string{"Back to Konoha, my nakama!"})}
+	lines, err := p.translateBatchWithRetry(context.Background(), batch, 0)
+	if err != nil {
+		t.Fatalf("translateBatchWithRetry failed: %v", err)
+	}
+	if !strings.Contains(provider.LastPrompt, "⟦0⟧") {
+		t.Error("the prompt should explain the placeholders")
+	}
+	if lines[0].Text != "De volta a Konoha, meus companheiro!" {
+		t.Errorf("placeholders should be restored, got %q", lines[0].Text)
+	}
+
+	entry, found := p.Cache.GetScopedMatch("Back to Konoha, my nakama!", p.cacheScope(), db.PolicyStrict)
+	if !found || entry.TranslatedText != lines[0].Text {
+		t.Errorf("the restored translation should be cached, got %+v", entry)
+	}
+}
+
+// TestGlossaryEnforcement tests that the quality gate substitutes forbidden renderings and re-requests missing terms
+func TestGlossaryEnforcement(t *testing.T) {
+	provider := &replyProvider{Replies: map[string]string{"You are my nakama.": "Você é meu companheiro."}}
+	var logs []string
+	p := qualityPipeline(t, provider, &logs)
+	p.Config.GlossaryTerms = enforcedTerms
+
+	sources := linter.TextLines([]string{"Back to Konoha!", "You are my nakama."})
+	lines := linter.TextLines([]string{"De volta à Vila da Folha!", "Você é meu amigo."})
+
+	fixed, issues, err := p.qualityGate(context.Background(), sources, lines, nil, qualityRepairAttempts)
+	if err != nil {
+		t.Fatalf("qualityGate failed: %v", err)
+	}
+	if fixed[0].Text != "De volta à Konoha!" || fixed[1].Text != "Você é meu companheiro." || len(issues) != 0 {
+		t.Errorf("unexpected result: %+v %+v", fixed, issues)
+	}
+	if provider.CallCount != 1 || len(provider.LastPayload) != 1 || !strings.Contains(provider.LastPrompt, "Expected 'companheiro'") {
+		t.Errorf("only the line missing its term should be re-requested, got %+v", provider.LastPayload)
+	}
+}
+
+// TestApplySeriesGlossaryEnforced tests that flagged terms of the series glossary are enforced
+func TestApplySeriesGlossaryEnforced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), termbase.FileName)
+	glossary := `[{"original":"Konoha","translation":"Vila da Folha","type":"Place","status":"approved","enforce":"keep"}]`
+	if err := os.WriteFile(path, []byte(glossary), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := New(nil, nil, &PipelineConfig{InputPath: "/shows/ep01.mkv", GlossaryPath: path})
+	p.applySeriesGlossary(context.Background(), []parser.SubtitleLine{{Index: 1, Text: "Konoha!"}})
+
+	if len(p.Config.GlossaryTerms) != 1 || !p.Config.GlossaryTerms[0].Keep {
+		t.Errorf("kept term should be enforced, got %+v", p.Config.GlossaryTerms)
+	}
+	if p.Config.Glossary["Konoha"] != "Konoha" {
+		t.Errorf("kept term should be listed untranslated, got %v", p.Config.Glossary)
+	}
+}
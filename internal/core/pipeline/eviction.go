@@ -0,0 +1,25 @@
+package pipeline
+
+import (
+	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+)
+
+// EvictionLimits converts the cache settings to eviction limits
+func EvictionLimits(settings config.CacheSettings) db.EvictionLimits {
+	return db.EvictionLimits{
+		MaxEntries: settings.MaxEntries,
+		MaxBytes:   int64(settings.MaxSizeMB) << 20,
+		Policy:     db.ParseEvictionPolicy(settings.EvictionPolicy),
+	}
+}
+
+// EnforceCacheLimits evicts the entries beyond the configured limits, as
+// done after every job. It returns a nil report when no limit is set.
+func EnforceCacheLimits(cache *db.Cache, settings config.CacheSettings) (*db.EvictionReport, error) {
+	limits := EvictionLimits(settings)
+	if cache == nil || !limits.Enabled() {
+		return nil, nil
+	}
+	return cache.Evict(limits)
+}
@@ -0,0 +1,41 @@
+package pipeline
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+)
+
+// TestEvictionLimits tests the conversion of cache settings
+func TestEvictionLimits(t *testing.T) {
+	limits := EvictionLimits(config.CacheSettings{MaxEntries: 1000, MaxSizeMB: 2, EvictionPolicy: "LFU"})
+	if limits.MaxEntries != 1000 || limits.MaxBytes != 2<<20 || limits.Policy != db.EvictLFU {
+		t.Errorf("unexpected limits: %+v", limits)
+	}
+	if EvictionLimits(config.CacheSettings{}).Enabled() {
+		t.Error("zero settings should not limit the cache")
+	}
+}
+
+// TestEnforceCacheLimits tests eviction after a job
+func TestEnforceCacheLimits(t *testing.T) {
+	cache := openTestCache(t)
+	for i := 0; i < 5; i++ {
+		cache.SaveTranslation(fmt.Sprintf("line %d", i), "linha", "en->pt-br")
+	}
+
+	report, err := EnforceCacheLimits(cache, config.CacheSettings{})
+	if err != nil || report != nil {
+		t.Errorf("no limit: got report %+v, err %v", report, err)
+	}
+
+	report, err = EnforceCacheLimits(cache, config.CacheSettings{MaxEntries: 3})
+	if err != nil {
+		t.Fatalf("EnforceCacheLimits failed: %v", err)
+	}
+	if report == nil || report.EvictedCount != 2 || report.EntriesAfter != 3 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/lsilvatti/bakasub/internal/core/ner"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
+)
+
+// applySeriesGlossary scans the lines for named entities, records them as
+// candidates in the series glossary and sets the job glossary to the merged
+// result. Without a series glossary the entities only apply to this job.
+func (p *Pipeline) applySeriesGlossary(ctx context.Context, lines []parser.SubtitleLine) error {
+	p.log("Scanning for named entities...")
+	entities := NewNERScanner().ScanLines(lines)
+
+	if p.Config.GlossaryPath == "" {
+		if len(entities) > 0 {
+			p.log(fmt.Sprintf("Detected %d potential entities", len(entities)))
+		}
+		if p.Config.GlossaryPrePass {
+			p.log("Glossary pre-pass skipped: no series glossary")
+		}
+		p.Config.Glossary = ner.MergeWithProjectGlossary(entities, p.Config.Glossary)
+		return nil
+	}
+
+	store, err := termbase.Load(p.Config.GlossaryPath)
+	if err != nil {
+		p.log(fmt.Sprintf("Warning: ignoring series glossary %s: %v", p.Config.GlossaryPath, err))
+		p.Config.Glossary = ner.MergeWithProjectGlossary(entities, p.Config.Glossary)
+		return nil
+	}
+
+	if len(entities) > 0 {
+		added := store.Record(filepath.Base(p.Config.InputPath), entities)
+		p.log(fmt.Sprintf("Detected %d potential entities (%d new candidates in the series glossary)", len(entities), added))
+	}
+
+	proposed := 0
+	if p.Config.GlossaryPrePass {
+		proposals, err := p.proposeTerms(ctx, lines)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, ErrBudgetExceeded) {
+				return err
+			}
+			p.log(fmt.Sprintf("Warning: glossary pre-pass failed: %v", err))
+		}
+		added := store.Propose(proposals)
+		proposed = store.Proposed()
+		p.log(fmt.Sprintf("Glossary pre-pass: %d terms proposed (%d new), %d awaiting approval", len(proposals), added, proposed))
+	}
+
+	if len(entities) > 0 || p.Config.GlossaryPrePass {
+		if err := store.Save(); err != nil {
+			p.log(fmt.Sprintf("Warning: failed to save series glossary: %v", err))
+		}
+	}
+
+	// Let the user approve the proposals before translating
+	if proposed > 0 && p.GlossaryCallback != nil {
+		p.GlossaryCallback(p.Config.GlossaryPath)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if reviewed, err := termbase.Load(p.Config.GlossaryPath); err != nil {
+			p.log(fmt.Sprintf("Warning: failed to reload series glossary: %v", err))
+		} else {
+			store = reviewed
+		}
+	}
+
+	p.Config.Glossary = store.Merge(p.Config.Glossary)
+	p.Config.GlossaryTerms = append(p.Config.GlossaryTerms, store.Enforced()...)
+	p.log(fmt.Sprintf("Series glossary: %d terms, %d enforced (%d candidates awaiting review)",
+		len(p.Config.Glossary), len(p.Config.GlossaryTerms), len(store.Candidates())+store.Proposed()))
+	return nil
+}
@@ -0,0 +1,105 @@
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
+)
+
+// TestApplySeriesGlossary tests that entities accumulate across episodes and reviewed terms are reused
+func TestApplySeriesGlossary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), termbase.FileName)
+	lines := linter.TextLines([]string{
+		"Where is Naruto-kun?",
+		"I saw Naruto-kun near Konoha.",
+		"We go back to Konoha tomorrow.",
+	})
+
+	p := New(nil, nil, &PipelineConfig{InputPath: "/shows/ep01.mkv", GlossaryPath: path})
+	p.applySeriesGlossary(context.Background(), lines)
+	if p.Config.Glossary["Naruto"] != "Naruto" || p.Config.Glossary["Konoha"] != "Konoha" {
+		t.Fatalf("candidates should be kept as-is, got %v", p.Config.Glossary)
+	}
+
+	store, err := termbase.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	naruto, konoha := store.Find("Naruto"), store.Find("Konoha")
+	if naruto == nil || konoha == nil || naruto.Status != termbase.StatusCandidate || naruto.Episodes["ep01.mkv"] == 0 {
+		t.Fatalf("entities should be stored as candidates: %+v", store.Terms)
+	}
+
+	// Review in the glossary editor, then the next episode
+	konoha.Reject()
+	store.Set("Naruto", "Naruto Uzumaki", "Name")
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	p = New(nil, nil, &PipelineConfig{InputPath: "/shows/ep02.mkv", GlossaryPath: path, Glossary: store.Glossary()})
+	p.applySeriesGlossary(context.Background(), lines)
+	if p.Config.Glossary["Naruto"] != "Naruto Uzumaki" {
+		t.Errorf("approved translation should be used, got %v", p.Config.Glossary)
+	}
+	if _, ok := p.Config.Glossary["Konoha"]; ok {
+		t.Error("rejected terms should not be used")
+	}
+
+	store, _ = termbase.Load(path)
+	if got := store.Find("Naruto"); len(got.Episodes) != 2 || got.Status != termbase.StatusApproved {
+		t.Errorf("counts should accumulate across episodes: %+v", got)
+	}
+}
+
+// TestApplySeriesGlossaryVolatile tests the job-only glossary without a series glossary
+func TestApplySeriesGlossaryVolatile(t *testing.T) {
+	p := New(nil, nil, &PipelineConfig{Glossary: map[string]string{"Naruto": "Naruto Uzumaki"}})
+	p.applySeriesGlossary(context.Background(), linter.TextLines([]string{"Naruto-kun and Sakura-chan.", "Sakura-chan!"}))
+
+	if p.Config.Glossary["Naruto"] != "Naruto Uzumaki" || p.Config.Glossary["Sakura"] != "Sakura" {
+		t.Errorf("unexpected glossary: %v", p.Config.Glossary)
+	}
+}
+
+// TestGlossaryPrePass tests that proposals are reviewed before they are used
+func TestGlossaryPrePass(t *testing.T) {
+	path := filepath.Join(t.TempDir(), termbase.FileName)
+	provider := &termsProvider{Terms: []string{
+		"nakama | Term | companheiro | recurring word for friends",
+		"Konoha | Place | Vila da Folha | village name",
+	}}
+	p := New(provider, nil, &PipelineConfig{InputPath: "/shows/ep01.mkv", TargetLang: "pt-br", GlossaryPath: path, GlossaryPrePass: true})
+
+	reviewed := false
+	p.GlossaryCallback = func(reviewPath string) {
+		reviewed = true
+		store, err := termbase.Load(reviewPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := store.Proposed(); n != 2 {
+			t.Errorf("%d proposals awaiting approval, want 2", n)
+		}
+		store.Find("nakama").Promote()
+		store.Save()
+	}
+
+	lines := linter.TextLines([]string{"You are my nakama.", "Back to Konoha!"})
+	if err := p.applySeriesGlossary(context.Background(), lines); err != nil {
+		t.Fatalf("applySeriesGlossary failed: %v", err)
+	}
+	if !reviewed || provider.CallCount != 1 || !strings.Contains(provider.LastPrompt, "pt-br") {
+		t.Fatalf("the pre-pass should ask the model once and open the review")
+	}
+	if p.Config.Glossary["nakama"] != "companheiro" {
+		t.Errorf("approved proposal should be used, got %v", p.Config.Glossary)
+	}
+	if _, ok := p.Config.Glossary["Konoha"]; ok {
+		t.Error("proposals left unapproved should not be used")
+	}
+}
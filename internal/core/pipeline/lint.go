@@ -0,0 +1,84 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// LintRules converts the lint settings in effect for a prompt profile to
+// linter rule overrides. Entries with an unknown rule ID or severity are
+// skipped and reported in the returned error; the rest still apply.
+func LintRules(settings config.LintSettings, profile string) (map[string]linter.RuleConfig, error) {
+	overrides := settings.ForProfile(profile)
+
+	ids := make([]string, 0, len(overrides))
+	for id := range overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rules := make(map[string]linter.RuleConfig, len(overrides))
+	var errs []error
+	for _, id := range ids {
+		if _, ok := linter.LookupRule(id); !ok {
+			errs = append(errs, fmt.Errorf("lint: unknown rule %q", id))
+			continue
+		}
+		severity, err := linter.ParseSeverity(overrides[id].Severity)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lint: rule %q: %w", id, err))
+			continue
+		}
+		rules[id] = linter.RuleConfig{Severity: severity, Options: overrides[id].Options}
+	}
+	return rules, errors.Join(errs...)
+}
+
+// normalizeTypography applies the typography rules of the target language
+// that are enabled for the profile, logging how many lines each rule fixed.
+// Normalized lines are saved to the translation memory as lint-fixed.
+func (p *Pipeline) normalizeTypography(sources, lines []parser.SubtitleLine) []parser.SubtitleLine {
+	fixed, issues := linter.NormalizeTypography(lines, linter.CheckOptions{
+		TargetLang: p.Config.TargetLang,
+		Format:     p.format,
+		Rules:      p.Config.LintRules,
+	})
+	if len(issues) == 0 {
+		return lines
+	}
+
+	counts := make(map[string]int)
+	changed := make(map[int]bool)
+	for _, issue := range issues {
+		counts[issue.RuleID]++
+		changed[issue.LineID] = true
+		if i := issue.LineID - 1; i < len(sources) {
+			p.recordFix(sources[i].Text, issue, lines[i].Text)
+		}
+	}
+	var parts []string
+	for _, id := range linter.TypographyRules {
+		if counts[id] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", id, counts[id]))
+		}
+	}
+	p.log(fmt.Sprintf("Typography: normalized %d lines (%s)", len(changed), strings.Join(parts, ", ")))
+
+	// Cache the normalized lines so later hits need no fixing
+	if p.Cache != nil {
+		scope := p.cacheScope()
+		for id := range changed {
+			if i := id - 1; i < len(sources) && cacheable(sources[i]) {
+				p.Cache.SaveWithProvenance(sources[i].Text, fixed[i].Text, scope, db.ProvenanceLintFixed)
+			}
+		}
+	}
+	return fixed
+}
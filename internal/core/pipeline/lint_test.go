@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// TestLintRules tests converting lint settings and reporting invalid entries
+func TestLintRules(t *testing.T) {
+	settings := config.LintSettings{
+		Rules: map[string]config.LintRuleConfig{
+			"glossary":    {Severity: "high"},
+			"punctuation": {Severity: "loud"},
+			"no-such":     {Severity: "low"},
+		},
+		Profiles: map[string]map[string]config.LintRuleConfig{
+			"anime": {"source-residue": {Severity: "off"}},
+		},
+	}
+
+	rules, err := LintRules(settings, "anime")
+	if err == nil {
+		t.Error("expected an error for the invalid entries")
+	}
+	if rules[linter.RuleGlossary].Severity != linter.SeverityHigh || rules[linter.RuleSourceResidue].Severity != linter.SeverityOff {
+		t.Errorf("valid entries should still apply: %+v", rules)
+	}
+	if _, ok := rules[linter.RulePunctuation]; ok {
+		t.Error("an entry with an invalid severity should be skipped")
+	}
+
+	if rules, _ := LintRules(settings, "movie"); rules[linter.RuleSourceResidue].Severity != "" {
+		t.Error("profile overrides should not leak into other profiles")
+	}
+}
+
+// TestNormalizeTypography tests the typography stage and its log line
+func TestNormalizeTypography(t *testing.T) {
+	p := New(nil, nil, &PipelineConfig{TargetLang: "es", LintRules: map[string]linter.RuleConfig{
+		linter.RuleQuotes: {Severity: linter.SeverityOff},
+	}})
+	var logs []string
+	p.LogCallback = func(msg string) { logs = append(logs, msg) }
+
+	lines := []parser.SubtitleLine{{Index: 1, Text: `Qué dijo "ella"?`}, {Index: 2, Text: "Nada."}}
+	fixed := p.normalizeTypography(lines, lines)
+	if fixed[0].Text != `¿Qué dijo "ella"?` || fixed[1].Text != "Nada." {
+		t.Errorf("unexpected typography result: %+v", fixed)
+	}
+	if len(logs) != 1 || logs[0] != "Typography: normalized 1 lines (inverted-marks 1)" {
+		t.Errorf("unexpected log: %v", logs)
+	}
+
+	logs = nil
+	if p.normalizeTypography(fixed, fixed); len(logs) != 0 {
+		t.Errorf("nothing left to normalize should not log, got %v", logs)
+	}
+}
+
+// TestNormalizeTypographyCaches tests that normalized lines replace their
+// machine translation in the translation memory
+func TestNormalizeTypographyCaches(t *testing.T) {
+	cache := openTestCache(t)
+	p := New(nil, cache, &PipelineConfig{SourceLang: "en", TargetLang: "es"})
+	scope := p.cacheScope()
+	cache.SaveScopedTranslation("What did she say?", "Qué dijo?", scope)
+
+	sources := []parser.SubtitleLine{{Index: 1, Text: "What did she say?"}, {Index: 2, Text: "Really?", Speaker: "Asuna"}}
+	lines := []parser.SubtitleLine{{Index: 1, Text: "Qué dijo?"}, {Index: 2, Text: "En serio?"}}
+	p.normalizeTypography(sources, lines)
+
+	entry, found := cache.GetScopedMatch("What did she say?", scope, db.PolicyStrict)
+	if !found || entry.TranslatedText != "¿Qué dijo?" || entry.Provenance != db.ProvenanceLintFixed {
+		t.Errorf("got %+v, want the normalized line as lint-fixed", entry)
+	}
+	if _, found := cache.GetScopedMatch("Really?", scope, db.PolicyStrict); found {
+		t.Error("a line with a speaker should not be cached")
+	}
+}
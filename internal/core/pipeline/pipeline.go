@@ -10,11 +10,15 @@ import (
 	"time"
 
 	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
+	"github.com/lsilvatti/bakasub/internal/core/characters"
 	"github.com/lsilvatti/bakasub/internal/core/db"
 	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/lintreport"
 	"github.com/lsilvatti/bakasub/internal/core/media"
 	"github.com/lsilvatti/bakasub/internal/core/ner"
 	"github.com/lsilvatti/bakasub/internal/core/parser"
+	"github.com/lsilvatti/bakasub/internal/core/tokenizer"
 )
 
 // NewNERScanner creates a new NER scanner (wrapper for ner package)
@@ -30,6 +34,31 @@ type Pipeline struct {
 	ResumeState      *ResumeState
 	LogCallback      func(string)
 	ProgressCallback func(current, total int)
+	UsageCallback    func(batch ai.Usage)   // Called after every provider request
+	Usage            ai.Usage               // Aggregated usage for the current file
+	DedupCallback    func(stats DedupStats) // Called once lines are resolved, before batching
+	Dedup            DedupStats             // Deduplication of the current file
+
+	// Budget enforces spending limits (nil = unlimited). When a limit would be
+	// exceeded, BudgetCallback is asked whether to continue; without a callback
+	// (headless/watch mode) the job is aborted with ErrBudgetExceeded.
+	Budget         *BudgetGuard
+	BudgetCallback func(status BudgetStatus) bool
+
+	// QualityCallback is asked what to do with the lint issues left once a
+	// file is translated; without a callback they are only logged. Quality
+	// holds the last report of the current file.
+	QualityCallback func(report QualityReport) QualityDecision
+	Quality         *QualityReport
+
+	// GlossaryCallback opens the series glossary for review once the LLM
+	// pre-pass proposed terms, and returns when the user is done; without a
+	// callback the proposals wait for a later review.
+	GlossaryCallback func(path string)
+
+	format     string                  // Format of the subtitle being translated ("ass" or "srt")
+	fixes      map[string][]appliedFix // Lint fixes applied to the current file, by source text
+	characters *characters.Store       // Character profiles of the series (nil until loaded)
 }
 
 // PipelineConfig holds pipeline configuration
@@ -40,14 +69,31 @@ type PipelineConfig struct {
 	TargetLang        string
 	Model             string
 	Temperature       float64
-	BatchSize         int
+	BatchSize         int            // Lines per batch; caps token-budgeted batches
+	BatchStrategy     string         // parser.BatchStrategy* ("" = tokens if MaxBatchTokens is set, else fixed)
+	MaxBatchTokens    int            // Source tokens per batch (0 = derived from the model's context window)
+	SceneGap          time.Duration  // Silence treated as a scene change (0 = parser.DefaultSceneGap)
+	Batcher           parser.Batcher // Custom batching; overrides BatchStrategy
 	RemoveHI          bool
 	Glossary          map[string]string
+	GlossaryPath      string        // Series glossary (termbase) shared by the episodes; "" keeps detected entities for this job only
+	GlossaryPrePass   bool          // Ask the model for glossary terms before translating (needs GlossaryPath)
+	GlossaryTerms     []linter.Term // Terms enforced after translation; the series glossary adds its flagged terms
+	TermPlaceholders  bool          // Replace kept and forced terms with placeholders in requests
+	CharactersPath    string        // Per-series character profiles; "" = none
 	SystemPrompt      string
 	SlidingWindowSize int    // Number of lines for context
 	TrackID           int    // Subtitle track ID to extract (-1 for auto-detect)
 	MuxMode           string // "replace" or "new-file"
 	BackupOriginal    bool   // Create backup before replace
+	JobID             string // Identifies the job in the usage log
+	Profile           string // Prompt profile, part of the cache scope
+	ProjectID         string // Optional project/series ID, part of the cache scope
+	CachePolicy       db.LookupPolicy
+	FuzzyThreshold    float64                      // Minimum similarity for fuzzy cache hits (0 = default, 1 = exact only)
+	ProviderName      string                       // Provider name recorded in the usage log
+	LintRules         map[string]linter.RuleConfig // Quality gate rule overrides (see LintRules)
+	LintReports       []lintreport.Format          // Lint reports written next to the output file
 }
 
 // ResumeState holds state for smart resume
@@ -65,14 +111,43 @@ type TranslationBatch struct {
 	ContextLines []parser.SubtitleLine // Sliding window context
 	BatchIndex   int
 	TotalBatches int
+	Prefetched   bool // Lines were already looked up in the cache
 }
 
+// Batch token budget. Used when the model's context window is unknown; the
+// budget otherwise grows with the window up to maxBatchTokens, since the
+// output (of similar size) is bounded by the model's completion limit.
+const (
+	DefaultBatchTokens = 1500
+	minBatchTokens     = 300
+	maxBatchTokens     = 3000
+	// Tokens kept free for the system prompt, glossary and context lines
+	promptReserveTokens = 2000
+	// Context window assumed for local models that do not report one
+	localContextLength = 4096
+)
+
+// batchLineOverhead approximates the JSON framing ({"id":..,"text":..}) sent per line
+const batchLineOverhead = 8
+
 // New creates a new pipeline instance
 func New(provider ai.LLMProvider, cache *db.Cache, config *PipelineConfig) *Pipeline {
 	if config.SlidingWindowSize == 0 {
 		config.SlidingWindowSize = 3
 	}
-	if config.BatchSize == 0 {
+	if config.CachePolicy == "" {
+		config.CachePolicy = db.PolicyStrict
+	}
+	if config.FuzzyThreshold <= 0 {
+		config.FuzzyThreshold = db.DefaultFuzzyThreshold
+	}
+	if config.BatchStrategy == "" {
+		config.BatchStrategy = parser.BatchStrategyFixed
+		if config.MaxBatchTokens > 0 {
+			config.BatchStrategy = parser.BatchStrategyTokens
+		}
+	}
+	if config.BatchSize == 0 && config.BatchStrategy == parser.BatchStrategyFixed {
 		config.BatchSize = 50
 	}
 
@@ -86,6 +161,18 @@ func New(provider ai.LLMProvider, cache *db.Cache, config *PipelineConfig) *Pipe
 // Execute runs the full translation pipeline
 func (p *Pipeline) Execute(ctx context.Context) error {
 	p.log("Starting translation pipeline...")
+	p.Usage = ai.Usage{}
+	p.fixes = nil
+
+	// Pricing and context windows come from the model catalog; refresh it
+	// once its TTL expired so long watch sessions don't keep stale prices
+	p.refreshCatalog(ctx, catalog.Shared())
+
+	// Batch sizes and cost estimates fall back to an approximation without
+	// the model's BPE vocabulary; say so instead of presenting them as exact
+	if err := tokenizer.NewEstimator().ForModel(p.Config.Model).Err(); err != nil {
+		p.log(fmt.Sprintf("Warning: token counts are approximate: %v", err))
+	}
 
 	// Determine track ID to use
 	trackID := p.Config.TrackID
@@ -120,6 +207,11 @@ func (p *Pipeline) Execute(ctx context.Context) error {
 		return fmt.Errorf("parse failed: %w", err)
 	}
 	p.log(fmt.Sprintf("Found %d lines", subFile.LineCount))
+	p.format = subFile.Format
+
+	// Step 2.5: Speakers, before HI removal strips NAME: labels
+	p.loadCharacters()
+	p.assignSpeakers(subFile.Lines)
 
 	// Step 3: Preprocessing (remove HI tags if enabled)
 	if p.Config.RemoveHI {
@@ -129,45 +221,42 @@ func (p *Pipeline) Execute(ctx context.Context) error {
 		}
 	}
 
-	// Step 3.5: NER Scan for Volatile Glossary (if no project glossary provided)
-	if len(p.Config.Glossary) == 0 {
-		p.log("Scanning for named entities (Volatile Glossary)...")
-		nerScanner := NewNERScanner()
-		entities := nerScanner.ScanLines(subFile.Lines)
-		if len(entities) > 0 {
-			p.log(fmt.Sprintf("Detected %d potential entities", len(entities)))
-			// Create volatile glossary from detected entities
-			p.Config.Glossary = make(map[string]string)
-			for _, e := range entities {
-				// Only add high-confidence entities (preserve original form)
-				if e.Confidence >= 0.7 {
-					p.Config.Glossary[e.Text] = e.Text
-				}
-			}
-		}
+	// Step 3.5: NER scan; candidates accumulate in the series glossary
+	if err := p.applySeriesGlossary(ctx, subFile.Lines); err != nil {
+		return err
 	}
 
-	// Step 4: Batch and translate
-	batches := parser.BatchLines(subFile.Lines, p.Config.BatchSize)
+	// Step 4: Resolve repeated lines and cache hits, then batch the rest
+	var done map[lineKey]string
+	if p.ResumeState != nil {
+		done = resumedTranslations(subFile.Lines, p.ResumeState.TranslatedLines)
+		p.log(fmt.Sprintf("Resuming with %d lines already translated", len(p.ResumeState.TranslatedLines)))
+	}
+
+	plan := p.planTranslation(subFile.Lines, done)
+	p.reportDedup(plan.stats)
+
+	batches := p.batchLines(plan.pending)
 	p.log(fmt.Sprintf("Split into %d batches", len(batches)))
 
+	translations := plan.resolved
 	translatedLines := []parser.SubtitleLine{}
-	var contextWindow []parser.SubtitleLine
-
-	startBatch := 0
 	if p.ResumeState != nil {
-		startBatch = p.ResumeState.CompletedBatches
 		translatedLines = p.ResumeState.TranslatedLines
-		p.log(fmt.Sprintf("Resuming from batch %d/%d", startBatch+1, len(batches)))
 	}
+	var contextWindow []parser.SubtitleLine
 
-	for i := startBatch; i < len(batches); i++ {
+	for i := range batches {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
 
+		if err := p.checkBudget(batches[i]); err != nil {
+			return err
+		}
+
 		p.log(fmt.Sprintf("Processing batch %d/%d...", i+1, len(batches)))
 		p.progress(i+1, len(batches))
 
@@ -176,6 +265,7 @@ func (p *Pipeline) Execute(ctx context.Context) error {
 			ContextLines: contextWindow,
 			BatchIndex:   i,
 			TotalBatches: len(batches),
+			Prefetched:   true,
 		}
 
 		translated, err := p.translateBatch(ctx, batch)
@@ -183,6 +273,9 @@ func (p *Pipeline) Execute(ctx context.Context) error {
 			return fmt.Errorf("batch %d failed: %w", i+1, err)
 		}
 
+		for j, line := range translated {
+			translations[keyOf(batches[i][j])] = line.Text
+		}
 		translatedLines = append(translatedLines, translated...)
 
 		// Update sliding window with last N lines
@@ -198,6 +291,19 @@ func (p *Pipeline) Execute(ctx context.Context) error {
 		}
 	}
 
+	// Every occurrence of a line gets the translation of its first occurrence
+	translatedLines = fanOut(subFile.Lines, translations)
+
+	// Step 4.5: Target-language typography (quotes, ellipses, dashes)
+	translatedLines = p.normalizeTypography(subFile.Lines, translatedLines)
+
+	// Step 4.6: Quality review of the whole file before muxing
+	translatedLines, err = p.reviewQuality(ctx, subFile.Lines, translatedLines)
+	if err != nil {
+		return fmt.Errorf("quality review failed: %w", err)
+	}
+	p.writeLintReports(subFile.Lines, translatedLines)
+
 	// Step 5: Reassemble subtitle file
 	p.log("Reassembling subtitle file...")
 	var content string
@@ -262,20 +368,136 @@ func (p *Pipeline) Execute(ctx context.Context) error {
 	// Clean up resume state
 	p.clearResumeState()
 
+	if p.Usage.Requests > 0 {
+		p.log(fmt.Sprintf("Usage: %d prompt + %d completion tokens (%d cached), $%.4f",
+			p.Usage.PromptTokens, p.Usage.CompletionTokens, p.Usage.CachedTokens, p.Usage.Cost))
+	}
+
 	p.log("Translation complete!")
 	return nil
 }
 
-// translateBatch translates a single batch with anti-desync protocol
+// translateBatch translates a single batch with anti-desync protocol, then
+// runs the quality gate on it
 func (p *Pipeline) translateBatch(ctx context.Context, batch TranslationBatch) ([]parser.SubtitleLine, error) {
-	return p.translateBatchWithRetry(ctx, batch, 0)
+	translated, err := p.translateBatchWithRetry(ctx, batch, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	translated, _, err = p.qualityGate(ctx, batch.Lines, translated, batch.ContextLines, qualityRepairAttempts)
+	return translated, err
+}
+
+// batchLines splits lines using the configured batching strategy
+func (p *Pipeline) batchLines(lines []parser.SubtitleLine) [][]parser.SubtitleLine {
+	return p.batcher().Batch(lines)
+}
+
+// batcher builds the batching strategy for this pipeline
+func (p *Pipeline) batcher() parser.Batcher {
+	if p.Config.Batcher != nil {
+		return p.Config.Batcher
+	}
+
+	if p.Config.BatchStrategy == parser.BatchStrategyFixed {
+		return parser.FixedBatcher{Size: p.Config.BatchSize}
+	}
+
+	estimator := tokenizer.NewEstimator().ForModel(p.Config.Model)
+	count := func(text string) int {
+		return estimator.EstimateTokens(text) + batchLineOverhead
+	}
+
+	budget := p.Config.MaxBatchTokens
+	if budget <= 0 {
+		budget = p.batchTokenBudget()
+	}
+
+	if p.Config.BatchStrategy == parser.BatchStrategyTokens {
+		return parser.TokenBatcher{MaxTokens: budget, MaxLines: p.Config.BatchSize, Count: count}
+	}
+
+	return parser.SceneBatcher{
+		MaxTokens: budget,
+		MaxLines:  p.Config.BatchSize,
+		Count:     count,
+		SceneGap:  p.Config.SceneGap,
+	}
+}
+
+// batchTokenBudget derives the batch token budget from the model's context window
+func (p *Pipeline) batchTokenBudget() int {
+	contextLength := 0
+	if info, ok := catalog.Shared().Lookup(p.Config.ProviderName, p.Config.Model); ok {
+		contextLength = info.ContextLength
+	}
+	if contextLength == 0 && p.Config.ProviderName == "local" {
+		contextLength = localContextLength
+	}
+	return BatchTokenBudget(contextLength)
+}
+
+// BatchTokenBudget returns the source tokens per batch for a context window
+// (0 = unknown). Input and output share the window, and the JSON framing of
+// the response adds roughly a fifth on top of the translated text.
+func BatchTokenBudget(contextLength int) int {
+	if contextLength <= 0 {
+		return DefaultBatchTokens
+	}
+
+	budget := (contextLength - promptReserveTokens) * 2 / 5
+	if budget < minBatchTokens {
+		return minBatchTokens
+	}
+	if budget > maxBatchTokens {
+		return maxBatchTokens
+	}
+	return budget
+}
+
+// catalogRefreshTimeout bounds the model list request made at job start
+const catalogRefreshTimeout = 15 * time.Second
+
+// refreshCatalog refreshes the provider's models in c when they are stale.
+// Failures keep the cached list and are only logged.
+func (p *Pipeline) refreshCatalog(ctx context.Context, c *catalog.Catalog) {
+	fetcher, ok := p.Provider.(catalog.Fetcher)
+	if !ok || p.Config.ProviderName == "" || !c.IsStale(p.Config.ProviderName) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, catalogRefreshTimeout)
+	defer cancel()
+	if err := c.Refresh(ctx, p.Config.ProviderName, fetcher); err != nil {
+		p.log(fmt.Sprintf("Warning: could not refresh model pricing, using cached prices: %v", err))
+	}
+}
+
+// cacheScope returns the translation memory scope of the current job settings
+func (p *Pipeline) cacheScope() db.Scope {
+	return db.Scope{
+		LangPair:     db.JoinLangPair(p.Config.SourceLang, p.Config.TargetLang),
+		Model:        p.Config.Model,
+		Profile:      p.Config.Profile,
+		GlossaryHash: db.GlossaryFingerprint(p.Config.Glossary),
+		ProjectID:    p.Config.ProjectID,
+	}
+}
+
+// fuzzyMatch looks up a near-identical cached line; a threshold of 1 disables it
+func (p *Pipeline) fuzzyMatch(text string, scope db.Scope) (*db.CacheEntry, bool) {
+	if p.Config.FuzzyThreshold >= 1 {
+		return nil, false
+	}
+	return p.Cache.GetScopedFuzzyMatch(text, scope, p.Config.CachePolicy, p.Config.FuzzyThreshold)
 }
 
 // translateBatchWithRetry implements self-healing split strategy
 // maxDepth prevents infinite recursion (max 3 levels: 50 -> 25 -> 12 -> 6)
 func (p *Pipeline) translateBatchWithRetry(ctx context.Context, batch TranslationBatch, depth int) ([]parser.SubtitleLine, error) {
 	const maxRetryDepth = 3
-	langPair := fmt.Sprintf("%s->%s", p.Config.SourceLang, p.Config.TargetLang)
+	scope := p.cacheScope()
 
 	// Check cache for each line
 	cachedCount := 0
@@ -283,11 +505,13 @@ func (p *Pipeline) translateBatchWithRetry(ctx context.Context, batch Translatio
 	needsTranslation := []int{}
 
 	for i, line := range batch.Lines {
-		if cached, found := p.Cache.GetExactMatch(line.Text, langPair); found {
+		if batch.Prefetched || !cacheable(line) {
+			needsTranslation = append(needsTranslation, i)
+		} else if cached, found := p.Cache.GetScopedMatch(line.Text, scope, p.Config.CachePolicy); found {
 			translatedLines[i] = line
-			translatedLines[i].Text = cached
+			translatedLines[i].Text = cached.TranslatedText
 			cachedCount++
-		} else if cached, found := p.Cache.GetFuzzyMatch(line.Text, langPair, 0.95); found {
+		} else if cached, found := p.fuzzyMatch(line.Text, scope); found {
 			translatedLines[i] = line
 			translatedLines[i].Text = cached.TranslatedText
 			cachedCount++
@@ -305,16 +529,23 @@ func (p *Pipeline) translateBatchWithRetry(ctx context.Context, batch Translatio
 	}
 
 	// Build system prompt with context and glossary
-	systemPrompt := p.buildSystemPrompt(batch.ContextLines)
+	requested := make([]parser.SubtitleLine, 0, len(needsTranslation))
+	for _, idx := range needsTranslation {
+		requested = append(requested, batch.Lines[idx])
+	}
+	systemPrompt := p.buildSystemPrompt(requested, batch.ContextLines)
 
 	// Prepare payload for AI
 	payload := []ai.Line{}
 	for _, idx := range needsTranslation {
-		payload = append(payload, ai.Line{ID: idx, Text: batch.Lines[idx].Text})
+		translatedLines[idx] = batch.Lines[idx]
+		payload = append(payload, ai.Line{ID: idx, Text: batch.Lines[idx].Text, Speaker: batch.Lines[idx].Speaker})
 	}
 
 	// Send to AI provider
-	response, err := p.Provider.SendBatch(ctx, payload, systemPrompt)
+	guard := p.protectTerms(payload)
+	response, usage, err := p.Provider.SendBatch(ctx, payload, guard.prompt(systemPrompt))
+	p.recordUsage(usage)
 
 	// Handle errors or desync with self-healing split strategy
 	if err != nil || len(response) != len(needsTranslation) {
@@ -336,6 +567,7 @@ func (p *Pipeline) translateBatchWithRetry(ctx context.Context, batch Translatio
 				ContextLines: batch.ContextLines,
 				BatchIndex:   batch.BatchIndex,
 				TotalBatches: batch.TotalBatches,
+				Prefetched:   batch.Prefetched,
 			}
 
 			batchB := TranslationBatch{
@@ -343,6 +575,7 @@ func (p *Pipeline) translateBatchWithRetry(ctx context.Context, batch Translatio
 				ContextLines: batch.Lines[max(0, mid-p.Config.SlidingWindowSize):mid], // Use end of A as context for B
 				BatchIndex:   batch.BatchIndex,
 				TotalBatches: batch.TotalBatches,
+				Prefetched:   batch.Prefetched,
 			}
 
 			p.log(fmt.Sprintf("  └─ Split %da (Lines 1-%d) processing...", batch.BatchIndex+1, mid))
@@ -369,29 +602,13 @@ func (p *Pipeline) translateBatchWithRetry(ctx context.Context, batch Translatio
 	}
 
 	// Apply translations
+	guard.restore(response)
 	for _, resp := range response {
 		if resp.ID >= 0 && resp.ID < len(translatedLines) {
 			translatedLines[resp.ID].Text = resp.Text
 			// Cache the translation
-			p.Cache.SaveTranslation(batch.Lines[resp.ID].Text, resp.Text, langPair)
-		}
-	}
-
-	// Quality Gate: Run linter on translated lines
-	if depth == 0 { // Only lint at top level to avoid retry loops
-		lintResult := p.lintTranslation(translatedLines)
-		if !lintResult.PassedAll && len(lintResult.Issues) > 0 {
-			highSeverityCount := 0
-			for _, issue := range lintResult.Issues {
-				if issue.Severity == linter.SeverityHigh {
-					highSeverityCount++
-				}
-			}
-
-			// Only retry if high severity issues found
-			if highSeverityCount > 0 && depth < maxRetryDepth {
-				p.log(fmt.Sprintf("  Quality Gate: %d HIGH severity issues, retrying...", highSeverityCount))
-				return p.translateBatchWithRetry(ctx, batch, depth+1)
+			if cacheable(batch.Lines[resp.ID]) {
+				p.Cache.SaveScopedTranslation(batch.Lines[resp.ID].Text, resp.Text, scope)
 			}
 		}
 	}
@@ -399,8 +616,9 @@ func (p *Pipeline) translateBatchWithRetry(ctx context.Context, batch Translatio
 	return translatedLines, nil
 }
 
-// buildSystemPrompt creates system prompt with sliding window context and glossary
-func (p *Pipeline) buildSystemPrompt(contextLines []parser.SubtitleLine) string {
+// buildSystemPrompt creates the system prompt for lines, with glossary,
+// character profiles and sliding window context
+func (p *Pipeline) buildSystemPrompt(lines, contextLines []parser.SubtitleLine) string {
 	prompt := p.Config.SystemPrompt
 
 	// Inject glossary
@@ -414,12 +632,14 @@ func (p *Pipeline) buildSystemPrompt(contextLines []parser.SubtitleLine) string
 		prompt = strings.Replace(prompt, "{{glossary}}", "", 1)
 	}
 
+	prompt += p.characterPrompt(lines, contextLines)
+
 	// Add sliding window context (passive context from previous batch)
 	// Per spec: last 3 lines of Batch N appended as read-only context at start of Batch N+1
 	if len(contextLines) > 0 {
 		contextText := "\n\n---\nPASSIVE CONTEXT (Previous lines for reference - DO NOT translate these):\n"
 		for i, line := range contextLines {
-			contextText += fmt.Sprintf("%d. %s\n", i+1, line.Text)
+			contextText += fmt.Sprintf("%d. %s\n", i+1, contextLine(line))
 		}
 		contextText += "---\n"
 		prompt += contextText
@@ -428,12 +648,101 @@ func (p *Pipeline) buildSystemPrompt(contextLines []parser.SubtitleLine) string
 	return prompt
 }
 
+// recordUsage aggregates and persists the usage of a provider request.
+// When the provider does not report cost, it is computed from model pricing.
+func (p *Pipeline) recordUsage(usage ai.Usage) {
+	if usage.IsZero() {
+		return
+	}
+
+	if !usage.CostReported {
+		usage.Cost = tokenizer.NewEstimator().WithCatalog(catalog.Shared(), p.Config.ProviderName).CalculateCost(usage.PromptTokens, usage.CompletionTokens, p.Config.Model)
+	}
+
+	p.Usage.Add(usage)
+	if p.Budget != nil {
+		p.Budget.Add(usage.Cost)
+	}
+
+	if p.Cache != nil {
+		if err := p.Cache.RecordUsage(db.UsageRecord{
+			JobID:            p.Config.JobID,
+			FilePath:         p.Config.InputPath,
+			Provider:         p.Config.ProviderName,
+			Model:            p.Config.Model,
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			CachedTokens:     usage.CachedTokens,
+			Cost:             usage.Cost,
+			CostReported:     usage.CostReported,
+		}); err != nil {
+			p.log(fmt.Sprintf("Warning: Failed to record usage: %v", err))
+		}
+	}
+
+	if p.UsageCallback != nil {
+		p.UsageCallback(usage)
+	}
+}
+
+// checkBudget verifies that the next batch fits within the spending limits.
+// Resume state is saved after every batch, so an aborted job can be resumed.
+func (p *Pipeline) checkBudget(lines []parser.SubtitleLine) error {
+	if p.Budget == nil || !p.Budget.Enabled() {
+		return nil
+	}
+
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = line.Text
+	}
+	projected := tokenizer.NewEstimator().WithCatalog(catalog.Shared(), p.Config.ProviderName).EstimateCost(texts, p.Config.Model).CostUSD
+
+	warnings, exceeded := p.Budget.Check(projected)
+	for _, w := range warnings {
+		p.log(fmt.Sprintf("Warning: Budget at %.0f%% of %s", w.Total()/w.Limit*100, w.String()))
+	}
+
+	if exceeded == nil {
+		return nil
+	}
+
+	p.log(fmt.Sprintf("Warning: Budget exceeded: %s", exceeded.String()))
+
+	if p.BudgetCallback != nil && p.BudgetCallback(*exceeded) {
+		p.Budget.Approve(exceeded.Scope)
+		p.log(fmt.Sprintf("Budget override approved for %s limit, continuing...", exceeded.Scope))
+		return nil
+	}
+
+	if ResumeStateFor(p.Config.InputPath) != nil {
+		p.log("Job stopped by budget limit. Progress was saved: run the job on this file again to resume.")
+	} else {
+		p.log("Job stopped by budget limit.")
+	}
+	return fmt.Errorf("%w: %s", ErrBudgetExceeded, exceeded.String())
+}
+
 func (p *Pipeline) log(msg string) {
 	if p.LogCallback != nil {
 		p.LogCallback(msg)
 	}
 }
 
+// reportDedup logs and publishes how many lines were resolved before batching
+func (p *Pipeline) reportDedup(stats DedupStats) {
+	p.Dedup = stats
+	if stats.CacheHits > 0 {
+		p.log(fmt.Sprintf("Cache hit: %d/%d unique lines", stats.CacheHits, stats.UniqueLines))
+	}
+	if stats.DuplicateLines > 0 {
+		p.log(fmt.Sprintf("Deduplicated %d repeated lines (~%d tokens saved)", stats.DuplicateLines, stats.TokensSaved))
+	}
+	if p.DedupCallback != nil {
+		p.DedupCallback(stats)
+	}
+}
+
 func (p *Pipeline) progress(current, total int) {
 	if p.ProgressCallback != nil {
 		p.ProgressCallback(current, total)
@@ -486,20 +795,27 @@ func LoadResumeState(path string) (*ResumeState, error) {
 	return &state, nil
 }
 
-// lintTranslation runs quality checks on translated lines
-func (p *Pipeline) lintTranslation(lines []parser.SubtitleLine) linter.Result {
-	// Extract text from lines
-	texts := make([]string, len(lines))
-	for i, line := range lines {
-		texts[i] = line.Text
+// ResumeStateFor returns the saved progress of a video, or nil when the
+// resume file is missing, unreadable or belongs to another file of the folder
+func ResumeStateFor(inputPath string) *ResumeState {
+	state, err := LoadResumeState(inputPath)
+	if err != nil || state.FilePath != inputPath || len(state.TranslatedLines) == 0 {
+		return nil
 	}
+	return state
+}
 
-	// Build lint options
+// lintTranslation runs quality checks on translated lines against their sources
+func (p *Pipeline) lintTranslation(sources, lines []parser.SubtitleLine) linter.Result {
 	opts := linter.CheckOptions{
 		SourceLang: p.Config.SourceLang,
 		TargetLang: p.Config.TargetLang,
+		Format:     p.format,
 		Glossary:   p.Config.Glossary,
+		Terms:      p.Config.GlossaryTerms,
+		Source:     sources,
+		Rules:      p.Config.LintRules,
 	}
 
-	return linter.Check(texts, opts)
+	return linter.Check(lines, opts)
 }
@@ -2,29 +2,44 @@ package pipeline
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
+	"github.com/lsilvatti/bakasub/internal/core/db"
 	"github.com/lsilvatti/bakasub/internal/core/parser"
 )
 
+// openTestCache opens a throwaway cache database for the test
+func openTestCache(t *testing.T) *db.Cache {
+	t.Helper()
+	cache, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open cache: %v", err)
+	}
+	t.Cleanup(func() { cache.Close() })
+	return cache
+}
+
 // MockProvider implements LLMProvider for testing
 type MockProvider struct {
 	Lines       []ai.Line
+	Usage       ai.Usage
 	Error       error
 	CallCount   int
 	LastPayload []ai.Line
 	LastPrompt  string
 }
 
-func (m *MockProvider) SendBatch(ctx context.Context, payload []ai.Line, systemPrompt string) ([]ai.Line, error) {
+func (m *MockProvider) SendBatch(ctx context.Context, payload []ai.Line, systemPrompt string) ([]ai.Line, ai.Usage, error) {
 	m.CallCount++
 	m.LastPayload = payload
 	m.LastPrompt = systemPrompt
 
 	if m.Error != nil {
-		return nil, m.Error
+		return nil, m.Usage, m.Error
 	}
 
 	// Return translated lines
@@ -35,7 +50,7 @@ func (m *MockProvider) SendBatch(ctx context.Context, payload []ai.Line, systemP
 			Text: "Translated: " + line.Text,
 		}
 	}
-	return result, nil
+	return result, m.Usage, nil
 }
 
 func (m *MockProvider) ValidateKey(ctx context.Context) bool {
@@ -83,6 +98,79 @@ func TestNewPipelineDefaultBatchSize(t *testing.T) {
 	}
 }
 
+// TestBatchLinesByTokenBudget tests token-budgeted batching
+func TestBatchLinesByTokenBudget(t *testing.T) {
+	lines := make([]parser.SubtitleLine, 40)
+	for i := range lines {
+		lines[i] = parser.SubtitleLine{Index: i, Text: "This line has a handful of tokens in it."}
+	}
+
+	fixed := New(nil, nil, &PipelineConfig{})
+	if got := len(fixed.batchLines(lines)); got != 1 {
+		t.Errorf("expected 1 fixed-size batch, got %d", got)
+	}
+
+	budgeted := New(nil, nil, &PipelineConfig{MaxBatchTokens: 100})
+	if budgeted.Config.BatchSize != 0 {
+		t.Errorf("expected no line cap with a token budget, got %d", budgeted.Config.BatchSize)
+	}
+
+	batches := budgeted.batchLines(lines)
+	if len(batches) < 4 {
+		t.Fatalf("expected the budget to split 40 lines into several batches, got %d", len(batches))
+	}
+
+	total := 0
+	for _, b := range batches {
+		total += len(b)
+	}
+	if total != len(lines) {
+		t.Errorf("expected all %d lines batched, got %d", len(lines), total)
+	}
+}
+
+// TestBatchTokenBudget tests deriving the budget from the context window
+func TestBatchTokenBudget(t *testing.T) {
+	tests := []struct {
+		contextLength int
+		want          int
+	}{
+		{0, DefaultBatchTokens},
+		{2048, minBatchTokens},
+		{4096, 838},
+		{8192, 2476},
+		{128000, maxBatchTokens},
+	}
+
+	for _, tt := range tests {
+		if got := BatchTokenBudget(tt.contextLength); got != tt.want {
+			t.Errorf("BatchTokenBudget(%d) = %d, want %d", tt.contextLength, got, tt.want)
+		}
+	}
+}
+
+// TestBatcherStrategies tests strategy selection
+func TestBatcherStrategies(t *testing.T) {
+	fixed := New(nil, nil, &PipelineConfig{})
+	if _, ok := fixed.batcher().(parser.FixedBatcher); !ok {
+		t.Errorf("expected fixed batcher by default, got %T", fixed.batcher())
+	}
+
+	scene := New(nil, nil, &PipelineConfig{BatchStrategy: parser.BatchStrategyScene})
+	b, ok := scene.batcher().(parser.SceneBatcher)
+	if !ok {
+		t.Fatalf("expected scene batcher, got %T", scene.batcher())
+	}
+	if b.MaxTokens <= 0 || b.MaxLines != 0 {
+		t.Errorf("unexpected scene batcher limits: tokens=%d lines=%d", b.MaxTokens, b.MaxLines)
+	}
+
+	custom := New(nil, nil, &PipelineConfig{Batcher: parser.FixedBatcher{Size: 7}})
+	if got := custom.batcher().(parser.FixedBatcher).Size; got != 7 {
+		t.Errorf("expected custom batcher to be used, got size %d", got)
+	}
+}
+
 // TestNewPipelineDefaultSlidingWindow tests default sliding window size
 func TestNewPipelineDefaultSlidingWindow(t *testing.T) {
 	config := &PipelineConfig{
@@ -275,6 +363,69 @@ func TestPipelineWithResume(t *testing.T) {
 	}
 }
 
+// catalogProvider is a provider that can list its models
+type catalogProvider struct {
+	MockProvider
+	Models  []catalog.ModelInfo
+	Fetches int
+}
+
+func (c *catalogProvider) ListModelInfo(ctx context.Context) ([]catalog.ModelInfo, error) {
+	c.Fetches++
+	return c.Models, nil
+}
+
+// TestRefreshCatalog tests that stale pricing is refreshed at job start
+func TestRefreshCatalog(t *testing.T) {
+	provider := &catalogProvider{Models: []catalog.ModelInfo{{ID: "gpt-4o", InputPer1M: 2.5, PricingKnown: true}}}
+	p := New(provider, nil, &PipelineConfig{Model: "gpt-4o", ProviderName: "openai"})
+	c := catalog.New("")
+
+	p.refreshCatalog(context.Background(), c)
+	if info, ok := c.Lookup("openai", "gpt-4o"); !ok || info.InputPer1M != 2.5 || provider.Fetches != 1 {
+		t.Fatalf("stale catalog should be refreshed, got %+v (%d fetches)", info, provider.Fetches)
+	}
+
+	p.refreshCatalog(context.Background(), c)
+	if provider.Fetches != 1 {
+		t.Errorf("fresh catalog should not be fetched again, got %d fetches", provider.Fetches)
+	}
+
+	c.SetTTL(0)
+	provider.Models[0].InputPer1M = 5
+	p.refreshCatalog(context.Background(), c)
+	if info, _ := c.Lookup("openai", "gpt-4o"); info.InputPer1M != 5 {
+		t.Errorf("expired prices should be replaced, got %+v", info)
+	}
+}
+
+// TestResumeStateFor tests that saved progress is only picked up by its own file
+func TestResumeStateFor(t *testing.T) {
+	dir := t.TempDir()
+	video := filepath.Join(dir, "ep01.mkv")
+	p := New(nil, nil, &PipelineConfig{InputPath: video})
+
+	if ResumeStateFor(video) != nil {
+		t.Fatal("no state should be found before saving")
+	}
+	if err := p.saveResumeState(2, 5, []parser.SubtitleLine{{Index: 0, Text: "Olá"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	state := ResumeStateFor(video)
+	if state == nil || state.CompletedBatches != 2 || state.TranslatedLines[0].Text != "Olá" {
+		t.Errorf("unexpected state %+v", state)
+	}
+	if ResumeStateFor(filepath.Join(dir, "ep02.mkv")) != nil {
+		t.Error("another file of the folder should not resume this state")
+	}
+
+	p.clearResumeState()
+	if ResumeStateFor(video) != nil {
+		t.Error("cleared state should not be found")
+	}
+}
+
 // TestBuildSystemPrompt tests system prompt building
 func TestBuildSystemPrompt(t *testing.T) {
 	config := &PipelineConfig{
@@ -292,10 +443,148 @@ func TestBuildSystemPrompt(t *testing.T) {
 		{Index: 1, Text: "Previous line 2"},
 	}
 
-	prompt := pipeline.buildSystemPrompt(contextLines)
+	prompt := pipeline.buildSystemPrompt(nil, contextLines)
 
 	// Prompt should not be empty
 	if prompt == "" {
 		t.Error("buildSystemPrompt returned empty string")
 	}
 }
+
+// TestTranslateBatchRecordsUsage tests that provider usage is aggregated and persisted
+func TestTranslateBatchRecordsUsage(t *testing.T) {
+	provider := &MockProvider{
+		Usage: ai.Usage{PromptTokens: 1000, CompletionTokens: 500, Requests: 1},
+	}
+	cache := openTestCache(t)
+	config := &PipelineConfig{
+		SourceLang: "en",
+		TargetLang: "pt-br",
+		Model:      "openai/gpt-4o-mini",
+		JobID:      "job-usage",
+	}
+
+	p := New(provider, cache, config)
+	var callbacks int
+	p.UsageCallback = func(batch ai.Usage) {
+		callbacks++
+	}
+
+	batch := TranslationBatch{
+		Lines: []parser.SubtitleLine{
+			{Index: 0, Text: "Hello there"},
+			{Index: 1, Text: "General Kenobi"},
+		},
+	}
+
+	if _, err := p.translateBatch(context.Background(), batch); err != nil {
+		t.Fatalf("translateBatch failed: %v", err)
+	}
+
+	if callbacks != 1 {
+		t.Errorf("UsageCallback called %d times, want 1", callbacks)
+	}
+	if p.Usage.PromptTokens != 1000 || p.Usage.CompletionTokens != 500 {
+		t.Errorf("unexpected aggregated usage: %+v", p.Usage)
+	}
+	// Cost is not reported by the mock, so it is computed from gpt-4o-mini pricing
+	if p.Usage.Cost <= 0 {
+		t.Errorf("expected estimated cost > 0, got %f", p.Usage.Cost)
+	}
+
+	summary, err := cache.GetJobUsage("job-usage")
+	if err != nil {
+		t.Fatalf("GetJobUsage failed: %v", err)
+	}
+	if summary.Requests != 1 || summary.PromptTokens != 1000 {
+		t.Errorf("unexpected persisted usage: %+v", summary)
+	}
+}
+
+// TestTranslateBatchCacheScope tests that cached lines are only reused within the allowed scope
+func TestTranslateBatchCacheScope(t *testing.T) {
+	cache := openTestCache(t)
+	batch := TranslationBatch{
+		Lines: []parser.SubtitleLine{{Index: 0, Text: "Believe it!"}},
+	}
+
+	anime := New(&MockProvider{}, cache, &PipelineConfig{
+		SourceLang: "en", TargetLang: "pt-br", Model: "gpt-4o", Profile: "anime", ProjectID: "show",
+	})
+	if _, err := anime.translateBatch(context.Background(), batch); err != nil {
+		t.Fatalf("translateBatch failed: %v", err)
+	}
+
+	// Same scope: served from cache
+	again := &MockProvider{}
+	p := New(again, cache, &PipelineConfig{
+		SourceLang: "en", TargetLang: "pt-br", Model: "gpt-4o", Profile: "anime", ProjectID: "show",
+	})
+	if _, err := p.translateBatch(context.Background(), batch); err != nil {
+		t.Fatalf("translateBatch failed: %v", err)
+	}
+	if again.CallCount != 0 {
+		t.Errorf("expected cache hit in the same scope, provider called %d times", again.CallCount)
+	}
+
+	// Other profile under the default strict policy: translated again
+	documentary := &MockProvider{}
+	p = New(documentary, cache, &PipelineConfig{
+		SourceLang: "en", TargetLang: "pt-br", Model: "gpt-4o", Profile: "documentary", ProjectID: "show",
+	})
+	if _, err := p.translateBatch(context.Background(), batch); err != nil {
+		t.Fatalf("translateBatch failed: %v", err)
+	}
+	if documentary.CallCount != 1 {
+		t.Errorf("expected strict policy to miss for another profile, provider called %d times", documentary.CallCount)
+	}
+
+	// Same project policy: reused across profiles
+	relaxed := &MockProvider{}
+	p = New(relaxed, cache, &PipelineConfig{
+		SourceLang: "en", TargetLang: "pt-br", Model: "gemini-2.0-flash", Profile: "movie", ProjectID: "show",
+		CachePolicy: db.PolicySameProject,
+	})
+	if _, err := p.translateBatch(context.Background(), batch); err != nil {
+		t.Fatalf("translateBatch failed: %v", err)
+	}
+	if relaxed.CallCount != 0 {
+		t.Errorf("expected same-project hit, provider called %d times", relaxed.CallCount)
+	}
+}
+
+// TestTranslateBatchFuzzyThreshold tests that the configured threshold controls fuzzy hits
+func TestTranslateBatchFuzzyThreshold(t *testing.T) {
+	cache := openTestCache(t)
+	scope := db.Scope{LangPair: "en->pt-br"}
+	cache.SaveScopedTranslation("I will never forgive you for this", "Nunca vou te perdoar por isso", scope)
+
+	// Differs by the final punctuation only
+	batch := TranslationBatch{
+		Lines: []parser.SubtitleLine{{Index: 0, Text: "I will never forgive you for this!"}},
+	}
+
+	tests := []struct {
+		name      string
+		threshold float64
+		wantCalls int
+	}{
+		{"default", 0, 0},
+		{"exact only", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &MockProvider{}
+			p := New(provider, cache, &PipelineConfig{
+				SourceLang: "en", TargetLang: "pt-br", FuzzyThreshold: tt.threshold,
+			})
+			if _, err := p.translateBatch(context.Background(), batch); err != nil {
+				t.Fatalf("translateBatch failed: %v", err)
+			}
+			if provider.CallCount != tt.wantCalls {
+				t.Errorf("provider called %d times, want %d", provider.CallCount, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/lintreport"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// QualityAction is what to do with the issues the quality gate could not repair
+type QualityAction int
+
+const (
+	QualityAutoFix      QualityAction = iota // Re-request the offending lines once more
+	QualityManualReview                      // Hand the lines to the review editor before muxing
+	QualityIgnore                            // Mux the translation as it is
+)
+
+// QualityReport lists the issues left in a file before it is muxed
+type QualityReport struct {
+	Source []parser.SubtitleLine // Original lines
+	Lines  []parser.SubtitleLine // Translated lines
+	Issues []linter.Issue        // LineID is the 1-based position in Lines
+	Scope  db.Scope              // Translation memory scope of the lines
+}
+
+// QualityDecision answers a QualityReport. For QualityManualReview, Lines
+// holds the reviewed translation (nil keeps the lines unchanged).
+type QualityDecision struct {
+	Action QualityAction
+	Lines  []parser.SubtitleLine
+}
+
+// appliedFix is a lint fix applied to a translation, kept for the lint report
+type appliedFix struct {
+	issue  linter.Issue
+	before string // Translation before the fix
+}
+
+// qualityRepairAttempts is how many times a batch's offending lines are re-requested
+const qualityRepairAttempts = 1
+
+// reviewLogLimit caps the issues listed in the job log before muxing
+const reviewLogLimit = 20
+
+// offendingLines groups the issues worth a new request by line index. Only
+// HIGH issues qualify; the rest are left to review.
+func offendingLines(issues []linter.Issue) map[int][]linter.Issue {
+	offending := make(map[int][]linter.Issue)
+	for _, issue := range issues {
+		if issue.Severity == linter.SeverityHigh {
+			offending[issue.LineID-1] = append(offending[issue.LineID-1], issue)
+		}
+	}
+	return offending
+}
+
+// qualityGate checks translated lines against their sources, applies the
+// linter's auto-fixes and re-requests the lines still failing, describing
+// their issues in the prompt. It returns the repaired lines and the issues left.
+func (p *Pipeline) qualityGate(ctx context.Context, sources, lines []parser.SubtitleLine, contextLines []parser.SubtitleLine, attempts int) ([]parser.SubtitleLine, []linter.Issue, error) {
+	lines = append([]parser.SubtitleLine(nil), lines...)
+	result := p.autoFix(sources, lines)
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		offending := offendingLines(result.Issues)
+		if len(offending) == 0 {
+			break
+		}
+		if err := p.rerequestLines(ctx, sources, lines, contextLines, offending); err != nil {
+			return lines, result.Issues, err
+		}
+		result = p.autoFix(sources, lines)
+	}
+
+	return lines, result.Issues, nil
+}
+
+// autoFix lints the lines and applies the auto-fixable issues in place,
+// recording fixed lines in the translation memory. It returns the issues left.
+func (p *Pipeline) autoFix(sources, lines []parser.SubtitleLine) linter.Result {
+	result := p.lintTranslation(sources, lines)
+
+	counts := make(map[string]int)
+	for _, issue := range result.Issues {
+		if issue.AutoFixable {
+			counts[issue.RuleID]++
+		}
+	}
+	if len(counts) == 0 {
+		return result
+	}
+
+	fixed := linter.AutoFix(lines, result.Issues)
+	for _, issue := range result.Issues {
+		if i := issue.LineID - 1; issue.AutoFixable && i < len(sources) && fixed[i].Text != lines[i].Text {
+			p.recordFix(sources[i].Text, issue, lines[i].Text)
+		}
+	}
+
+	scope := p.cacheScope()
+	changed := 0
+	for i := range lines {
+		if fixed[i].Text == lines[i].Text {
+			continue
+		}
+		changed++
+		lines[i].Text = fixed[i].Text
+		if p.Cache != nil && i < len(sources) && cacheable(sources[i]) {
+			p.Cache.SaveWithProvenance(sources[i].Text, fixed[i].Text, scope, db.ProvenanceLintFixed)
+		}
+	}
+	if changed > 0 {
+		p.log(fmt.Sprintf("  Quality Gate: auto-fixed %d lines (%s)", changed, ruleCounts(counts)))
+	}
+
+	return p.lintTranslation(sources, lines)
+}
+
+// rerequestLines translates the offending lines again, telling the model
+// what was wrong with its previous attempt
+func (p *Pipeline) rerequestLines(ctx context.Context, sources, lines, contextLines []parser.SubtitleLine, offending map[int][]linter.Issue) error {
+	indexes := make([]int, 0, len(offending))
+	for i := range offending {
+		if i >= 0 && i < len(sources) && i < len(lines) {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+	sort.Ints(indexes)
+
+	payload := make([]ai.Line, len(indexes))
+	requested := make([]parser.SubtitleLine, len(indexes))
+	var feedback strings.Builder
+	feedback.WriteString("\n\n---\nQUALITY FIX: these lines were translated before but failed quality checks. Translate them again and avoid the problems listed:\n")
+	for n, i := range indexes {
+		payload[n] = ai.Line{ID: i, Text: sources[i].Text, Speaker: sources[i].Speaker}
+		requested[n] = sources[i]
+		fmt.Fprintf(&feedback, "- ID %d, previous translation %q:", i, lines[i].Text)
+		for _, issue := range offending[i] {
+			fmt.Fprintf(&feedback, " %s (%s);", issue.IssueType, issue.Suggestion)
+		}
+		feedback.WriteString("\n")
+	}
+	feedback.WriteString("---\n")
+
+	if err := p.checkBudget(requested); err != nil {
+		return err
+	}
+
+	p.log(fmt.Sprintf("  Quality Gate: re-requesting %d lines (%s)", len(indexes), issueSummary(offending)))
+	guard := p.protectTerms(payload)
+	response, usage, err := p.Provider.SendBatch(ctx, payload, guard.prompt(p.buildSystemPrompt(requested, contextLines))+feedback.String())
+	p.recordUsage(usage)
+	if err != nil {
+		p.log(fmt.Sprintf("  Quality Gate: re-request failed, keeping previous translations: %v", err))
+		return nil
+	}
+	guard.restore(response)
+
+	scope := p.cacheScope()
+	replaced := 0
+	for _, resp := range response {
+		if _, ok := offending[resp.ID]; !ok || resp.ID >= len(lines) || strings.TrimSpace(resp.Text) == "" {
+			continue
+		}
+		lines[resp.ID].Text = resp.Text
+		replaced++
+		if p.Cache != nil && cacheable(sources[resp.ID]) {
+			p.Cache.SaveScopedTranslation(sources[resp.ID].Text, resp.Text, scope)
+		}
+	}
+	p.log(fmt.Sprintf("  Quality Gate: replaced %d/%d re-requested lines", replaced, len(indexes)))
+	return nil
+}
+
+// reviewQuality lints the finished file and, when issues remain, logs them and
+// asks QualityCallback what to do before muxing
+func (p *Pipeline) reviewQuality(ctx context.Context, sources, lines []parser.SubtitleLine) ([]parser.SubtitleLine, error) {
+	result := p.lintTranslation(sources, lines)
+	p.Quality = &QualityReport{Source: sources, Lines: lines, Issues: result.Issues, Scope: p.cacheScope()}
+	if len(result.Issues) == 0 {
+		p.log("Quality Gate: all lines passed")
+		return lines, nil
+	}
+
+	p.logIssues(result.Issues)
+	if result.PassedAll || p.QualityCallback == nil {
+		return lines, nil
+	}
+
+	decision := p.QualityCallback(*p.Quality)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	switch decision.Action {
+	case QualityAutoFix:
+		p.log("Quality Gate: repairing the remaining issues...")
+		repaired, issues, err := p.qualityGate(ctx, sources, lines, nil, 1)
+		if err != nil {
+			return nil, err
+		}
+		p.Quality = &QualityReport{Source: sources, Lines: repaired, Issues: issues, Scope: p.cacheScope()}
+		p.log(fmt.Sprintf("Quality Gate: %d issues left after repair", len(issues)))
+		return repaired, nil
+
+	case QualityManualReview:
+		if len(decision.Lines) != len(lines) {
+			p.log("Quality Gate: review closed without changes")
+			return lines, nil
+		}
+		edited := 0
+		for i := range lines {
+			if decision.Lines[i].Text != lines[i].Text {
+				edited++
+			}
+		}
+		p.log(fmt.Sprintf("Quality Gate: %d lines edited in review", edited))
+		reviewed := p.lintTranslation(sources, decision.Lines)
+		p.Quality = &QualityReport{Source: sources, Lines: decision.Lines, Issues: reviewed.Issues, Scope: p.cacheScope()}
+		return decision.Lines, nil
+	}
+
+	p.log("Quality Gate: remaining issues ignored")
+	return lines, nil
+}
+
+// recordFix remembers a fix applied to the translation of source
+func (p *Pipeline) recordFix(source string, issue linter.Issue, before string) {
+	if p.fixes == nil {
+		p.fixes = make(map[string][]appliedFix)
+	}
+	for _, f := range p.fixes[source] {
+		if f.issue.RuleID == issue.RuleID && f.before == before {
+			return
+		}
+	}
+	p.fixes[source] = append(p.fixes[source], appliedFix{issue: issue, before: before})
+}
+
+// lintReport builds the report of a translated file: the issues left and the fixes applied
+func (p *Pipeline) lintReport(sources, lines []parser.SubtitleLine) *lintreport.Report {
+	issues := p.lintTranslation(sources, lines).Issues
+	report := lintreport.New(filepath.Base(p.Config.InputPath), p.Config.SourceLang, p.Config.TargetLang, sources, lines, issues)
+	for i, source := range sources {
+		for _, f := range p.fixes[source.Text] {
+			report.AddFix(i+1, f.issue, f.before)
+		}
+	}
+	return report
+}
+
+// writeLintReports writes the lint report of the file in every configured
+// format next to the output file. Failures are logged and do not stop the job.
+func (p *Pipeline) writeLintReports(sources, lines []parser.SubtitleLine) {
+	if len(p.Config.LintReports) == 0 {
+		return
+	}
+
+	report := p.lintReport(sources, lines)
+	for _, format := range p.Config.LintReports {
+		path := lintreport.Path(p.Config.OutputPath, format)
+		if err := lintreport.Export(report, path, format); err != nil {
+			p.log(fmt.Sprintf("Warning: Failed to write lint report: %v", err))
+			continue
+		}
+		p.log(fmt.Sprintf("Lint report: %s", path))
+	}
+}
+
+// logIssues records the issues left in a file, most severe first
+func (p *Pipeline) logIssues(issues []linter.Issue) {
+	lines := make(map[int]bool)
+	for _, issue := range issues {
+		lines[issue.LineID] = true
+	}
+	p.log(fmt.Sprintf("Quality Gate: %d issues on %d lines need review", len(issues), len(lines)))
+
+	sorted := append([]linter.Issue(nil), issues...)
+	rank := map[linter.Severity]int{linter.SeverityHigh: 0, linter.SeverityMedium: 1, linter.SeverityLow: 2}
+	sort.SliceStable(sorted, func(i, j int) bool { return rank[sorted[i].Severity] < rank[sorted[j].Severity] })
+	for i, issue := range sorted {
+		if i == reviewLogLimit {
+			p.log(fmt.Sprintf("  ... and %d more", len(sorted)-reviewLogLimit))
+			break
+		}
+		p.log(fmt.Sprintf("  Line %d [%s] %s: %s", issue.LineID, issue.Severity, issue.RuleID, issue.Suggestion))
+	}
+}
+
+// ruleCounts formats issue counts per rule ID, sorted by ID
+func ruleCounts(counts map[string]int) string {
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("%s %d", id, counts[id])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// issueSummary counts the offending issues per rule ID
+func issueSummary(offending map[int][]linter.Issue) string {
+	counts := make(map[string]int)
+	for _, issues := range offending {
+		for _, issue := range issues {
+			counts[issue.RuleID]++
+		}
+	}
+	return ruleCounts(counts)
+}
@@ -0,0 +1,194 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/lintreport"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// replyProvider answers every source text with a fixed translation
+type replyProvider struct {
+	MockProvider
+	Replies map[string]string
+}
+
+func (r *replyProvider) SendBatch(ctx context.Context, payload []ai.Line, systemPrompt string) ([]ai.Line, ai.Usage, error) {
+	r.CallCount++
+	r.LastPayload = payload
+	r.LastPrompt = systemPrompt
+
+	result := make([]ai.Line, len(payload))
+	for i, line := range payload {
+		result[i] = ai.Line{ID: line.ID, Text: r.Replies[line.Text]}
+	}
+	return result, r.Usage, nil
+}
+
+// qualityPipeline builds a pipeline whose log is collected in logs
+func qualityPipeline(t *testing.T, provider ai.LLMProvider, logs *[]string) *Pipeline {
+	p := New(provider, openTestCache(t), &PipelineConfig{SourceLang: "en", TargetLang: "pt-br", Model: "gpt-4o"})
+	p.LogCallback = func(msg string) { *logs = append(*logs, msg) }
+	return p
+}
+
+// TestQualityGate tests auto-fixes, re-requesting only the failing lines and the job log
+func TestQualityGate(t *testing.T) {
+	provider := &replyProvider{Replies: map[string]string{"Where are you going now?": "Aonde você vai agora?"}}
+	var logs []string
+	p := qualityPipeline(t, provider, &logs)
+
+	sources := linter.TextLines([]string{"Where are you going now?", "Let's go.", "Thank you."})
+	lines := linter.TextLines([]string{"Where are you going now?", "**Vamos** embora.", "Obrigado."})
+
+	fixed, issues, err := p.qualityGate(context.Background(), sources, lines, nil, qualityRepairAttempts)
+	if err != nil {
+		t.Fatalf("qualityGate failed: %v", err)
+	}
+	if fixed[0].Text != "Aonde você vai agora?" || fixed[1].Text != "Vamos embora." || fixed[2].Text != "Obrigado." {
+		t.Errorf("unexpected lines: %+v", fixed)
+	}
+	if len(issues) != 0 {
+		t.Errorf("no issues should be left, got %+v", issues)
+	}
+	if lines[1].Text != "**Vamos** embora." {
+		t.Error("qualityGate should not modify its input")
+	}
+
+	if provider.CallCount != 1 || len(provider.LastPayload) != 1 || provider.LastPayload[0].ID != 0 {
+		t.Errorf("only the failing line should be re-requested, got %+v", provider.LastPayload)
+	}
+	if !strings.Contains(provider.LastPrompt, "QUALITY FIX") || !strings.Contains(provider.LastPrompt, "Line was left untranslated") {
+		t.Error("the prompt should describe the issues")
+	}
+
+	joined := strings.Join(logs, "\n")
+	for _, want := range []string{"auto-fixed 1 lines (model-artifacts 1)", "re-requesting 1 lines", "replaced 1/1"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("log should contain %q, got:\n%s", want, joined)
+		}
+	}
+
+	entry, found := p.Cache.GetScopedMatch("Let's go.", p.cacheScope(), db.PolicyStrict)
+	if !found || entry.Provenance != db.ProvenanceLintFixed || entry.TranslatedText != "Vamos embora." {
+		t.Errorf("lint-fixed line should be cached as %s, got %+v", db.ProvenanceLintFixed, entry)
+	}
+}
+
+// TestQualityGateKeepsUnrepairedLines tests that lines still failing after the attempts are returned with their issues
+func TestQualityGateKeepsUnrepairedLines(t *testing.T) {
+	provider := &replyProvider{Replies: map[string]string{"Where are you going now?": "Where are you going now?"}}
+	var logs []string
+	p := qualityPipeline(t, provider, &logs)
+
+	sources := linter.TextLines([]string{"Where are you going now?"})
+	fixed, issues, err := p.qualityGate(context.Background(), sources, sources, nil, qualityRepairAttempts)
+	if err != nil {
+		t.Fatalf("qualityGate failed: %v", err)
+	}
+	if provider.CallCount != qualityRepairAttempts {
+		t.Errorf("expected %d re-requests, got %d", qualityRepairAttempts, provider.CallCount)
+	}
+	if fixed[0].Text != sources[0].Text || len(issues) == 0 {
+		t.Errorf("the failing line should be kept with its issues, got %+v %+v", fixed, issues)
+	}
+}
+
+// TestReviewQuality tests the decisions offered before muxing
+func TestReviewQuality(t *testing.T) {
+	sources := linter.TextLines([]string{"Where are you going now?", "Thank you."})
+	lines := linter.TextLines([]string{"Where are you going now?", "Obrigado."})
+
+	var logs []string
+	p := qualityPipeline(t, &MockProvider{}, &logs)
+	got, err := p.reviewQuality(context.Background(), sources, lines)
+	if err != nil || got[0].Text != lines[0].Text {
+		t.Fatalf("without a callback the lines should be kept, got %+v %v", got, err)
+	}
+	if p.Quality == nil || len(p.Quality.Issues) == 0 || !strings.Contains(strings.Join(logs, "\n"), "Line 1 [HIGH] untranslated") {
+		t.Errorf("remaining issues should be reported and logged, got %+v\n%v", p.Quality, logs)
+	}
+
+	var report QualityReport
+	p.QualityCallback = func(r QualityReport) QualityDecision {
+		report = r
+		reviewed := append([]parser.SubtitleLine(nil), r.Lines...)
+		reviewed[0].Text = "Aonde você vai agora?"
+		return QualityDecision{Action: QualityManualReview, Lines: reviewed}
+	}
+	got, _ = p.reviewQuality(context.Background(), sources, lines)
+	if len(report.Issues) == 0 || got[0].Text != "Aonde você vai agora?" || len(p.Quality.Issues) != 0 {
+		t.Errorf("reviewed lines should be used, got %+v", got)
+	}
+
+	p.QualityCallback = func(QualityReport) QualityDecision { return QualityDecision{Action: QualityIgnore} }
+	logs = nil
+	if got, _ = p.reviewQuality(context.Background(), sources, lines); got[0].Text != lines[0].Text {
+		t.Errorf("ignored issues should keep the lines, got %+v", got)
+	}
+	if !strings.Contains(strings.Join(logs, "\n"), "remaining issues ignored") {
+		t.Errorf("ignoring should be logged, got %v", logs)
+	}
+
+	provider := &replyProvider{Replies: map[string]string{"Where are you going now?": "Aonde você vai agora?"}}
+	p = qualityPipeline(t, provider, &logs)
+	p.QualityCallback = func(QualityReport) QualityDecision { return QualityDecision{Action: QualityAutoFix} }
+	if got, _ = p.reviewQuality(context.Background(), sources, lines); got[0].Text != "Aonde você vai agora?" || provider.CallCount != 1 {
+		t.Errorf("auto-fix should re-request the failing line, got %+v", got)
+	}
+}
+
+// TestWriteLintReports tests that reports list the fixes applied and the issues left
+func TestWriteLintReports(t *testing.T) {
+	var logs []string
+	p := qualityPipeline(t, &replyProvider{Replies: map[string]string{}}, &logs)
+	dir := t.TempDir()
+	p.Config.InputPath = filepath.Join(dir, "episode.mkv")
+	p.Config.OutputPath = p.Config.InputPath
+	p.Config.LintReports = []lintreport.Format{lintreport.FormatJSON, lintreport.FormatCSV}
+
+	sources := linter.TextLines([]string{"Where are you going now?", "Let's go."})
+	lines, _, err := p.qualityGate(context.Background(), sources, linter.TextLines([]string{"Where are you going now?", "**Vamos** embora."}), nil, 0)
+	if err != nil {
+		t.Fatalf("qualityGate failed: %v", err)
+	}
+	p.writeLintReports(sources, lines)
+
+	data, err := os.ReadFile(filepath.Join(dir, "episode.lint.json"))
+	if err != nil {
+		t.Fatalf("JSON report missing: %v", err)
+	}
+	var report struct {
+		File    string             `json:"file"`
+		Entries []lintreport.Entry `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid report: %v", err)
+	}
+	if report.File != "episode.mkv" || len(report.Entries) < 2 {
+		t.Fatalf("unexpected report: %s", data)
+	}
+	var fixed, open bool
+	for _, e := range report.Entries {
+		fixed = fixed || (e.Line == 2 && e.Fixed && e.Before == "**Vamos** embora." && e.Target == "Vamos embora.")
+		open = open || (e.Line == 1 && e.Rule == linter.RuleUntranslated && !e.Fixed)
+	}
+	if !fixed || !open {
+		t.Errorf("report should list the fix and the open issue: %s", data)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "episode.lint.csv")); err != nil {
+		t.Errorf("CSV report missing: %v", err)
+	}
+	if !strings.Contains(strings.Join(logs, "\n"), "Lint report: ") {
+		t.Errorf("reports should be logged, got %v", logs)
+	}
+}
@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/core/characters"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// speakerInstruction explains the speaker key of the payload
+const speakerInstruction = "\n\nLines may name their speaker in \"s\" (context only, do not return it): use it for gender agreement, pronouns and formality."
+
+// loadCharacters reads the character profiles of the series, if any
+func (p *Pipeline) loadCharacters() {
+	p.characters = &characters.Store{}
+	if p.Config.CharactersPath == "" {
+		return
+	}
+
+	store, err := characters.Load(p.Config.CharactersPath)
+	if err != nil {
+		p.log(fmt.Sprintf("Warning: ignoring character profiles %s: %v", p.Config.CharactersPath, err))
+		return
+	}
+	p.characters = store
+	if len(store.Profiles) > 0 {
+		p.log(fmt.Sprintf("Loaded %d character profiles", len(store.Profiles)))
+	}
+}
+
+// assignSpeakers sets the speaker of each line from its ASS Name field or,
+// without one, from a NAME: label. Must run before HI removal strips the
+// labels. Mixed-case labels ("Note:") only count when they name a profiled
+// character, and aliases are replaced by the profile name.
+func (p *Pipeline) assignSpeakers(lines []parser.SubtitleLine) {
+	identified := 0
+	for i := range lines {
+		speaker := lines[i].Name
+		if speaker == "" {
+			label := parser.SpeakerLabel(lines[i].Text)
+			if len([]rune(label)) > 1 && (label == strings.ToUpper(label) || p.findCharacter(label) != nil) {
+				speaker = label
+			}
+		}
+		if profile := p.findCharacter(speaker); profile != nil {
+			speaker = profile.Name
+		}
+
+		lines[i].Speaker = speaker
+		if speaker != "" {
+			identified++
+		}
+	}
+
+	if identified > 0 {
+		p.log(fmt.Sprintf("Identified the speaker of %d/%d lines", identified, len(lines)))
+	}
+}
+
+// findCharacter returns the profile of a character name or alias, or nil
+func (p *Pipeline) findCharacter(name string) *characters.Profile {
+	if p.characters == nil || name == "" {
+		return nil
+	}
+	return p.characters.Find(name)
+}
+
+// characterPrompt lists the profiles of the characters speaking or
+// mentioned in the lines and their context, and explains the speaker key
+// when lines carry one
+func (p *Pipeline) characterPrompt(lines, contextLines []parser.SubtitleLine) string {
+	var speakers, texts []string
+	for _, line := range append(append([]parser.SubtitleLine{}, contextLines...), lines...) {
+		if line.Speaker != "" {
+			speakers = append(speakers, line.Speaker)
+		}
+		texts = append(texts, line.Text)
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		if line.Speaker != "" {
+			b.WriteString(speakerInstruction)
+			break
+		}
+	}
+
+	if p.characters != nil {
+		if profiles := p.characters.Relevant(speakers, texts); len(profiles) > 0 {
+			b.WriteString("\n\nCharacters (keep their voice, gender agreement and formality consistent):\n")
+			for _, profile := range profiles {
+				b.WriteString("- " + profile.Describe() + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// contextLine renders a sliding window line, with its speaker when known
+func contextLine(line parser.SubtitleLine) string {
+	if line.Speaker == "" {
+		return line.Text
+	}
+	return fmt.Sprintf("[%s] %s", line.Speaker, line.Text)
+}
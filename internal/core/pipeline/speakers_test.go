@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/characters"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+)
+
+// testProfiles are the character profiles of the speaker tests
+const testProfiles = `[{"name":"Hinata","aliases":["Hyuga"],"gender":"female","register":"shy and polite","relationships":{"Naruto":"admirer"}},
+{"name":"Naruto","gender":"male","register":"brash and informal"},
+{"name":"Sakura","gender":"female"}]`
+
+// speakerPipeline builds a pipeline with the test character profiles loaded
+func speakerPipeline(t *testing.T, provider *replyProvider) *Pipeline {
+	path := filepath.Join(t.TempDir(), characters.FileName)
+	if err := os.WriteFile(path, []byte(testProfiles), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := New(provider, openTestCache(t), &PipelineConfig{TargetLang: "pt-br", CharactersPath: path})
+	p.loadCharacters()
+	return p
+}
+
+// TestAssignSpeakers tests speakers from the ASS Name field and NAME: labels
+func TestAssignSpeakers(t *testing.T) {
+	p := speakerPipeline(t, nil)
+	lines := []parser.SubtitleLine{
+		{Name: "hyuga", Text: "Good morning."},
+		{Text: "NARUTO: Believe it!"},
+		{Text: "Sakura: Idiot."},
+		{Text: "Note: the shop is closed."},
+		{Name: "Kakashi", Text: "SASUKE: Not me."},
+		{Text: "No label."},
+	}
+	p.assignSpeakers(lines)
+
+	want := []string{"Hinata", "Naruto", "Sakura", "", "Kakashi", ""}
+	for i, line := range lines {
+		if line.Speaker != want[i] {
+			t.Errorf("line %d: speaker = %q, want %q", i, line.Speaker, want[i])
+		}
+	}
+
+	// Speakers survive HI removal, which strips the labels
+	if text := parser.RemoveHearingImpairedTags(lines[1].Text); text != "Believe it!" || lines[1].Speaker != "Naruto" {
+		t.Errorf("unexpected line after HI removal: %q (%q)", text, lines[1].Speaker)
+	}
+}
+
+// TestSpeakerContext tests that speakers reach the payload and context and
+// that only the relevant profiles are put in the prompt
+func TestSpeakerContext(t *testing.T) {
+	provider := &replyProvider{Replies: map[string]string{"Thank you, Naruto.": "Obrigada, Naruto."}}
+	p := speakerPipeline(t, provider)
+
+	batch := TranslationBatch{
+		Lines:        []parser.SubtitleLine{{Index: 2, Speaker: "Hinata", Text: "Thank you, Naruto."}},
+		ContextLines: []parser.SubtitleLine{{Index: 1, Speaker: "Naruto", Text: "Here you go."}, {Index: 0, Text: "..."}},
+	}
+	lines, err := p.translateBatchWithRetry(context.Background(), batch, 0)
+	if err != nil {
+		t.Fatalf("translateBatchWithRetry failed: %v", err)
+	}
+	if lines[0].Text != "Obrigada, Naruto." || lines[0].Speaker != "Hinata" {
+		t.Errorf("translated lines should keep their speaker, got %+v", lines[0])
+	}
+
+	if provider.LastPayload[0].Speaker != "Hinata" {
+		t.Errorf("payload should carry the speaker, got %+v", provider.LastPayload)
+	}
+	for _, want := range []string{
+		speakerInstruction,
+		"- Hinata (also Hyuga): female; speaks shy and polite; admirer of Naruto\n",
+		"- Naruto: male; speaks brash and informal\n",
+		"1. [Naruto] Here you go.\n2. ...\n",
+	} {
+		if !strings.Contains(provider.LastPrompt, want) {
+			t.Errorf("prompt should contain %q:\n%s", want, provider.LastPrompt)
+		}
+	}
+	if strings.Contains(provider.LastPrompt, "Sakura") {
+		t.Errorf("prompt should only list the relevant characters:\n%s", provider.LastPrompt)
+	}
+
+	if prompt := p.buildSystemPrompt([]parser.SubtitleLine{{Text: "Hello."}}, nil); strings.Contains(prompt, speakerInstruction) || strings.Contains(prompt, "Characters") {
+		t.Errorf("lines without speakers or characters should not add to the prompt:\n%s", prompt)
+	}
+}
@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
+)
+
+// termSampleLines caps the lines sent to the LLM glossary pre-pass
+const termSampleLines = 150
+
+// termExtractionPrompt asks for the glossary of a script; %s is the target language
+const termExtractionPrompt = `You are preparing the glossary for translating a subtitle script into %s. The JSON payload is a sample of the script's lines.
+
+List the terms a translator must render consistently: character names, places, titles and honorifics, techniques and items, invented or fantasy vocabulary and recurring expressions, in any script and in any case. Skip ordinary words.
+
+For each term propose the translation to use. Keep names as written unless the target language has an established form.
+
+Output Format: Return ONLY a valid JSON array of {"i": n, "t": "term | type | translation | rationale"} objects, one per term, numbered from 0, where type is one of Name, Place, Attack, Title or Term and rationale is a short reason.`
+
+var sampleTagPattern = regexp.MustCompile(`\{[^}]*\}`)
+
+// condensedSample picks up to max distinct lines spread over the script,
+// without formatting tags and line breaks
+func condensedSample(lines []parser.SubtitleLine, max int) []parser.SubtitleLine {
+	seen := make(map[string]bool)
+	var unique []parser.SubtitleLine
+	for _, line := range lines {
+		text := sampleTagPattern.ReplaceAllString(line.Text, "")
+		text = strings.NewReplacer(`\N`, " ", `\n`, " ", "\n", " ").Replace(text)
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		unique = append(unique, parser.SubtitleLine{Index: line.Index, Text: text})
+	}
+
+	if len(unique) <= max {
+		return unique
+	}
+	sample := make([]parser.SubtitleLine, max)
+	for i := range sample {
+		sample[i] = unique[i*len(unique)/max]
+	}
+	return sample
+}
+
+// parseProposals reads "term | type | translation | rationale" items
+func parseProposals(response []ai.Line) []termbase.Proposal {
+	var proposals []termbase.Proposal
+	for _, line := range response {
+		fields := strings.SplitN(line.Text, "|", 4)
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if fields[0] == "" {
+			continue
+		}
+		for len(fields) < 4 {
+			fields = append(fields, "")
+		}
+		proposals = append(proposals, termbase.Proposal{
+			Term:        fields[0],
+			Type:        fields[1],
+			Translation: fields[2],
+			Rationale:   fields[3],
+		})
+	}
+	return proposals
+}
+
+// proposeTerms sends a condensed sample of the script to the provider and
+// returns the glossary terms it suggests
+func (p *Pipeline) proposeTerms(ctx context.Context, lines []parser.SubtitleLine) ([]termbase.Proposal, error) {
+	sample := condensedSample(lines, termSampleLines)
+	if len(sample) == 0 {
+		return nil, nil
+	}
+	if err := p.checkBudget(sample); err != nil {
+		return nil, err
+	}
+
+	payload := make([]ai.Line, len(sample))
+	for i, line := range sample {
+		payload[i] = ai.Line{ID: i, Text: line.Text}
+	}
+
+	p.log(fmt.Sprintf("Glossary pre-pass: asking the model for terms in %d sample lines...", len(sample)))
+	response, usage, err := p.Provider.SendBatch(ctx, payload, fmt.Sprintf(termExtractionPrompt, p.Config.TargetLang))
+	p.recordUsage(usage)
+	if err != nil {
+		return nil, err
+	}
+	return parseProposals(response), nil
+}
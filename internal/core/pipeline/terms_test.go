@@ -0,0 +1,68 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+)
+
+// termsProvider answers every request with a fixed term list
+type termsProvider struct {
+	MockProvider
+	Terms []string
+}
+
+func (r *termsProvider) SendBatch(ctx context.Context, payload []ai.Line, systemPrompt string) ([]ai.Line, ai.Usage, error) {
+	r.CallCount++
+	r.LastPayload = payload
+	r.LastPrompt = systemPrompt
+
+	result := make([]ai.Line, len(r.Terms))
+	for i, term := range r.Terms {
+		result[i] = ai.Line{ID: i, Text: term}
+	}
+	return result, r.Usage, nil
+}
+
+// TestCondensedSample tests deduplication, cleanup and spreading of the sample
+func TestCondensedSample(t *testing.T) {
+	lines := linter.TextLines([]string{`{\i1}Hello\Nthere{\i0}`, "Hello there", "", "Bye."})
+	sample := condensedSample(lines, 10)
+	if len(sample) != 2 || sample[0].Text != "Hello there" || sample[1].Text != "Bye." {
+		t.Errorf("unexpected sample: %+v", sample)
+	}
+
+	var many []string
+	for i := range 100 {
+		many = append(many, strings.Repeat("x", i+1))
+	}
+	sample = condensedSample(linter.TextLines(many), 10)
+	if len(sample) != 10 || sample[0].Text != "x" || len(sample[9].Text) != 91 {
+		t.Errorf("sample should be spread over the script, got %d lines ending with %q", len(sample), sample[len(sample)-1].Text)
+	}
+}
+
+// TestParseProposals tests reading the term list
+func TestParseProposals(t *testing.T) {
+	proposals := parseProposals([]ai.Line{
+		{ID: 0, Text: "Konoha | Place | Vila da Folha | village name, used every episode"},
+		{ID: 1, Text: "nakama|Term|companheiro"},
+		{ID: 2, Text: " | Name | x | y"},
+		{ID: 3, Text: "Chakra | Term | chakra | energy | used for jutsu"},
+	})
+	if len(proposals) != 3 {
+		t.Fatalf("got %d proposals, want 3: %+v", len(proposals), proposals)
+	}
+	if p := proposals[0]; p.Term != "Konoha" || p.Type != "Place" || p.Translation != "Vila da Folha" || p.Rationale != "village name, used every episode" {
+		t.Errorf("unexpected proposal: %+v", p)
+	}
+	if p := proposals[1]; p.Translation != "companheiro" || p.Rationale != "" {
+		t.Errorf("missing fields should be empty: %+v", p)
+	}
+	if p := proposals[2]; p.Rationale != "energy | used for jutsu" {
+		t.Errorf("the rationale should keep extra separators: %+v", p)
+	}
+}
@@ -0,0 +1,134 @@
+package termbase
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+)
+
+// termColumns is the header written to CSV/TSV exports
+var termColumns = []string{"original", "translation", "type", "status", "enforce", "forbidden", "notes"}
+
+// columnAliases maps header names used by other tools to the column they fill
+var columnAliases = map[string]string{
+	"source": "original", "term": "original", "front": "original",
+	"target": "translation", "back": "translation",
+	"rationale": "notes", "note": "notes", "comment": "notes",
+}
+
+// WriteDelimited writes terms as CSV (comma ',') or TSV (comma '\t') with a
+// header row. Forbidden renderings share one cell, separated by "; ".
+func WriteDelimited(w io.Writer, terms []Term, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	if err := cw.Write(termColumns); err != nil {
+		return err
+	}
+	for _, t := range terms {
+		if err := cw.Write([]string{
+			t.Original, t.Translation, t.Type, string(t.Status), string(t.Enforce),
+			strings.Join(t.Forbidden, "; "), t.Rationale,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadDelimited reads terms from CSV or TSV. With a header row, columns are
+// matched by name (case-insensitive, any order) and "original" (or "source",
+// "term") is required. Without one, as in Anki exports, the first column is
+// the term and the second its translation. Lines starting with # are skipped.
+func ReadDelimited(r io.Reader, comma rune) ([]Term, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	records, err := cr.ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+
+	col := exchange.Columns(records[0], columnAliases)
+	if _, ok := col["original"]; ok {
+		records = records[1:]
+	} else {
+		// Headerless: the first record is a term
+		col = map[string]int{"original": 0, "translation": 1}
+	}
+
+	var terms []Term
+	for n, record := range records {
+		field := func(name string) string {
+			if i, ok := col[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		term := Term{
+			Original:    field("original"),
+			Translation: field("translation"),
+			Type:        field("type"),
+			Forbidden:   splitList(field("forbidden")),
+			Rationale:   field("notes"),
+		}
+		if term.Original == "" {
+			continue
+		}
+		if term.Status, err = parseStatus(field("status")); err != nil {
+			return nil, fmt.Errorf("record %d: %w", n+1, err)
+		}
+		if term.Enforce, err = parseEnforcement(field("enforce")); err != nil {
+			return nil, fmt.Errorf("record %d: %w", n+1, err)
+		}
+		terms = append(terms, term)
+	}
+
+	return terms, nil
+}
+
+// splitList splits a cell of values separated by ";" or "|"
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == '|' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseStatus converts an imported status ("" = approved)
+func parseStatus(s string) (Status, error) {
+	switch Status(strings.ToLower(s)) {
+	case StatusApproved, "":
+		return StatusApproved, nil
+	case StatusCandidate:
+		return StatusCandidate, nil
+	case StatusRejected:
+		return StatusRejected, nil
+	}
+	return "", fmt.Errorf("unknown status %q (use approved, candidate or rejected)", s)
+}
+
+// parseEnforcement converts an imported enforcement ("" = prompt only)
+func parseEnforcement(s string) (Enforcement, error) {
+	switch Enforcement(strings.ToLower(s)) {
+	case EnforcePrompt:
+		return EnforcePrompt, nil
+	case EnforceKeep:
+		return EnforceKeep, nil
+	case EnforceForce:
+		return EnforceForce, nil
+	}
+	return "", fmt.Errorf("unknown enforcement %q (use keep or force)", s)
+}
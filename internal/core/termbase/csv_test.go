@@ -0,0 +1,57 @@
+package termbase
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// exchangeTerms covers every field carried by the exchange formats
+var exchangeTerms = []Term{
+	{Original: "Konoha", Translation: "Vila da Folha", Type: "Place", Status: StatusApproved, Enforce: EnforceForce,
+		Forbidden: []string{"Aldeia da Folha", "Vila, \"Folha\""}, Rationale: "village name"},
+	{Original: "Naruto", Type: "Name", Status: StatusCandidate},
+}
+
+// TestDelimitedRoundTrip tests CSV and TSV export/import
+func TestDelimitedRoundTrip(t *testing.T) {
+	for _, comma := range []rune{',', '\t'} {
+		var buf bytes.Buffer
+		if err := WriteDelimited(&buf, exchangeTerms, comma); err != nil {
+			t.Fatalf("WriteDelimited(%q) failed: %v", comma, err)
+		}
+
+		got, err := ReadDelimited(&buf, comma)
+		if err != nil {
+			t.Fatalf("ReadDelimited(%q) failed: %v", comma, err)
+		}
+		if !reflect.DeepEqual(got, exchangeTerms) {
+			t.Errorf("%q:\n got  %+v\n want %+v", comma, got, exchangeTerms)
+		}
+	}
+}
+
+// TestReadDelimitedColumns tests header aliases, headerless Anki files and validation
+func TestReadDelimitedColumns(t *testing.T) {
+	terms, err := ReadDelimited(strings.NewReader("\uFEFFTarget,Term,Notes\nVila da Folha,Konoha,village\n"), ',')
+	if err != nil {
+		t.Fatalf("ReadDelimited failed: %v", err)
+	}
+	if len(terms) != 1 || terms[0].Original != "Konoha" || terms[0].Translation != "Vila da Folha" || terms[0].Status != StatusApproved {
+		t.Errorf("unexpected terms %+v", terms)
+	}
+
+	anki := "#separator:tab\n#html:false\nnakama\tcompanheiro\tanime\nKonoha\tVila da Folha\n"
+	terms, err = ReadDelimited(strings.NewReader(anki), '\t')
+	if err != nil {
+		t.Fatalf("ReadDelimited failed: %v", err)
+	}
+	if len(terms) != 2 || terms[0].Original != "nakama" || terms[0].Translation != "companheiro" || terms[0].Type != "" {
+		t.Errorf("headerless rows should be term and translation, got %+v", terms)
+	}
+
+	if _, err := ReadDelimited(strings.NewReader("original,status\nKonoha,maybe\n"), ','); err == nil {
+		t.Error("expected error for an unknown status")
+	}
+}
@@ -0,0 +1,139 @@
+package termbase
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+)
+
+// Formats are the glossary file formats
+var Formats = []exchange.Format{exchange.FormatCSV, exchange.FormatTSV, exchange.FormatTBX}
+
+// Languages are the language codes written to and picked from TBX files
+type Languages struct {
+	Source string // "" = undetermined on export, the first language on import
+	Target string // "" = undetermined on export, the other language on import
+}
+
+// sameEntry reports whether an incoming term matches the existing one
+func sameEntry(existing, incoming Term) bool {
+	return existing.Target() == incoming.Target() &&
+		existing.Status == incoming.Status &&
+		existing.Enforce == incoming.Enforce &&
+		(incoming.Type == "" || existing.Type == incoming.Type) &&
+		(len(incoming.Forbidden) == 0 || slices.Equal(existing.Forbidden, incoming.Forbidden))
+}
+
+// MergeTerms merges imported terms into the glossary, resolving terms that
+// already exist (ignoring case) with policy. Occurrence counts of existing
+// terms are kept; imported terms without a status are approved.
+func (s *Store) MergeTerms(terms []Term, policy exchange.ConflictPolicy) exchange.ImportResult {
+	var result exchange.ImportResult
+	for _, incoming := range terms {
+		incoming.Original = strings.TrimSpace(incoming.Original)
+		if incoming.Original == "" {
+			continue
+		}
+		if incoming.Status == "" {
+			incoming.Status = StatusApproved
+		}
+
+		existing := s.Find(incoming.Original)
+		if existing == nil {
+			s.Terms = append(s.Terms, incoming)
+			result.Added++
+			continue
+		}
+		if sameEntry(*existing, incoming) {
+			result.Skipped++
+			continue
+		}
+		if existing.Translation != "" && incoming.Translation != "" && existing.Target() != incoming.Target() {
+			result.Conflicts = append(result.Conflicts, existing.Original)
+		}
+		if !policy.Replaces(existing.Status == StatusApproved, incoming.Status == StatusApproved) {
+			result.Skipped++
+			continue
+		}
+
+		existing.Translation = incoming.Translation
+		existing.Status = incoming.Status
+		existing.Enforce = incoming.Enforce
+		existing.Proposed = false
+		if incoming.Type != "" {
+			existing.Type = incoming.Type
+		}
+		if len(incoming.Forbidden) > 0 {
+			existing.Forbidden = incoming.Forbidden
+		}
+		if incoming.Rationale != "" {
+			existing.Rationale = incoming.Rationale
+		}
+		result.Updated++
+	}
+	return result
+}
+
+// Export writes the glossary to path. An empty format is derived from the
+// file extension. It returns the number of terms written.
+func Export(s *Store, path string, format exchange.Format, langs Languages) (int, error) {
+	format, err := exchange.Resolve(path, format, Formats...)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	switch format {
+	case exchange.FormatTBX:
+		err = WriteTBX(f, s.Terms, langs)
+	case exchange.FormatTSV:
+		err = WriteDelimited(f, s.Terms, '\t')
+	default:
+		err = WriteDelimited(f, s.Terms, ',')
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return len(s.Terms), nil
+}
+
+// Import merges the terms of path into the glossary without saving it.
+// An empty format is derived from the file extension.
+func Import(s *Store, path string, format exchange.Format, langs Languages, policy exchange.ConflictPolicy) (exchange.ImportResult, error) {
+	format, err := exchange.Resolve(path, format, Formats...)
+	if err != nil {
+		return exchange.ImportResult{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return exchange.ImportResult{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var terms []Term
+	switch format {
+	case exchange.FormatTBX:
+		terms, err = ReadTBX(f, langs)
+	case exchange.FormatTSV:
+		terms, err = ReadDelimited(f, '\t')
+	default:
+		terms, err = ReadDelimited(f, ',')
+	}
+	if err != nil {
+		return exchange.ImportResult{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return s.MergeTerms(terms, policy), nil
+}
@@ -0,0 +1,92 @@
+package termbase
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+)
+
+// TestFormats tests format detection with the glossary formats
+func TestFormats(t *testing.T) {
+	for path, want := range map[string]exchange.Format{
+		"g.csv": exchange.FormatCSV, "g.TSV": exchange.FormatTSV, "anki.txt": exchange.FormatTSV, "g.tbx": exchange.FormatTBX,
+	} {
+		if got, err := exchange.FormatForPath(path, Formats...); err != nil || got != want {
+			t.Errorf("FormatForPath(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	for _, path := range []string{"g.json", "memory.tmx"} {
+		if _, err := exchange.FormatForPath(path, Formats...); err == nil {
+			t.Errorf("expected error for %q", path)
+		}
+	}
+}
+
+// TestMergeTerms tests the conflict policies
+func TestMergeTerms(t *testing.T) {
+	incoming := []Term{
+		{Original: "konoha", Translation: "Aldeia da Folha"},
+		{Original: "Naruto", Translation: "Naruto", Status: StatusCandidate},
+		{Original: "Sasuke", Translation: "Sasuke"},
+	}
+
+	tests := []struct {
+		policy                  exchange.ConflictPolicy
+		konoha, naruto          string
+		added, updated, skipped int
+	}{
+		{exchange.ConflictKeep, "Vila da Folha", "Naruto Uzumaki", 1, 0, 2},
+		{exchange.ConflictOverwrite, "Aldeia da Folha", "Naruto", 1, 2, 0},
+		{exchange.ConflictPreferApproved, "Aldeia da Folha", "Naruto Uzumaki", 1, 1, 1},
+	}
+
+	for _, tt := range tests {
+		s := &Store{}
+		s.Set("Konoha", "Vila da Folha", "Place")
+		s.Set("Naruto", "Naruto Uzumaki", "Name")
+		s.Record("ep01.mkv", nil)
+
+		result := s.MergeTerms(incoming, tt.policy)
+		if result.Added != tt.added || result.Updated != tt.updated || result.Skipped != tt.skipped || len(result.Conflicts) != 2 {
+			t.Errorf("%s: result = %+v", tt.policy, result)
+		}
+		if got := s.Find("Konoha"); got.Translation != tt.konoha || got.Original != "Konoha" || got.Type != "Place" {
+			t.Errorf("%s: Konoha = %+v", tt.policy, got)
+		}
+		if got := s.Find("Naruto").Translation; got != tt.naruto {
+			t.Errorf("%s: Naruto = %q, want %q", tt.policy, got, tt.naruto)
+		}
+		if s.Find("Sasuke").Status != StatusApproved {
+			t.Errorf("%s: imported terms should be approved", tt.policy)
+		}
+	}
+}
+
+// TestExportImport tests exchanging a glossary through files
+func TestExportImport(t *testing.T) {
+	dir := t.TempDir()
+	source := &Store{Terms: exchangeTerms}
+
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			path := filepath.Join(dir, "glossary."+string(format))
+			if n, err := Export(source, path, "", Languages{Source: "ja", Target: "pt-br"}); err != nil || n != 2 {
+				t.Fatalf("Export = %d, %v", n, err)
+			}
+
+			target := &Store{Path: filepath.Join(dir, FileName)}
+			target.Set("Konoha", "Konoha", "Place")
+			result, err := Import(target, path, format, Languages{Target: "pt-br"}, exchange.ConflictPreferApproved)
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if result.Added != 1 || result.Updated != 1 {
+				t.Errorf("result = %+v, want 1 added, 1 updated", result)
+			}
+			if got := target.Find("Konoha"); got.Translation != "Vila da Folha" || got.Enforce != EnforceForce || len(got.Forbidden) != 2 {
+				t.Errorf("unexpected term %+v", got)
+			}
+		})
+	}
+}
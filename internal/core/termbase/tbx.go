@@ -0,0 +1,246 @@
+package termbase
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Descrip types carrying BakaSub term details through TBX files.
+// Other tools preserve unknown "x-" types.
+const (
+	descripType    = "x-bakasub-type"
+	descripStatus  = "x-bakasub-status"
+	descripEnforce = "x-bakasub-enforce"
+)
+
+// TBX-Basic administrative statuses of target terms
+const (
+	adminStatus     = "administrativeStatus"
+	adminPreferred  = "preferredTerm-admn-sts"
+	adminDeprecated = "deprecatedTerm-admn-sts"
+	adminSuperseded = "supersededTerm-admn-sts"
+)
+
+// undeterminedLang is written for languages that are not known
+const undeterminedLang = "und"
+
+// tbxDocument is a TBX-Basic (TBX 2008, "martif") document. Reading also
+// accepts TBX 2019 (conceptEntry, langSec, termSec).
+type tbxDocument struct {
+	XMLName  xml.Name   `xml:"martif"`
+	Type     string     `xml:"type,attr,omitempty"`
+	Lang     string     `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	Header   *tbxHeader `xml:"martifHeader,omitempty"`
+	Entries  []tbxEntry `xml:"text>body>termEntry"`
+	Concepts []tbxEntry `xml:"text>body>conceptEntry"`
+}
+
+type tbxHeader struct {
+	Source string `xml:"fileDesc>sourceDesc>p"`
+}
+
+type tbxEntry struct {
+	ID       string       `xml:"id,attr,omitempty"`
+	Descrips []tbxDescrip `xml:"descrip"`
+	Notes    []string     `xml:"note"`
+	LangSets []tbxLangSet `xml:"langSet"`
+	LangSecs []tbxLangSet `xml:"langSec"`
+}
+
+type tbxDescrip struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tbxLangSet struct {
+	Lang     string    `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Tigs     []tbxTerm `xml:"tig"`
+	TermSecs []tbxTerm `xml:"termSec"`
+}
+
+type tbxTerm struct {
+	Term      string       `xml:"term"`
+	TermNotes []tbxDescrip `xml:"termNote"`
+}
+
+// terms returns the terms of a language set in either TBX version
+func (l tbxLangSet) terms() []tbxTerm {
+	return append(append([]tbxTerm{}, l.Tigs...), l.TermSecs...)
+}
+
+// status returns the administrative status of a term
+func (t tbxTerm) status() string {
+	for _, n := range t.TermNotes {
+		if n.Type == adminStatus {
+			return strings.TrimSpace(n.Value)
+		}
+	}
+	return ""
+}
+
+// WriteTBX writes terms as a TBX-Basic document, one entry per term. The
+// translation is the preferred target term and forbidden renderings are
+// deprecated ones.
+func WriteTBX(w io.Writer, terms []Term, langs Languages) error {
+	source, target := langs.Source, langs.Target
+	if source == "" {
+		source = undeterminedLang
+	}
+	if target == "" {
+		target = undeterminedLang
+	}
+
+	doc := tbxDocument{
+		Type:   "TBX-Basic",
+		Lang:   source,
+		Header: &tbxHeader{Source: "BakaSub"},
+	}
+	for i, t := range terms {
+		entry := tbxEntry{
+			ID:       fmt.Sprintf("t%d", i+1),
+			LangSets: []tbxLangSet{{Lang: source, Tigs: []tbxTerm{{Term: t.Original}}}},
+		}
+		for _, d := range []tbxDescrip{
+			{Type: descripType, Value: t.Type},
+			{Type: descripStatus, Value: string(t.Status)},
+			{Type: descripEnforce, Value: string(t.Enforce)},
+		} {
+			if d.Value != "" {
+				entry.Descrips = append(entry.Descrips, d)
+			}
+		}
+		if t.Rationale != "" {
+			entry.Notes = []string{t.Rationale}
+		}
+
+		targetSet := tbxLangSet{Lang: target}
+		if t.Translation != "" {
+			targetSet.Tigs = append(targetSet.Tigs, tbxTerm{
+				Term:      t.Translation,
+				TermNotes: []tbxDescrip{{Type: adminStatus, Value: adminPreferred}},
+			})
+		}
+		for _, f := range t.Forbidden {
+			targetSet.Tigs = append(targetSet.Tigs, tbxTerm{
+				Term:      f,
+				TermNotes: []tbxDescrip{{Type: adminStatus, Value: adminDeprecated}},
+			})
+		}
+		if len(targetSet.Tigs) > 0 {
+			entry.LangSets = append(entry.LangSets, targetSet)
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadTBX reads terms from a TBX document. The source and target languages
+// are picked by langs; unset, the first language is the source and the next
+// one the target. Deprecated and superseded target terms become forbidden
+// renderings.
+func ReadTBX(r io.Reader, langs Languages) ([]Term, error) {
+	var doc tbxDocument
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid TBX: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			// TBX 2019 documents have a <tbx> root with the same body
+			start.Name.Local = "martif"
+			if err := dec.DecodeElement(&doc, &start); err != nil {
+				return nil, fmt.Errorf("invalid TBX: %w", err)
+			}
+			break
+		}
+	}
+
+	var terms []Term
+	for _, entry := range append(doc.Entries, doc.Concepts...) {
+		sets := append(append([]tbxLangSet{}, entry.LangSets...), entry.LangSecs...)
+		src := pickLangSet(sets, langs.Source, langs.Target, -1)
+		if src < 0 || len(sets[src].terms()) == 0 {
+			continue
+		}
+		term := Term{Original: strings.TrimSpace(sets[src].terms()[0].Term)}
+
+		if tgt := pickLangSet(sets, langs.Target, "", src); tgt >= 0 {
+			for _, t := range sets[tgt].terms() {
+				text := strings.TrimSpace(t.Term)
+				switch status := t.status(); {
+				case text == "":
+				case status == adminDeprecated || status == adminSuperseded:
+					term.Forbidden = append(term.Forbidden, text)
+				case term.Translation == "" || status == adminPreferred:
+					term.Translation = text
+				}
+			}
+		}
+
+		var err error
+		for _, d := range entry.Descrips {
+			value := strings.TrimSpace(d.Value)
+			switch d.Type {
+			case descripType:
+				term.Type = value
+			case descripStatus:
+				term.Status, err = parseStatus(value)
+			case descripEnforce:
+				term.Enforce, err = parseEnforcement(value)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("entry %q: %w", term.Original, err)
+			}
+		}
+		if len(entry.Notes) > 0 {
+			term.Rationale = strings.TrimSpace(entry.Notes[0])
+		}
+		if term.Original != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms, nil
+}
+
+// pickLangSet returns the index of the language set in lang or, when lang is
+// unset, of the first one not in other; skip is never picked. Sets of an
+// undetermined language match any lang. It returns -1 when there is none.
+func pickLangSet(sets []tbxLangSet, lang, other string, skip int) int {
+	for i, s := range sets {
+		if i == skip {
+			continue
+		}
+		undetermined := s.Lang == "" || strings.EqualFold(s.Lang, undeterminedLang)
+		if lang != "" && (undetermined || sameLang(s.Lang, lang)) {
+			return i
+		}
+		if lang == "" && (other == "" || !sameLang(s.Lang, other)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sameLang compares language codes, ignoring case and, when one side has
+// no region, the region of the other (pt matches pt-BR)
+func sameLang(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	baseA, regionA, _ := strings.Cut(strings.ReplaceAll(a, "_", "-"), "-")
+	baseB, regionB, _ := strings.Cut(strings.ReplaceAll(b, "_", "-"), "-")
+	return strings.EqualFold(baseA, baseB) && (regionA == "" || regionB == "")
+}
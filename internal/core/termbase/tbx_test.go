@@ -0,0 +1,60 @@
+package termbase
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestTBXRoundTrip tests TBX-Basic export/import
+func TestTBXRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTBX(&buf, exchangeTerms, Languages{Source: "ja", Target: "pt-BR"}); err != nil {
+		t.Fatalf("WriteTBX failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `<martif type="TBX-Basic" xml:lang="ja">`) || !strings.Contains(buf.String(), adminDeprecated) {
+		t.Errorf("unexpected document:\n%s", buf.String())
+	}
+
+	got, err := ReadTBX(bytes.NewReader(buf.Bytes()), Languages{Target: "pt"})
+	if err != nil {
+		t.Fatalf("ReadTBX failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, exchangeTerms) {
+		t.Errorf("\n got  %+v\n want %+v", got, exchangeTerms)
+	}
+
+	if got, _ := ReadTBX(bytes.NewReader(buf.Bytes()), Languages{Source: "pt-br", Target: "ja"}); len(got) != 1 || got[0].Original != "Vila da Folha" {
+		t.Errorf("languages should pick the sides, got %+v", got)
+	}
+}
+
+// TestReadTBX2019 tests TBX 2019 documents from other tools
+func TestReadTBX2019(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<tbx type="TBX-Basic" style="dca" xml:lang="en" xmlns="urn:iso:std:iso:30042:ed-2">
+  <text><body>
+    <conceptEntry id="c1">
+      <langSec xml:lang="en"><termSec><term>Hidden Leaf</term></termSec></langSec>
+      <langSec xml:lang="es">
+        <termSec><term>Aldea de la Hoja</term><termNote type="administrativeStatus">supersededTerm-admn-sts</termNote></termSec>
+        <termSec><term>Hoja Oculta</term><termNote type="administrativeStatus">preferredTerm-admn-sts</termNote></termSec>
+      </langSec>
+    </conceptEntry>
+  </body></text>
+</tbx>`
+
+	terms, err := ReadTBX(strings.NewReader(doc), Languages{})
+	if err != nil {
+		t.Fatalf("ReadTBX failed: %v", err)
+	}
+	if len(terms) != 1 || terms[0].Original != "Hidden Leaf" || terms[0].Translation != "Hoja Oculta" ||
+		len(terms[0].Forbidden) != 1 || terms[0].Forbidden[0] != "Aldea de la Hoja" {
+		t.Errorf("unexpected terms %+v", terms)
+	}
+
+	if _, err := ReadTBX(strings.NewReader("not xml"), Languages{}); err == nil {
+		t.Error("expected error for an invalid document")
+	}
+}
@@ -0,0 +1,311 @@
+// Package termbase keeps the per-series glossary: the terms approved by the
+// user, the named-entity candidates detected across episodes and the terms
+// proposed by the LLM pre-pass. It is stored as glossary.json next to the
+// media files.
+package termbase
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/ner"
+)
+
+// FileName is the name of the series glossary next to the media files
+const FileName = "glossary.json"
+
+// Status tells whether a term is used in translations
+type Status string
+
+const (
+	StatusApproved  Status = "approved"  // Used by every job
+	StatusCandidate Status = "candidate" // Detected by NER, kept as-is until reviewed
+	StatusRejected  Status = "rejected"  // Never used, even when detected again
+)
+
+// Enforcement tells how strictly an approved term is applied
+type Enforcement string
+
+const (
+	EnforcePrompt Enforcement = ""      // Only listed in the prompt
+	EnforceKeep   Enforcement = "keep"  // Do not translate: the original must be kept
+	EnforceForce  Enforcement = "force" // The translation must be used
+)
+
+// Next cycles prompt, keep and force
+func (e Enforcement) Next() Enforcement {
+	switch e {
+	case EnforcePrompt:
+		return EnforceKeep
+	case EnforceKeep:
+		return EnforceForce
+	}
+	return EnforcePrompt
+}
+
+// Term is a glossary entry. Entries written before statuses existed load as
+// approved.
+type Term struct {
+	Original     string         `json:"original"`
+	Translation  string         `json:"translation"`
+	Type         string         `json:"type"`
+	AutoDetected bool           `json:"auto_detected"`
+	Status       Status         `json:"status,omitempty"`
+	Confidence   float64        `json:"confidence,omitempty"`
+	Episodes     map[string]int `json:"episodes,omitempty"` // Occurrences per episode file
+	Proposed     bool           `json:"proposed,omitempty"` // Translation proposed by the LLM pre-pass, not used until approved
+	Rationale    string         `json:"rationale,omitempty"`
+	Enforce      Enforcement    `json:"enforce,omitempty"`
+	Forbidden    []string       `json:"forbidden,omitempty"` // Renderings that must never be used
+	Inflect      bool           `json:"inflect,omitempty"`   // The translation may be inflected (plural, gender) where enforced
+}
+
+// Proposal is a term suggested by the LLM glossary pre-pass
+type Proposal struct {
+	Term        string
+	Type        string
+	Translation string
+	Rationale   string
+}
+
+// Count returns the occurrences across all episodes
+func (t Term) Count() int {
+	n := 0
+	for _, c := range t.Episodes {
+		n += c
+	}
+	return n
+}
+
+// Target returns the translation, or the original when the term is kept as-is
+func (t Term) Target() string {
+	if t.Translation == "" || t.Enforce == EnforceKeep {
+		return t.Original
+	}
+	return t.Translation
+}
+
+// Promote approves the term, keeping it as-is unless it has a translation
+func (t *Term) Promote() {
+	t.Status = StatusApproved
+	t.Proposed = false
+	if t.Translation == "" {
+		t.Translation = t.Original
+	}
+}
+
+// Reject excludes the term from translations
+func (t *Term) Reject() {
+	t.Status = StatusRejected
+}
+
+// Store is the glossary of one series
+type Store struct {
+	Path  string
+	Terms []Term
+}
+
+// PathFor returns the series glossary path for a media directory
+func PathFor(dir string) string {
+	return filepath.Join(dir, FileName)
+}
+
+// Load reads a series glossary; a missing file gives an empty store
+func Load(path string) (*Store, error) {
+	s := &Store{Path: path, Terms: []Term{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.Terms); err != nil {
+		return nil, err
+	}
+	for i := range s.Terms {
+		if s.Terms[i].Status == "" {
+			s.Terms[i].Status = StatusApproved
+		}
+	}
+	return s, nil
+}
+
+// Save writes the glossary back to its file
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s.Terms, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0644)
+}
+
+// Find returns the term with the given original text, ignoring case
+func (s *Store) Find(original string) *Term {
+	for i := range s.Terms {
+		if strings.EqualFold(s.Terms[i].Original, original) {
+			return &s.Terms[i]
+		}
+	}
+	return nil
+}
+
+// Set adds or updates an approved term
+func (s *Store) Set(original, translation, termType string) {
+	if t := s.Find(original); t != nil {
+		t.Translation = translation
+		t.Status = StatusApproved
+		if termType != "" {
+			t.Type = termType
+		}
+		return
+	}
+	s.Terms = append(s.Terms, Term{Original: original, Translation: translation, Type: termType, Status: StatusApproved})
+}
+
+// Remove deletes a term and reports whether it existed
+func (s *Store) Remove(original string) bool {
+	for i := range s.Terms {
+		if strings.EqualFold(s.Terms[i].Original, original) {
+			s.Terms = append(s.Terms[:i], s.Terms[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Record adds the entities detected in an episode, returning how many were
+// new candidates. Recording the same episode again replaces its counts.
+func (s *Store) Record(episode string, entities []ner.Entity) int {
+	added := 0
+	for _, e := range entities {
+		t := s.Find(e.Text)
+		if t == nil {
+			s.Terms = append(s.Terms, Term{
+				Original:     e.Text,
+				Type:         string(e.Type),
+				AutoDetected: true,
+				Status:       StatusCandidate,
+			})
+			t = &s.Terms[len(s.Terms)-1]
+			added++
+		}
+		if t.Episodes == nil {
+			t.Episodes = make(map[string]int)
+		}
+		t.Episodes[episode] = e.Count
+		if e.Confidence > t.Confidence {
+			t.Confidence = e.Confidence
+		}
+	}
+	return added
+}
+
+// Propose adds the terms suggested by the LLM pre-pass as candidates,
+// returning how many were new. Candidates get the proposed translation;
+// reviewed terms are left alone.
+func (s *Store) Propose(proposals []Proposal) int {
+	added := 0
+	for _, pr := range proposals {
+		if strings.TrimSpace(pr.Term) == "" {
+			continue
+		}
+		t := s.Find(pr.Term)
+		if t == nil {
+			s.Terms = append(s.Terms, Term{
+				Original:     pr.Term,
+				Type:         pr.Type,
+				AutoDetected: true,
+				Status:       StatusCandidate,
+			})
+			t = &s.Terms[len(s.Terms)-1]
+			added++
+		}
+		if t.Status != StatusCandidate {
+			continue
+		}
+		if pr.Translation != "" {
+			t.Translation = pr.Translation
+		}
+		if t.Type == "" {
+			t.Type = pr.Type
+		}
+		t.Rationale = pr.Rationale
+		t.Proposed = true
+	}
+	return added
+}
+
+// Proposed returns how many candidates wait for a proposed translation to be approved
+func (s *Store) Proposed() int {
+	n := 0
+	for _, t := range s.Terms {
+		if t.Status == StatusCandidate && t.Proposed {
+			n++
+		}
+	}
+	return n
+}
+
+// Glossary returns the approved terms
+func (s *Store) Glossary() map[string]string {
+	glossary := make(map[string]string)
+	for _, t := range s.Terms {
+		if t.Status == StatusApproved {
+			glossary[t.Original] = t.Target()
+		}
+	}
+	return glossary
+}
+
+// Enforced returns the approved terms checked after translation: those kept
+// or forced and those with forbidden renderings
+func (s *Store) Enforced() []linter.Term {
+	var terms []linter.Term
+	for _, t := range s.Terms {
+		if t.Status != StatusApproved || (t.Enforce == EnforcePrompt && len(t.Forbidden) == 0) {
+			continue
+		}
+		terms = append(terms, linter.Term{
+			Source:    t.Original,
+			Target:    t.Target(),
+			Keep:      t.Enforce == EnforceKeep,
+			Force:     t.Enforce == EnforceForce,
+			Forbidden: t.Forbidden,
+			Inflect:   t.Inflect,
+		})
+	}
+	return terms
+}
+
+// Candidates returns the detected terms still waiting for review as
+// entities. Proposed translations need approval and are left out.
+func (s *Store) Candidates() []ner.Entity {
+	var entities []ner.Entity
+	for _, t := range s.Terms {
+		if t.Status == StatusCandidate && !t.Proposed {
+			entities = append(entities, ner.Entity{
+				Text:       t.Original,
+				Type:       ner.EntityType(t.Type),
+				Confidence: t.Confidence,
+				Count:      t.Count(),
+			})
+		}
+	}
+	return entities
+}
+
+// Merge returns the glossary for a job: confident candidates kept as-is,
+// overridden by the approved terms and then by the job's own terms
+func (s *Store) Merge(job map[string]string) map[string]string {
+	glossary := s.Glossary()
+	for orig, trans := range job {
+		glossary[orig] = trans
+	}
+	return ner.MergeWithProjectGlossary(s.Candidates(), glossary)
+}
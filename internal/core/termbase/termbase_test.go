@@ -0,0 +1,148 @@
+package termbase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/ner"
+)
+
+// TestLoadLegacy tests that entries without a status load as approved
+func TestLoadLegacy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	legacy := `[{"original":"Konoha","translation":"Konoha","type":"Place","auto_detected":true}]`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(s.Terms) != 1 || s.Terms[0].Status != StatusApproved {
+		t.Errorf("unexpected terms: %+v", s.Terms)
+	}
+
+	if s, err := Load(filepath.Join(t.TempDir(), FileName)); err != nil || len(s.Terms) != 0 {
+		t.Errorf("a missing file should give an empty store, got %+v, %v", s, err)
+	}
+}
+
+// TestRecord tests that candidates accumulate counts across episodes
+func TestRecord(t *testing.T) {
+	s := &Store{Path: filepath.Join(t.TempDir(), FileName)}
+	s.Set("Naruto", "Naruto", "Name")
+
+	ep1 := []ner.Entity{
+		{Text: "Naruto", Type: ner.EntityName, Confidence: 0.9, Count: 5},
+		{Text: "Konoha", Type: ner.EntityPlace, Confidence: 0.8, Count: 2},
+	}
+	if added := s.Record("ep01.mkv", ep1); added != 1 {
+		t.Errorf("added %d candidates, want 1", added)
+	}
+	s.Record("ep02.mkv", []ner.Entity{{Text: "konoha", Type: ner.EntityPlace, Confidence: 0.7, Count: 3}})
+	s.Record("ep02.mkv", []ner.Entity{{Text: "Konoha", Type: ner.EntityPlace, Confidence: 0.7, Count: 4}})
+
+	konoha := s.Find("KONOHA")
+	if konoha == nil || konoha.Status != StatusCandidate || konoha.Count() != 6 || konoha.Confidence != 0.8 {
+		t.Fatalf("unexpected candidate: %+v", konoha)
+	}
+	if naruto := s.Find("Naruto"); naruto.Status != StatusApproved || naruto.Count() != 5 {
+		t.Errorf("approved terms should keep their status and count occurrences: %+v", naruto)
+	}
+
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(s.Path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := loaded.Find("Konoha"); got == nil || got.Count() != 6 || got.Status != StatusCandidate {
+		t.Errorf("candidate not persisted: %+v", got)
+	}
+}
+
+// TestMerge tests the glossary handed to jobs
+func TestMerge(t *testing.T) {
+	s := &Store{}
+	s.Record("ep01.mkv", []ner.Entity{
+		{Text: "Konoha", Confidence: 0.9, Count: 3},
+		{Text: "Dattebayo", Confidence: 0.9, Count: 3},
+		{Text: "Maybe", Confidence: 0.5, Count: 9},
+		{Text: "Rasengan", Confidence: 0.9, Count: 2},
+	})
+	s.Find("Dattebayo").Reject()
+	s.Find("Rasengan").Translation = "Rasengan"
+	s.Find("Rasengan").Promote()
+	s.Set("Hokage", "Hokage", "Title")
+
+	got := s.Merge(map[string]string{"Hokage": "Kage da Folha"})
+	want := map[string]string{"Konoha": "Konoha", "Rasengan": "Rasengan", "Hokage": "Kage da Folha"}
+	if len(got) != len(want) {
+		t.Fatalf("Merge = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Merge[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+
+	if g := s.Glossary(); len(g) != 2 || g["Rasengan"] != "Rasengan" {
+		t.Errorf("Glossary should only hold approved terms, got %v", g)
+	}
+}
+
+// TestPropose tests adding LLM proposals that wait for approval
+func TestPropose(t *testing.T) {
+	s := &Store{}
+	s.Record("ep01.mkv", []ner.Entity{{Text: "Konoha", Confidence: 0.9, Count: 3}})
+	s.Set("Hokage", "Hokage", "Title")
+
+	added := s.Propose([]Proposal{
+		{Term: "Konoha", Type: "Place", Translation: "Vila da Folha", Rationale: "village name"},
+		{Term: "nakama", Type: "Term", Translation: "companheiro", Rationale: "recurring word"},
+		{Term: "Hokage", Translation: "Kage"},
+		{Term: " "},
+	})
+	if added != 1 || s.Proposed() != 2 {
+		t.Fatalf("added %d, proposed %d; want 1 and 2", added, s.Proposed())
+	}
+	if h := s.Find("Hokage"); h.Translation != "Hokage" || h.Proposed {
+		t.Errorf("approved terms should be left alone: %+v", h)
+	}
+	if got := s.Merge(nil); len(got) != 1 || got["Hokage"] != "Hokage" {
+		t.Errorf("proposals should not be used before approval, got %v", got)
+	}
+
+	s.Find("nakama").Promote()
+	if got := s.Merge(nil); got["nakama"] != "companheiro" {
+		t.Errorf("approved proposal should be used, got %v", got)
+	}
+}
+
+// TestEnforced tests which approved terms are enforced after translation
+func TestEnforced(t *testing.T) {
+	s := &Store{Terms: []Term{
+		{Original: "Konoha", Translation: "Vila da Folha", Status: StatusApproved, Enforce: EnforceKeep},
+		{Original: "nakama", Translation: "companheiro", Status: StatusApproved, Enforce: EnforceForce},
+		{Original: "Hokage", Translation: "Hokage", Status: StatusApproved, Forbidden: []string{"Kage da Folha"}},
+		{Original: "Naruto", Translation: "Naruto", Status: StatusApproved},
+		{Original: "Sasuke", Status: StatusCandidate, Enforce: EnforceKeep},
+	}}
+
+	terms := s.Enforced()
+	if len(terms) != 3 {
+		t.Fatalf("got %d enforced terms, want 3: %+v", len(terms), terms)
+	}
+	if !terms[0].Keep || terms[0].Want() != "Konoha" || !terms[1].Force || terms[1].Want() != "companheiro" {
+		t.Errorf("unexpected flags: %+v", terms)
+	}
+	if s.Glossary()["Konoha"] != "Konoha" {
+		t.Errorf("kept terms should be listed untranslated, got %v", s.Glossary())
+	}
+	if EnforcePrompt.Next() != EnforceKeep || EnforceForce.Next() != EnforcePrompt {
+		t.Error("Next should cycle prompt, keep and force")
+	}
+}
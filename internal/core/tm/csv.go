@@ -0,0 +1,118 @@
+package tm
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+)
+
+// entryColumns is the header written to CSV/TSV exports
+var entryColumns = []string{
+	"source_lang", "target_lang", "source", "target",
+	"model", "profile", "glossary_hash", "project_id", "provenance", "created_at",
+}
+
+// WriteDelimited writes entries as CSV (comma ',') or TSV (comma '\t') with a header row
+func WriteDelimited(w io.Writer, entries []db.CacheEntry, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	if err := cw.Write(entryColumns); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		source, target := db.SplitLangPair(e.LangPair)
+		createdAt := ""
+		if !e.CreatedAt.IsZero() {
+			createdAt = e.CreatedAt.UTC().Format(time.RFC3339)
+		}
+		if err := cw.Write([]string{
+			source, target, e.OriginalText, e.TranslatedText,
+			e.Model, e.Profile, e.GlossaryHash, e.ProjectID, e.Provenance, createdAt,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadDelimited reads entries from CSV or TSV with a header row. Columns are
+// matched by name (case-insensitive, any order); "source" and "target" are
+// required, and the language pair comes from "source_lang"/"target_lang" or
+// a "lang_pair" column.
+func ReadDelimited(r io.Reader, comma rune) ([]db.CacheEntry, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	col := exchange.Columns(header, nil)
+	if _, ok := col["source"]; !ok {
+		return nil, fmt.Errorf("missing %q column", "source")
+	}
+	if _, ok := col["target"]; !ok {
+		return nil, fmt.Errorf("missing %q column", "target")
+	}
+
+	var entries []db.CacheEntry
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			if i, ok := col[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+
+		langPair := field("lang_pair")
+		if source, target := field("source_lang"), field("target_lang"); source != "" && target != "" {
+			langPair = db.JoinLangPair(source, target)
+		}
+		if langPair == "" {
+			return nil, fmt.Errorf("line %d: missing language pair", line)
+		}
+
+		entry := db.CacheEntry{
+			OriginalText:   field("source"),
+			TranslatedText: field("target"),
+			LangPair:       langPair,
+			Model:          field("model"),
+			Profile:        field("profile"),
+			GlossaryHash:   field("glossary_hash"),
+			ProjectID:      field("project_id"),
+			Provenance:     field("provenance"),
+		}
+		if createdAt := field("created_at"); createdAt != "" {
+			t, err := time.Parse(time.RFC3339, createdAt)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid created_at %q", line, createdAt)
+			}
+			entry.CreatedAt = t
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,58 @@
+package tm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+)
+
+// TestDelimitedRoundTrip tests CSV and TSV export/import
+func TestDelimitedRoundTrip(t *testing.T) {
+	entries := []db.CacheEntry{
+		{OriginalText: "Hello, \"friend\"", TranslatedText: "Olá,\tamigo", LangPair: "en->pt",
+			Model: "gpt-4o", ProjectID: "show", Provenance: db.ProvenanceMachine,
+			CreatedAt: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)},
+		{OriginalText: "Bye", TranslatedText: "Tchau\nmais tarde", LangPair: "en->pt", Provenance: db.ProvenanceApproved},
+	}
+
+	for _, comma := range []rune{',', '\t'} {
+		var buf bytes.Buffer
+		if err := WriteDelimited(&buf, entries, comma); err != nil {
+			t.Fatalf("WriteDelimited(%q) failed: %v", comma, err)
+		}
+
+		got, err := ReadDelimited(&buf, comma)
+		if err != nil {
+			t.Fatalf("ReadDelimited(%q) failed: %v", comma, err)
+		}
+		if len(got) != len(entries) {
+			t.Fatalf("got %d entries, want %d", len(got), len(entries))
+		}
+		for i := range entries {
+			if got[i] != entries[i] {
+				t.Errorf("%q entry %d:\n got  %+v\n want %+v", comma, i, got[i], entries[i])
+			}
+		}
+	}
+}
+
+// TestReadDelimitedColumns tests header matching and validation
+func TestReadDelimitedColumns(t *testing.T) {
+	entries, err := ReadDelimited(strings.NewReader("\uFEFFTarget,Source,Lang_Pair\nOlá,Hello,en->pt\n"), ',')
+	if err != nil {
+		t.Fatalf("ReadDelimited failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].OriginalText != "Hello" || entries[0].TranslatedText != "Olá" || entries[0].LangPair != "en->pt" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+
+	if _, err := ReadDelimited(strings.NewReader("source,lang_pair\nHello,en->pt\n"), ','); err == nil {
+		t.Error("expected error for missing target column")
+	}
+	if _, err := ReadDelimited(strings.NewReader("source,target\nHello,Olá\n"), ','); err == nil {
+		t.Error("expected error for missing language pair")
+	}
+}
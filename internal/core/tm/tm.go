@@ -0,0 +1,112 @@
+// Package tm imports and exports the translation memory in formats shared
+// with other CAT tools: TMX 1.4 and CSV/TSV.
+package tm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+)
+
+// Formats are the translation memory file formats
+var Formats = []exchange.Format{exchange.FormatTMX, exchange.FormatCSV, exchange.FormatTSV}
+
+// AutoLang is the source language recorded by jobs that detect it
+const AutoLang = "auto"
+
+// Export writes the cache entries matching filter to path.
+// An empty format is derived from the file extension. sourceLang replaces
+// the "auto" source language of entries; TMX export fails without it when
+// such entries are selected.
+// It returns the number of entries written.
+func Export(cache *db.Cache, path string, format exchange.Format, filter db.EntryFilter, sourceLang string) (int, error) {
+	format, err := exchange.Resolve(path, format, Formats...)
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := cache.ListEntries(filter)
+	if err != nil {
+		return 0, err
+	}
+	if sourceLang != "" {
+		setAutoSourceLang(entries, sourceLang)
+	}
+	if format == exchange.FormatTMX {
+		if err := checkTMXLangs(entries); err != nil {
+			return 0, err
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	switch format {
+	case exchange.FormatTMX:
+		err = WriteTMX(f, entries)
+	case exchange.FormatTSV:
+		err = WriteDelimited(f, entries, '\t')
+	default:
+		err = WriteDelimited(f, entries, ',')
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return len(entries), nil
+}
+
+// Import merges the entries of path matching filter into the cache.
+// An empty format is derived from the file extension.
+func Import(cache *db.Cache, path string, format exchange.Format, filter db.EntryFilter, policy exchange.ConflictPolicy) (exchange.ImportResult, error) {
+	format, err := exchange.Resolve(path, format, Formats...)
+	if err != nil {
+		return exchange.ImportResult{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return exchange.ImportResult{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var entries []db.CacheEntry
+	switch format {
+	case exchange.FormatTMX:
+		entries, err = ReadTMX(f)
+	case exchange.FormatTSV:
+		entries, err = ReadDelimited(f, '\t')
+	default:
+		entries, err = ReadDelimited(f, ',')
+	}
+	if err != nil {
+		return exchange.ImportResult{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	selected := entries[:0]
+	for _, e := range entries {
+		if filter.Matches(e) {
+			selected = append(selected, e)
+		}
+	}
+
+	return cache.ImportEntries(selected, policy)
+}
+
+// setAutoSourceLang replaces the "auto" source language of entries with lang
+func setAutoSourceLang(entries []db.CacheEntry, lang string) {
+	for i, e := range entries {
+		if source, target := db.SplitLangPair(e.LangPair); strings.EqualFold(source, AutoLang) {
+			entries[i].LangPair = db.JoinLangPair(lang, target)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package tm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+)
+
+// TestFormats tests format detection with the translation memory formats
+func TestFormats(t *testing.T) {
+	for path, want := range map[string]exchange.Format{
+		"memory.tmx": exchange.FormatTMX,
+		"memory.CSV": exchange.FormatCSV,
+		"memory.tsv": exchange.FormatTSV,
+	} {
+		if got, err := exchange.FormatForPath(path, Formats...); err != nil || got != want {
+			t.Errorf("FormatForPath(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+
+	for _, path := range []string{"memory.xlsx", "glossary.tbx"} {
+		if _, err := exchange.FormatForPath(path, Formats...); err == nil {
+			t.Errorf("expected error for %q", path)
+		}
+	}
+}
+
+// TestExportImport tests moving entries between two caches through each format
+func TestExportImport(t *testing.T) {
+	dir := t.TempDir()
+	source, err := db.Open(filepath.Join(dir, "source.db"))
+	if err != nil {
+		t.Fatalf("db.Open failed: %v", err)
+	}
+	defer source.Close()
+
+	source.SaveScopedTranslation("Hello", "Olá", db.Scope{LangPair: "en->pt", ProjectID: "show"})
+	source.ApproveTranslation("Bye", "Tchau", db.Scope{LangPair: "en->pt", ProjectID: "show"})
+	source.SaveScopedTranslation("Hello", "Hola", db.Scope{LangPair: "en->es", ProjectID: "show"})
+
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			path := filepath.Join(dir, "memory."+string(format))
+			n, err := Export(source, path, "", db.EntryFilter{LangPair: "en->pt"}, "")
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+			if n != 2 {
+				t.Errorf("exported %d entries, want 2", n)
+			}
+
+			target, err := db.Open(filepath.Join(dir, string(format)+".db"))
+			if err != nil {
+				t.Fatalf("db.Open failed: %v", err)
+			}
+			defer target.Close()
+			target.SaveScopedTranslation("Bye", "Adeus", db.Scope{LangPair: "en->pt", ProjectID: "show"})
+
+			result, err := Import(target, path, format, db.EntryFilter{}, exchange.ConflictPreferApproved)
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if result.Added != 1 || result.Updated != 1 {
+				t.Errorf("result = %+v, want 1 added, 1 updated", result)
+			}
+
+			entry, found := target.GetScopedMatch("Bye", db.Scope{LangPair: "en->pt", ProjectID: "show"}, db.PolicyStrict)
+			if !found || entry.TranslatedText != "Tchau" || !entry.IsApproved() {
+				t.Errorf("got %+v, want approved Tchau", entry)
+			}
+		})
+	}
+}
+
+// TestExportAutoSourceLang tests that "auto" source languages need an explicit one in TMX
+func TestExportAutoSourceLang(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := db.Open(filepath.Join(dir, "memory.db"))
+	if err != nil {
+		t.Fatalf("db.Open failed: %v", err)
+	}
+	defer cache.Close()
+	cache.SaveScopedTranslation("Hello", "Olá", db.Scope{LangPair: "auto->pt-br"})
+
+	path := filepath.Join(dir, "memory.tmx")
+	if _, err := Export(cache, path, "", db.EntryFilter{}, ""); err == nil {
+		t.Fatal("expected TMX export of auto entries to fail")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("failed export should not create the file")
+	}
+
+	if n, err := Export(cache, path, "", db.EntryFilter{}, "en"); err != nil || n != 1 {
+		t.Fatalf("Export with source language = %d, %v", n, err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), `"auto"`) || !strings.Contains(string(data), `xml:lang="en"`) {
+		t.Errorf("expected the explicit source language in:\n%s", data)
+	}
+
+	// CSV keeps the pair as recorded
+	if _, err := Export(cache, filepath.Join(dir, "memory.csv"), "", db.EntryFilter{}, ""); err != nil {
+		t.Errorf("CSV export failed: %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+package tm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/pkg/utils"
+)
+
+// tmxTimeFormat is the TMX 1.4 date format (ISO 8601 basic, UTC)
+const tmxTimeFormat = "20060102T150405Z"
+
+// Property types carrying BakaSub scope details through TMX files.
+// Other tools preserve unknown "x-" properties.
+const (
+	propModel        = "x-bakasub-model"
+	propProfile      = "x-bakasub-profile"
+	propGlossaryHash = "x-bakasub-glossary"
+	propProject      = "x-bakasub-project"
+	propProvenance   = "x-bakasub-provenance"
+)
+
+type tmxDocument struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	Units   []tmxUnit `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTmf                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+	CreationDate        string `xml:"creationdate,attr,omitempty"`
+}
+
+type tmxUnit struct {
+	SrcLang      string       `xml:"srclang,attr,omitempty"`
+	CreationDate string       `xml:"creationdate,attr,omitempty"`
+	Props        []tmxProp    `xml:"prop"`
+	Variants     []tmxVariant `xml:"tuv"`
+}
+
+type tmxProp struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tmxVariant struct {
+	Lang    string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	OldLang string `xml:"lang,attr,omitempty"` // TMX 1.1 files
+	Seg     tmxSeg `xml:"seg"`
+}
+
+// tmxSeg keeps the raw segment so inline markup (<bpt>, <ph>, <hi>...) can be flattened
+type tmxSeg struct {
+	Inner string `xml:",innerxml"`
+}
+
+// lang returns the variant language
+func (v tmxVariant) lang() string {
+	if v.Lang != "" {
+		return v.Lang
+	}
+	return v.OldLang
+}
+
+// WriteTMX writes entries as a TMX 1.4 document, one translation unit per entry.
+// Every entry needs explicit languages: "auto" is not a valid xml:lang.
+func WriteTMX(w io.Writer, entries []db.CacheEntry) error {
+	if err := checkTMXLangs(entries); err != nil {
+		return err
+	}
+
+	srcLang := "*all*"
+	if len(entries) > 0 {
+		first, _ := db.SplitLangPair(entries[0].LangPair)
+		srcLang = first
+		for _, e := range entries[1:] {
+			if source, _ := db.SplitLangPair(e.LangPair); source != first {
+				srcLang = "*all*"
+				break
+			}
+		}
+	}
+
+	doc := tmxDocument{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "BakaSub",
+			CreationToolVersion: utils.Version,
+			SegType:             "sentence",
+			OTmf:                "BakaSub",
+			AdminLang:           "en",
+			SrcLang:             srcLang,
+			DataType:            "plaintext",
+			CreationDate:        time.Now().UTC().Format(tmxTimeFormat),
+		},
+	}
+
+	for _, e := range entries {
+		source, target := db.SplitLangPair(e.LangPair)
+		unit := tmxUnit{
+			SrcLang: source,
+			Variants: []tmxVariant{
+				{Lang: source, Seg: tmxSeg{Inner: escapeXML(e.OriginalText)}},
+				{Lang: target, Seg: tmxSeg{Inner: escapeXML(e.TranslatedText)}},
+			},
+		}
+		if !e.CreatedAt.IsZero() {
+			unit.CreationDate = e.CreatedAt.UTC().Format(tmxTimeFormat)
+		}
+		for _, p := range []tmxProp{
+			{Type: propModel, Value: e.Model},
+			{Type: propProfile, Value: e.Profile},
+			{Type: propGlossaryHash, Value: e.GlossaryHash},
+			{Type: propProject, Value: e.ProjectID},
+			{Type: propProvenance, Value: e.Provenance},
+		} {
+			if p.Value != "" {
+				unit.Props = append(unit.Props, p)
+			}
+		}
+		doc.Units = append(doc.Units, unit)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// checkTMXLangs fails when an entry lacks an explicit source or target language
+func checkTMXLangs(entries []db.CacheEntry) error {
+	unknown := 0
+	for _, e := range entries {
+		source, target := db.SplitLangPair(e.LangPair)
+		if source == "" || target == "" || strings.EqualFold(source, AutoLang) {
+			unknown++
+		}
+	}
+	if unknown > 0 {
+		return fmt.Errorf("%d entries have no explicit source language (%q); set the source language to export them as TMX", unknown, AutoLang)
+	}
+	return nil
+}
+
+// ReadTMX reads translation units from a TMX document. Each unit yields one
+// entry per target variant, paired with the variant in the unit's (or the
+// header's) source language.
+func ReadTMX(r io.Reader) ([]db.CacheEntry, error) {
+	var doc tmxDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid TMX: %w", err)
+	}
+
+	var entries []db.CacheEntry
+	for i, unit := range doc.Units {
+		if len(unit.Variants) < 2 {
+			continue
+		}
+
+		srcLang := unit.SrcLang
+		if srcLang == "" || srcLang == "*all*" {
+			srcLang = doc.Header.SrcLang
+		}
+		srcIdx := 0
+		for j, v := range unit.Variants {
+			if strings.EqualFold(v.lang(), srcLang) {
+				srcIdx = j
+				break
+			}
+		}
+		source := unit.Variants[srcIdx]
+		sourceText, err := segText(source.Seg)
+		if err != nil {
+			return nil, fmt.Errorf("translation unit %d: %w", i+1, err)
+		}
+
+		base := db.CacheEntry{OriginalText: sourceText}
+		if t, err := time.Parse(tmxTimeFormat, unit.CreationDate); err == nil {
+			base.CreatedAt = t
+		}
+		for _, p := range unit.Props {
+			switch p.Type {
+			case propModel:
+				base.Model = p.Value
+			case propProfile:
+				base.Profile = p.Value
+			case propGlossaryHash:
+				base.GlossaryHash = p.Value
+			case propProject:
+				base.ProjectID = p.Value
+			case propProvenance:
+				base.Provenance = p.Value
+			}
+		}
+
+		for j, v := range unit.Variants {
+			if j == srcIdx {
+				continue
+			}
+			text, err := segText(v.Seg)
+			if err != nil {
+				return nil, fmt.Errorf("translation unit %d: %w", i+1, err)
+			}
+			entry := base
+			entry.TranslatedText = text
+			entry.LangPair = db.JoinLangPair(source.lang(), v.lang())
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// segText flattens a segment's inline markup, keeping the native codes of
+// <bpt>/<ept>/<ph>/<it> (e.g. ASS override tags) and the text of <hi>/<sub>
+func segText(seg tmxSeg) (string, error) {
+	dec := xml.NewDecoder(strings.NewReader("<seg>" + seg.Inner + "</seg>"))
+	var sb strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid segment: %w", err)
+		}
+		if data, ok := tok.(xml.CharData); ok {
+			sb.Write(data)
+		}
+	}
+}
+
+// escapeXML escapes text for use as raw segment content
+func escapeXML(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
@@ -0,0 +1,81 @@
+package tm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+)
+
+// TestTMXRoundTrip tests that exported entries read back unchanged
+func TestTMXRoundTrip(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	entries := []db.CacheEntry{
+		{OriginalText: "{\\i1}Hello{\\i0} & <bye>", TranslatedText: "{\\i1}Olá{\\i0}\\NTchau", LangPair: "en->pt-br",
+			Model: "gpt-4o", Profile: "anime", GlossaryHash: "abc", ProjectID: "show", Provenance: db.ProvenanceApproved, CreatedAt: created},
+		{OriginalText: "Line one\nline two", TranslatedText: "Linha um\nlinha dois", LangPair: "en->pt-br"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTMX(&buf, entries); err != nil {
+		t.Fatalf("WriteTMX failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{`<tmx version="1.4">`, `srclang="en"`, `xml:lang="pt-br"`, `creationdate="20240501T103000Z"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %s:\n%s", want, out)
+		}
+	}
+
+	got, err := ReadTMX(&buf)
+	if err != nil {
+		t.Fatalf("ReadTMX failed: %v", err)
+	}
+	if len(got) != len(entries) {
+		t.Fatalf("got %d entries, want %d", len(got), len(entries))
+	}
+	for i := range entries {
+		if got[i] != entries[i] {
+			t.Errorf("entry %d:\n got  %+v\n want %+v", i, got[i], entries[i])
+		}
+	}
+}
+
+// TestReadTMXFromOtherTools tests multilingual units and inline markup
+func TestReadTMXFromOtherTools(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="OtherCAT" creationtoolversion="1" segtype="sentence" o-tmf="x" adminlang="en-US" srclang="ja" datatype="plaintext"/>
+  <body>
+    <tu>
+      <tuv xml:lang="en"><seg>See you</seg></tuv>
+      <tuv xml:lang="ja"><seg><ph>{\i1}</ph>またね</seg></tuv>
+      <tuv xml:lang="pt"><seg>Até <hi>mais</hi></seg></tuv>
+    </tu>
+    <tu><tuv xml:lang="ja"><seg>孤立</seg></tuv></tu>
+  </body>
+</tmx>`
+
+	entries, err := ReadTMX(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ReadTMX failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %+v", len(entries), entries)
+	}
+	if entries[0].OriginalText != "{\\i1}またね" || entries[0].LangPair != "ja->en" || entries[0].TranslatedText != "See you" {
+		t.Errorf("unexpected first entry %+v", entries[0])
+	}
+	if entries[1].LangPair != "ja->pt" || entries[1].TranslatedText != "Até mais" {
+		t.Errorf("unexpected second entry %+v", entries[1])
+	}
+}
+
+// TestReadTMXInvalid tests malformed input
+func TestReadTMXInvalid(t *testing.T) {
+	if _, err := ReadTMX(strings.NewReader("<tmx><body><tu>")); err == nil {
+		t.Error("expected error for truncated document")
+	}
+}
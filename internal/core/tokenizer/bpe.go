@@ -0,0 +1,132 @@
+package tokenizer
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Encoding is a byte-level BPE tokenizer compatible with tiktoken vocabularies
+type Encoding struct {
+	name  string
+	ranks map[string]int
+	split func(string) []string
+}
+
+// LoadEncoding reads a vocabulary in tiktoken format ("<base64 token> <rank>"
+// per line). The vocabulary must cover all 256 single bytes so any input can
+// be encoded.
+func LoadEncoding(name string, r io.Reader) (*Encoding, error) {
+	ranks := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid vocabulary line: %q", line)
+		}
+
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid token %q: %w", fields[0], err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid rank %q: %w", fields[1], err)
+		}
+
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vocabulary: %w", err)
+	}
+
+	for b := 0; b < 256; b++ {
+		if _, ok := ranks[string([]byte{byte(b)})]; !ok {
+			return nil, fmt.Errorf("vocabulary %s is missing byte 0x%02x", name, b)
+		}
+	}
+
+	return &Encoding{
+		name:  name,
+		ranks: ranks,
+		split: splitterFor(name),
+	}, nil
+}
+
+// Name returns the encoding name (e.g. "cl100k_base")
+func (e *Encoding) Name() string {
+	return e.name
+}
+
+// Encode returns the token ranks of text
+func (e *Encoding) Encode(text string) []int {
+	var tokens []int
+	for _, piece := range e.split(text) {
+		if rank, ok := e.ranks[piece]; ok {
+			tokens = append(tokens, rank)
+			continue
+		}
+		bounds := e.merge([]byte(piece))
+		for i := 0; i < len(bounds)-1; i++ {
+			tokens = append(tokens, e.ranks[piece[bounds[i]:bounds[i+1]]])
+		}
+	}
+	return tokens
+}
+
+// Count returns the number of tokens in text without allocating the token list
+func (e *Encoding) Count(text string) int {
+	count := 0
+	for _, piece := range e.split(text) {
+		if _, ok := e.ranks[piece]; ok {
+			count++
+			continue
+		}
+		count += len(e.merge([]byte(piece))) - 1
+	}
+	return count
+}
+
+// merge applies BPE to a piece, returning the boundaries of its tokens.
+// The adjacent pair with the lowest rank is merged until no pair is in the vocabulary.
+func (e *Encoding) merge(piece []byte) []int {
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+
+	for len(bounds) > 2 {
+		minRank := math.MaxInt
+		minIdx := -1
+		for i := 0; i < len(bounds)-2; i++ {
+			if rank, ok := e.ranks[string(piece[bounds[i]:bounds[i+2]])]; ok && rank < minRank {
+				minRank = rank
+				minIdx = i
+			}
+		}
+		if minIdx < 0 {
+			break
+		}
+		bounds = append(bounds[:minIdx+1], bounds[minIdx+2:]...)
+	}
+
+	return bounds
+}
+
+// splitterFor returns the pre-tokenizer of an encoding
+func splitterFor(name string) func(string) []string {
+	if name == EncodingO200K {
+		return splitO200K
+	}
+	return splitCL100K
+}
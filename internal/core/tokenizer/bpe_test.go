@@ -0,0 +1,149 @@
+package tokenizer
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testVocabulary builds a tiktoken-format vocabulary with all single bytes
+// followed by the given merges
+func testVocabulary(merges ...string) string {
+	var sb strings.Builder
+	for b := 0; b < 256; b++ {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(b)}), b)
+	}
+	for i, m := range merges {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(m)), 256+i)
+	}
+	return sb.String()
+}
+
+func TestLoadEncoding(t *testing.T) {
+	enc, err := LoadEncoding(EncodingCL100K, strings.NewReader(testVocabulary("he")))
+	if err != nil {
+		t.Fatalf("LoadEncoding failed: %v", err)
+	}
+	if enc.Name() != EncodingCL100K {
+		t.Errorf("Name() = %q", enc.Name())
+	}
+
+	if _, err := LoadEncoding("broken", strings.NewReader("aGk= 0\n")); err == nil {
+		t.Error("expected error for vocabulary missing single bytes")
+	}
+	if _, err := LoadEncoding("broken", strings.NewReader("not-a-line\n")); err == nil {
+		t.Error("expected error for malformed line")
+	}
+}
+
+func TestEncodeMerges(t *testing.T) {
+	enc, err := LoadEncoding(EncodingCL100K, strings.NewReader(testVocabulary("he", "ll", "hell", " world")))
+	if err != nil {
+		t.Fatalf("LoadEncoding failed: %v", err)
+	}
+
+	// "hello" -> he|l|l|o -> he|ll|o -> hell|o ; " world" is a single token
+	got := enc.Encode("hello world")
+	want := []int{258, 'o', 259}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Encode = %v, want %v", got, want)
+	}
+
+	if count := enc.Count("hello world"); count != len(want) {
+		t.Errorf("Count = %d, want %d", count, len(want))
+	}
+
+	// Unmerged UTF-8 falls back to one token per byte
+	if count := enc.Count("é"); count != 2 {
+		t.Errorf("Count(é) = %d, want 2", count)
+	}
+}
+
+func TestGetEncodingUnavailable(t *testing.T) {
+	t.Setenv("BAKASUB_HOME", t.TempDir())
+
+	enc, err := GetEncoding("missing_base")
+	if enc != nil || !errors.Is(err, ErrEncodingUnavailable) {
+		t.Fatalf("GetEncoding = %v, %v; want ErrEncodingUnavailable", enc, err)
+	}
+
+	e := &Estimator{encodingErr: err, split: splitCL100K}
+	if e.Exact() || !errors.Is(e.Err(), ErrEncodingUnavailable) {
+		t.Errorf("estimator without vocabulary should report why, got %v", e.Err())
+	}
+}
+
+func TestGetEncodingFromUserDir(t *testing.T) {
+	t.Setenv("BAKASUB_HOME", t.TempDir())
+
+	if err := os.MkdirAll(UserEncodingDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(UserEncodingDir(), "user_base.tiktoken")
+	if err := os.WriteFile(path, []byte(testVocabulary("he", "ll", "hell", " world")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := GetEncoding("user_base")
+	if err != nil {
+		t.Fatalf("GetEncoding failed: %v", err)
+	}
+	if count := enc.Count("hello world"); count != 3 {
+		t.Errorf("Count = %d, want 3", count)
+	}
+}
+
+// TestKnownTokenCounts checks counts against tiktoken for the embedded vocabularies
+func TestKnownTokenCounts(t *testing.T) {
+	t.Setenv("BAKASUB_HOME", t.TempDir()) // Only the embedded vocabularies
+
+	tests := []struct {
+		encoding string
+		text     string
+		want     int
+	}{
+		{EncodingCL100K, "hello world", 2},
+		{EncodingCL100K, "Hello, how are you today?", 7},
+		{EncodingO200K, "hello world", 2},
+		{EncodingO200K, "Hello, how are you today?", 7},
+		{EncodingCL100K, "Você está bem? Não se preocupe.", 10},
+		{EncodingO200K, "Você está bem? Não se preocupe.", 9},
+		{EncodingCL100K, "お元気ですか？", 6},
+		{EncodingO200K, "お元気ですか？", 6},
+		{EncodingCL100K, `{\i1}I'm sorry!\NThank you.`, 12},
+		{EncodingO200K, `{\i1}I'm sorry!\NThank you.`, 11},
+	}
+
+	for _, tt := range tests {
+		enc, err := GetEncoding(tt.encoding)
+		if err != nil {
+			t.Fatalf("GetEncoding(%s) failed: %v", tt.encoding, err)
+		}
+		if got := enc.Count(tt.text); got != tt.want {
+			t.Errorf("%s Count(%q) = %d, want %d", tt.encoding, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestEncodingForModel(t *testing.T) {
+	tests := map[string]string{
+		"gpt-4o":                   EncodingO200K,
+		"openai/gpt-4o-mini":       EncodingO200K,
+		"o3-mini":                  EncodingO200K,
+		"gpt-4-turbo":              EncodingCL100K,
+		"gpt-3.5-turbo":            EncodingCL100K,
+		"google/gemini-2.0-flash":  EncodingCL100K,
+		"anthropic/claude-3-haiku": EncodingCL100K,
+	}
+
+	for model, want := range tests {
+		if got := EncodingForModel(model); got != want {
+			t.Errorf("EncodingForModel(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package tokenizer
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/lsilvatti/bakasub/internal/paths"
+)
+
+//go:generate curl -sSfo encodings/cl100k_base.tiktoken https://openaipublic.blob.core.windows.net/encodings/cl100k_base.tiktoken
+//go:generate curl -sSfo encodings/o200k_base.tiktoken https://openaipublic.blob.core.windows.net/encodings/o200k_base.tiktoken
+
+// Supported encodings
+const (
+	EncodingCL100K = "cl100k_base" // GPT-4, GPT-3.5 and the default for other vendors
+	EncodingO200K  = "o200k_base"  // GPT-4o and newer OpenAI models
+)
+
+// embeddedEncodings holds the cl100k_base and o200k_base vocabularies committed
+// in encodings/ (go:generate above refreshes them)
+//
+//go:embed encodings
+var embeddedEncodings embed.FS
+
+// encodingEntry is a loaded vocabulary or the reason it could not be loaded
+type encodingEntry struct {
+	enc *Encoding
+	err error
+}
+
+var (
+	encodings   = make(map[string]encodingEntry)
+	encodingsMu sync.Mutex
+)
+
+// o200kPrefixes lists model families tokenized with o200k_base
+var o200kPrefixes = []string{"gpt-4o", "chatgpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "o1", "o3", "o4"}
+
+// EncodingForModel returns the encoding name for a model ID.
+// Non-OpenAI models use cl100k_base, which is a close approximation for
+// Gemini, Claude, Llama and Qwen tokenizers on subtitle text.
+func EncodingForModel(model string) string {
+	id := strings.ToLower(strings.TrimSpace(model))
+	if idx := strings.LastIndex(id, "/"); idx >= 0 {
+		id = id[idx+1:]
+	}
+
+	for _, prefix := range o200kPrefixes {
+		if strings.HasPrefix(id, prefix) {
+			return EncodingO200K
+		}
+	}
+	return EncodingCL100K
+}
+
+// ErrEncodingUnavailable is returned when no vocabulary file exists for an encoding
+var ErrEncodingUnavailable = errors.New("tokenizer vocabulary not available")
+
+// GetEncoding returns the named encoding, loading its vocabulary from the
+// embedded files or from the user's tokenizer directory. It fails with
+// ErrEncodingUnavailable when neither has the vocabulary.
+func GetEncoding(name string) (*Encoding, error) {
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+
+	if entry, ok := encodings[name]; ok {
+		return entry.enc, entry.err
+	}
+
+	enc, err := loadEmbeddedEncoding(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		enc, err = loadUserEncoding(name)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		err = fmt.Errorf("%w: %s (place %s.tiktoken in %s)",
+			ErrEncodingUnavailable, name, name, UserEncodingDir())
+	}
+
+	// Cache failures too, so a missing vocabulary is only searched for once
+	encodings[name] = encodingEntry{enc: enc, err: err}
+	return enc, err
+}
+
+// UserEncodingDir is where additional .tiktoken vocabularies can be placed
+func UserEncodingDir() string {
+	return filepath.Join(paths.CacheDir(), "tokenizers")
+}
+
+func loadEmbeddedEncoding(name string) (*Encoding, error) {
+	f, err := embeddedEncodings.Open("encodings/" + name + ".tiktoken")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadEncoding(name, f)
+}
+
+func loadUserEncoding(name string) (*Encoding, error) {
+	f, err := os.Open(filepath.Join(UserEncodingDir(), name+".tiktoken"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadEncoding(name, f)
+}
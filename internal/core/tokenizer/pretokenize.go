@@ -0,0 +1,249 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The splitters below reproduce the pre-tokenization regexes of tiktoken's
+// cl100k_base and o200k_base encodings. Go's regexp package has no lookahead,
+// so the patterns are matched by hand, alternative by alternative, with the
+// same leftmost-first semantics.
+
+// contractions are matched case-insensitively after a word (or on their own in cl100k)
+var contractions = []string{"'s", "'t", "'re", "'ve", "'m", "'ll", "'d"}
+
+// splitCL100K splits text into pieces following the cl100k_base pattern:
+//
+//	(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}|
+//	 ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+
+func splitCL100K(text string) []string {
+	rs := []rune(text)
+	var pieces []string
+
+	for i := 0; i < len(rs); {
+		end := matchContraction(rs, i)
+		if end < 0 {
+			end = matchLetters(rs, i)
+		}
+		if end < 0 {
+			end = matchDigits(rs, i)
+		}
+		if end < 0 {
+			end = matchPunctuation(rs, i, false)
+		}
+		if end < 0 {
+			end = matchWhitespace(rs, i)
+		}
+		if end <= i {
+			end = i + 1
+		}
+		pieces = append(pieces, string(rs[i:end]))
+		i = end
+	}
+
+	return pieces
+}
+
+// splitO200K splits text into pieces following the o200k_base pattern, which
+// additionally splits words at case changes and keeps contractions attached:
+//
+//	[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|...)?|
+//	[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|...)?|
+//	\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+(?!\S)|\s+
+func splitO200K(text string) []string {
+	rs := []rune(text)
+	var pieces []string
+
+	for i := 0; i < len(rs); {
+		end := matchCasedWord(rs, i)
+		if end < 0 {
+			end = matchDigits(rs, i)
+		}
+		if end < 0 {
+			end = matchPunctuation(rs, i, true)
+		}
+		if end < 0 {
+			end = matchWhitespace(rs, i)
+		}
+		if end <= i {
+			end = i + 1
+		}
+		pieces = append(pieces, string(rs[i:end]))
+		i = end
+	}
+
+	return pieces
+}
+
+func isLetter(r rune) bool { return unicode.IsLetter(r) }
+func isNumber(r rune) bool { return unicode.IsNumber(r) }
+func isSpace(r rune) bool  { return unicode.IsSpace(r) }
+func isNewline(r rune) bool {
+	return r == '\r' || r == '\n'
+}
+
+// isWordPrefix matches [^\r\n\p{L}\p{N}]
+func isWordPrefix(r rune) bool {
+	return !isNewline(r) && !isLetter(r) && !isNumber(r)
+}
+
+// isUpperClass matches [\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]
+func isUpperClass(r rune) bool {
+	return unicode.In(r, unicode.Lu, unicode.Lt, unicode.Lm, unicode.Lo, unicode.M)
+}
+
+// isLowerClass matches [\p{Ll}\p{Lm}\p{Lo}\p{M}]
+func isLowerClass(r rune) bool {
+	return unicode.In(r, unicode.Ll, unicode.Lm, unicode.Lo, unicode.M)
+}
+
+// matchContraction returns the end of a contraction at i, or -1
+func matchContraction(rs []rune, i int) int {
+	if i >= len(rs) || rs[i] != '\'' {
+		return -1
+	}
+	for _, c := range contractions {
+		n := len([]rune(c))
+		if i+n <= len(rs) && strings.EqualFold(string(rs[i:i+n]), c) {
+			return i + n
+		}
+	}
+	return -1
+}
+
+// matchLetters matches [^\r\n\p{L}\p{N}]?\p{L}+
+func matchLetters(rs []rune, i int) int {
+	start := i
+	if isWordPrefix(rs[i]) && i+1 < len(rs) && isLetter(rs[i+1]) {
+		start = i + 1
+	}
+	end := start
+	for end < len(rs) && isLetter(rs[end]) {
+		end++
+	}
+	if end == start {
+		return -1
+	}
+	return end
+}
+
+// matchCasedWord matches the two o200k word alternatives
+func matchCasedWord(rs []rune, i int) int {
+	starts := []int{i}
+	if isWordPrefix(rs[i]) {
+		// The optional prefix is tried first, then dropped on failure
+		starts = []int{i + 1, i}
+	}
+
+	for _, start := range starts {
+		if end := matchLowerEnding(rs, start); end > 0 {
+			return matchOptionalContraction(rs, end)
+		}
+	}
+	for _, start := range starts {
+		if end := matchUpperLeading(rs, start); end > 0 {
+			return matchOptionalContraction(rs, end)
+		}
+	}
+	return -1
+}
+
+// matchLowerEnding matches [upper]*[lower]+ with greedy backtracking
+func matchLowerEnding(rs []rune, start int) int {
+	upperEnd := start
+	for upperEnd < len(rs) && isUpperClass(rs[upperEnd]) {
+		upperEnd++
+	}
+
+	// Give back upper-class runes until the lower run can start
+	for split := upperEnd; split >= start; split-- {
+		end := split
+		for end < len(rs) && isLowerClass(rs[end]) {
+			end++
+		}
+		if end > split {
+			return end
+		}
+	}
+	return -1
+}
+
+// matchUpperLeading matches [upper]+[lower]*
+func matchUpperLeading(rs []rune, start int) int {
+	end := start
+	for end < len(rs) && isUpperClass(rs[end]) {
+		end++
+	}
+	if end == start {
+		return -1
+	}
+	for end < len(rs) && isLowerClass(rs[end]) {
+		end++
+	}
+	return end
+}
+
+// matchOptionalContraction extends end over a trailing contraction, if any
+func matchOptionalContraction(rs []rune, end int) int {
+	if next := matchContraction(rs, end); next > 0 {
+		return next
+	}
+	return end
+}
+
+// matchDigits matches \p{N}{1,3}
+func matchDigits(rs []rune, i int) int {
+	end := i
+	for end < len(rs) && end-i < 3 && isNumber(rs[end]) {
+		end++
+	}
+	if end == i {
+		return -1
+	}
+	return end
+}
+
+// matchPunctuation matches " ?[^\s\p{L}\p{N}]+[\r\n]*" (with "/" in the trailer for o200k)
+func matchPunctuation(rs []rune, i int, slashTrailer bool) int {
+	start := i
+	if rs[i] == ' ' {
+		start = i + 1
+	}
+	end := start
+	for end < len(rs) && !isSpace(rs[end]) && !isLetter(rs[end]) && !isNumber(rs[end]) {
+		end++
+	}
+	if end == start {
+		return -1
+	}
+	for end < len(rs) && (isNewline(rs[end]) || (slashTrailer && rs[end] == '/')) {
+		end++
+	}
+	return end
+}
+
+// matchWhitespace matches \s*[\r\n]+|\s+(?!\S)|\s+
+func matchWhitespace(rs []rune, i int) int {
+	end := i
+	for end < len(rs) && isSpace(rs[end]) {
+		end++
+	}
+	if end == i {
+		return -1
+	}
+
+	// \s*[\r\n]+ : up to the last newline of the run
+	for j := end - 1; j >= i; j-- {
+		if isNewline(rs[j]) {
+			return j + 1
+		}
+	}
+
+	// \s+(?!\S) : leave the last space to prefix the following word
+	if end < len(rs) && end-1 > i {
+		return end - 1
+	}
+
+	return end
+}
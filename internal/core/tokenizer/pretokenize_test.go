@@ -0,0 +1,47 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCL100K(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Hello world", []string{"Hello", " world"}},
+		{"I'm here", []string{"I", "'m", " here"}},
+		{"12345", []string{"123", "45"}},
+		{"Wait...  what?", []string{"Wait", "...", " ", " what", "?"}},
+		{"line\n\nnext", []string{"line", "\n\n", "next"}},
+		{`{\an8}Hi`, []string{`{\`, "an", "8", "}Hi"}},
+		{"end   ", []string{"end", "   "}},
+		{"今日は", []string{"今日は"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitCL100K(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCL100K(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSplitO200K(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Hello world", []string{"Hello", " world"}},
+		{"I'm here", []string{"I'm", " here"}},
+		{"camelCase", []string{"camel", "Case"}},
+		{"HTTPServer", []string{"HTTPServer"}},
+		{"a/b//\nc", []string{"a", "/b", "//\n", "c"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitO200K(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitO200K(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
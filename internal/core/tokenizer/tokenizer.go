@@ -1,92 +1,130 @@
-// Package tokenizer provides BPE token estimation for AI cost calculations.
-// This is a simplified estimator based on common BPE tokenization patterns.
+// Package tokenizer provides BPE token counting for AI cost calculations
+// and token-budgeted batching. Counts use tiktoken-compatible vocabularies
+// (cl100k_base, o200k_base) selected per model, with an approximation over
+// the same pre-tokenizer when a vocabulary is not available.
 package tokenizer
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 	"unicode"
+
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
 )
 
-// Estimator provides token count estimation for various AI models
+// Estimator provides token counts for various AI models
 type Estimator struct {
-	// Average characters per token varies by language
-	charsPerToken float64
+	// BPE vocabulary; nil falls back to approximate counts
+	encoding *Encoding
+	// Why the vocabulary could not be loaded
+	encodingErr error
+	// Pre-tokenizer used by the approximation
+	split func(string) []string
+	// Live pricing source; nil uses the static ModelPricing table only
+	catalog *catalog.Catalog
+	// Provider whose catalog entry is preferred for pricing
+	provider string
 }
 
-// NewEstimator creates a new token estimator
+// NewEstimator creates a token estimator using the cl100k_base encoding
 func NewEstimator() *Estimator {
+	enc, err := GetEncoding(EncodingCL100K)
 	return &Estimator{
-		// Average for English text (GPT models)
-		// Most languages average 3-4 chars per token
-		charsPerToken: 4.0,
+		encoding:    enc,
+		encodingErr: err,
+		split:       splitCL100K,
+	}
+}
+
+// ForModel returns a copy of the estimator that tokenizes with the model's encoding
+func (e *Estimator) ForModel(model string) *Estimator {
+	name := EncodingForModel(model)
+	c := *e
+	c.encoding, c.encodingErr = GetEncoding(name)
+	c.split = splitterFor(name)
+	return &c
+}
+
+// WithCatalog makes the estimator price models from the given catalog,
+// preferring the provider's entry ("" = any provider) and falling back to
+// ModelPricing for models the catalog has no pricing for
+func (e *Estimator) WithCatalog(c *catalog.Catalog, provider string) *Estimator {
+	e.catalog = c
+	e.provider = provider
+	return e
+}
+
+// Exact reports whether counts come from a BPE vocabulary rather than an approximation
+func (e *Estimator) Exact() bool {
+	return e.encoding != nil
+}
+
+// Err returns why counts are approximate (nil when Exact)
+func (e *Estimator) Err() error {
+	if e.encoding != nil {
+		return nil
+	}
+	if e.encodingErr == nil {
+		return ErrEncodingUnavailable
 	}
+	return e.encodingErr
 }
 
-// EstimateTokens returns an estimated token count for the given text
+// EstimateTokens returns the token count of text
 func (e *Estimator) EstimateTokens(text string) int {
 	if text == "" {
 		return 0
 	}
 
-	// Count using multiple heuristics and average them
-	estimates := []int{
-		e.estimateByChars(text),
-		e.estimateByWords(text),
-		e.estimateByRunes(text),
+	if e.encoding != nil {
+		return e.encoding.Count(text)
 	}
 
 	total := 0
-	for _, est := range estimates {
-		total += est
+	for _, piece := range e.split(text) {
+		total += approximatePiece(piece)
 	}
-
-	return total / len(estimates)
-}
-
-// estimateByChars uses character count divided by average chars per token
-func (e *Estimator) estimateByChars(text string) int {
-	return int(float64(len(text)) / e.charsPerToken)
-}
-
-// estimateByWords uses word count as a rough approximation
-// On average, 1 word ≈ 1.3-1.5 tokens for English
-func (e *Estimator) estimateByWords(text string) int {
-	words := strings.Fields(text)
-	return int(float64(len(words)) * 1.4)
+	return total
 }
 
-// estimateByRunes handles unicode better for non-ASCII languages
-func (e *Estimator) estimateByRunes(text string) int {
-	runes := []rune(text)
-
-	// Count token-like segments
-	tokenCount := 0
-	inWord := false
-
-	for _, r := range runes {
-		if unicode.IsLetter(r) || unicode.IsNumber(r) {
-			if !inWord {
-				inWord = true
-				tokenCount++
-			}
-		} else {
-			inWord = false
-			// Punctuation and special characters often become separate tokens
+// approximatePiece estimates the tokens of one pre-tokenized piece.
+// Common English words are a single token; long words split every ~7 letters.
+// CJK characters are roughly one token each, other non-ASCII letters about
+// one token per two characters (their UTF-8 bytes rarely merge far).
+func approximatePiece(piece string) int {
+	ascii, cjk, other := 0, 0, 0
+	for _, r := range piece {
+		switch {
+		case r < 0x80:
 			if !unicode.IsSpace(r) {
-				tokenCount++
+				ascii++
 			}
+		case isCJK(r):
+			cjk++
+		default:
+			other++
+		}
+	}
+
+	tokens := cjk + (other+1)/2
+	if ascii > 0 {
+		if first := []rune(strings.TrimSpace(piece)); len(first) > 0 && (unicode.IsLetter(first[0]) || unicode.IsNumber(first[0])) {
+			tokens += (ascii + 6) / 7
+		} else {
+			// Punctuation runs (ASS tags, ellipses) merge less
+			tokens += (ascii + 1) / 2
 		}
 	}
 
-	// Add tokens for special patterns
-	// ASS tags are typically 1-2 tokens each
-	assTagPattern := regexp.MustCompile(`\{[^}]*\}`)
-	assTags := assTagPattern.FindAllString(text, -1)
-	tokenCount += len(assTags)
+	if tokens == 0 {
+		return 1
+	}
+	return tokens
+}
 
-	return tokenCount
+// isCJK reports whether r is a Han, kana or Hangul character
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
 }
 
 // EstimateBatch estimates tokens for multiple text lines
@@ -113,7 +151,8 @@ type Pricing struct {
 	OutputPer1M float64 // USD per 1M output tokens
 }
 
-// Common model pricing (as of 2024)
+// Common model pricing (as of 2024).
+// Used as a fallback when the model catalog has no pricing for a model.
 var ModelPricing = map[string]Pricing{
 	// OpenAI
 	"gpt-4o":        {InputPer1M: 2.50, OutputPer1M: 10.00},
@@ -143,7 +182,7 @@ var ModelPricing = map[string]Pricing{
 
 // EstimateCost calculates the estimated cost for translating the given text
 func (e *Estimator) EstimateCost(lines []string, model string) CostEstimate {
-	inputTokens := e.EstimateBatch(lines)
+	inputTokens := e.ForModel(model).EstimateBatch(lines)
 
 	// Output tokens are typically ~80% of input for translation
 	// (translated text is usually shorter or similar length)
@@ -152,10 +191,7 @@ func (e *Estimator) EstimateCost(lines []string, model string) CostEstimate {
 	// Add system prompt overhead (~500 tokens typically)
 	inputTokens += 500
 
-	pricing, ok := ModelPricing[normalizeModelName(model)]
-	if !ok {
-		pricing = ModelPricing["default"]
-	}
+	pricing := e.PricingFor(model)
 
 	costUSD := (float64(inputTokens) * pricing.InputPer1M / 1000000) +
 		(float64(outputTokens) * pricing.OutputPer1M / 1000000)
@@ -169,6 +205,29 @@ func (e *Estimator) EstimateCost(lines []string, model string) CostEstimate {
 	}
 }
 
+// CalculateCost returns the USD cost of actual token usage for the given model
+func (e *Estimator) CalculateCost(inputTokens, outputTokens int, model string) float64 {
+	pricing := e.PricingFor(model)
+
+	return (float64(inputTokens) * pricing.InputPer1M / 1000000) +
+		(float64(outputTokens) * pricing.OutputPer1M / 1000000)
+}
+
+// PricingFor returns the pricing of a model, preferring the live catalog
+func (e *Estimator) PricingFor(model string) Pricing {
+	if e.catalog != nil {
+		if info, ok := e.catalog.Lookup(e.provider, model); ok && info.PricingKnown {
+			return Pricing{InputPer1M: info.InputPer1M, OutputPer1M: info.OutputPer1M}
+		}
+	}
+
+	pricing, ok := ModelPricing[normalizeModelName(model)]
+	if !ok {
+		pricing = ModelPricing["default"]
+	}
+	return pricing
+}
+
 // normalizeModelName extracts the base model name for pricing lookup
 func normalizeModelName(model string) string {
 	model = strings.ToLower(model)
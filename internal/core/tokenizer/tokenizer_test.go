@@ -2,6 +2,8 @@ package tokenizer
 
 import (
 	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
 )
 
 func TestNewEstimator(t *testing.T) {
@@ -11,8 +13,8 @@ func TestNewEstimator(t *testing.T) {
 		t.Fatal("NewEstimator returned nil")
 	}
 
-	if estimator.charsPerToken != 4.0 {
-		t.Errorf("expected charsPerToken 4.0, got %f", estimator.charsPerToken)
+	if estimator.split == nil {
+		t.Error("expected a pre-tokenizer to be set")
 	}
 }
 
@@ -41,39 +43,34 @@ func TestEstimateTokens(t *testing.T) {
 	}
 }
 
-func TestEstimateByChars(t *testing.T) {
-	estimator := NewEstimator()
-
-	// 16 chars / 4 chars per token = 4 tokens
-	text := "1234567890123456"
-	tokens := estimator.estimateByChars(text)
+func TestApproximateCJK(t *testing.T) {
+	// Force the approximation regardless of bundled vocabularies
+	estimator := &Estimator{split: splitCL100K}
 
-	if tokens != 4 {
-		t.Errorf("expected 4 tokens, got %d", tokens)
+	// Each kanji/kana is roughly one token; the old char/word heuristic
+	// counted this whole line as ~3 tokens
+	tokens := estimator.EstimateTokens("今日はいい天気ですね")
+	if tokens < 8 || tokens > 12 {
+		t.Errorf("expected ~10 tokens for 10 CJK characters, got %d", tokens)
 	}
-}
-
-func TestEstimateByWords(t *testing.T) {
-	estimator := NewEstimator()
 
-	text := "one two three four five"
-	tokens := estimator.estimateByWords(text)
-
-	// 5 words * 1.4 = 7
-	if tokens != 7 {
-		t.Errorf("expected 7 tokens, got %d", tokens)
+	// ASS override tags are split into several tokens
+	tagged := estimator.EstimateTokens(`{\an8\pos(320,50)}Hello`)
+	plain := estimator.EstimateTokens("Hello")
+	if tagged <= plain+3 {
+		t.Errorf("expected ASS tags to add several tokens, got %d vs %d", tagged, plain)
 	}
 }
 
-func TestEstimateByRunes(t *testing.T) {
+func TestForModel(t *testing.T) {
 	estimator := NewEstimator()
 
-	text := "Hello World!"
-	tokens := estimator.estimateByRunes(text)
-
-	// Should count word segments and punctuation
-	if tokens <= 0 {
-		t.Error("should produce positive token count")
+	o200k := estimator.ForModel("openai/gpt-4o-mini")
+	if o200k.split == nil || o200k == estimator {
+		t.Error("ForModel should return a configured copy")
+	}
+	if enc, _ := GetEncoding(EncodingO200K); o200k.encoding != enc {
+		t.Error("expected o200k_base encoding for gpt-4o-mini")
 	}
 }
 
@@ -317,3 +314,37 @@ func TestOutputTokenRatio(t *testing.T) {
 		t.Errorf("output tokens %d not close to expected %d", estimate.OutputTokens, expectedOutput)
 	}
 }
+
+func TestCalculateCost(t *testing.T) {
+	estimator := NewEstimator()
+
+	// gpt-4o-mini: $0.15/1M input, $0.60/1M output
+	cost := estimator.CalculateCost(1000000, 1000000, "openai/gpt-4o-mini")
+	if cost < 0.749 || cost > 0.751 {
+		t.Errorf("expected cost 0.75, got %f", cost)
+	}
+
+	if cost := estimator.CalculateCost(5000, 5000, "meta-llama/llama-3.3-70b-instruct:free"); cost != 0 {
+		t.Errorf("free model should cost 0, got %f", cost)
+	}
+}
+
+func TestPricingForPrefersCatalog(t *testing.T) {
+	c := catalog.New("")
+	c.Update("openrouter", []catalog.ModelInfo{
+		{ID: "openai/gpt-4o", InputPer1M: 5, OutputPer1M: 20, PricingKnown: true},
+		{ID: "gemini-2.0-flash"}, // No pricing from provider
+	})
+
+	e := NewEstimator().WithCatalog(c, "")
+
+	if got := e.PricingFor("openai/gpt-4o"); got.InputPer1M != 5 || got.OutputPer1M != 20 {
+		t.Errorf("Expected catalog pricing, got %+v", got)
+	}
+	if got := e.PricingFor("gemini-2.0-flash"); got != ModelPricing["gemini-2.0-flash"] {
+		t.Errorf("Expected static fallback for unpriced model, got %+v", got)
+	}
+	if got := e.PricingFor("unknown-model"); got != ModelPricing["default"] {
+		t.Errorf("Expected default pricing, got %+v", got)
+	}
+}
@@ -0,0 +1,125 @@
+// Package paths resolves where BakaSub keeps its configuration, data and caches.
+//
+// On Linux and macOS the XDG base directories are used (~/.config/bakasub,
+// ~/.local/share/bakasub and ~/.cache/bakasub unless XDG_*_HOME says otherwise).
+// On Windows both config and data live in %AppData%\bakasub and caches in
+// %LocalAppData%\bakasub. Setting BAKASUB_HOME keeps everything in that one
+// directory instead, which is useful for portable installs.
+package paths
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	appName = "bakasub"
+
+	// HomeEnv overrides every BakaSub directory
+	HomeEnv = "BAKASUB_HOME"
+)
+
+// ConfigDir returns the directory holding config.json
+func ConfigDir() string {
+	if home := os.Getenv(HomeEnv); home != "" {
+		return home
+	}
+	return baseDir("XDG_CONFIG_HOME", ".config", os.UserConfigDir)
+}
+
+// DataDir returns the directory holding the translation memory
+func DataDir() string {
+	if home := os.Getenv(HomeEnv); home != "" {
+		return home
+	}
+	return baseDir("XDG_DATA_HOME", filepath.Join(".local", "share"), os.UserConfigDir)
+}
+
+// CacheDir returns the directory holding disposable caches (model catalog, tokenizers)
+func CacheDir() string {
+	if home := os.Getenv(HomeEnv); home != "" {
+		return filepath.Join(home, "cache")
+	}
+	return baseDir("XDG_CACHE_HOME", ".cache", os.UserCacheDir)
+}
+
+// baseDir resolves an XDG base directory, falling back to the platform
+// directory on Windows and to the temp directory when no home is known
+func baseDir(env, homeRel string, platform func() (string, error)) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appName)
+	}
+	if runtime.GOOS == "windows" {
+		if dir, err := platform(); err == nil {
+			return filepath.Join(dir, appName)
+		}
+	} else if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, homeRel, appName)
+	}
+	return filepath.Join(os.TempDir(), appName)
+}
+
+// Migrate moves a file left by older versions in the working directory to dest.
+// Nothing happens when the legacy file is missing, is dest itself, or dest
+// already exists. It reports whether the file was moved.
+func Migrate(legacy, dest string) (bool, error) {
+	legacyAbs, err := filepath.Abs(legacy)
+	if err != nil {
+		return false, err
+	}
+	destAbs, err := filepath.Abs(dest)
+	if err != nil {
+		return false, err
+	}
+	if legacyAbs == destAbs {
+		return false, nil
+	}
+
+	info, err := os.Stat(legacyAbs)
+	if err != nil || info.IsDir() {
+		return false, nil
+	}
+	if _, err := os.Stat(destAbs); err == nil {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destAbs), 0755); err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", filepath.Dir(destAbs), err)
+	}
+	if err := move(legacyAbs, destAbs); err != nil {
+		return false, fmt.Errorf("failed to move %s to %s: %w", legacy, dest, err)
+	}
+	return true, nil
+}
+
+// move renames src to dst, copying across file systems when a rename is impossible
+func move(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
@@ -0,0 +1,84 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestHomeOverride tests that BAKASUB_HOME holds every directory
+func TestHomeOverride(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(HomeEnv, home)
+
+	if got := ConfigDir(); got != home {
+		t.Errorf("ConfigDir() = %q, want %q", got, home)
+	}
+	if got := DataDir(); got != home {
+		t.Errorf("DataDir() = %q, want %q", got, home)
+	}
+	if got, want := CacheDir(), filepath.Join(home, "cache"); got != want {
+		t.Errorf("CacheDir() = %q, want %q", got, want)
+	}
+}
+
+// TestXDGDirs tests that the XDG variables are honored
+func TestXDGDirs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("XDG variables only apply to Unix-like systems")
+	}
+	base := t.TempDir()
+	t.Setenv(HomeEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(base, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(base, "data"))
+	t.Setenv("XDG_CACHE_HOME", "relative/ignored")
+	t.Setenv("HOME", base)
+
+	if got, want := ConfigDir(), filepath.Join(base, "config", "bakasub"); got != want {
+		t.Errorf("ConfigDir() = %q, want %q", got, want)
+	}
+	if got, want := DataDir(), filepath.Join(base, "data", "bakasub"); got != want {
+		t.Errorf("DataDir() = %q, want %q", got, want)
+	}
+	// Relative XDG paths are invalid per the spec and fall back to the default
+	if got, want := CacheDir(), filepath.Join(base, ".cache", "bakasub"); got != want {
+		t.Errorf("CacheDir() = %q, want %q", got, want)
+	}
+}
+
+// TestMigrate tests moving a legacy file and leaving existing files alone
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "config.json")
+	dest := filepath.Join(dir, "home", "config.json")
+
+	if moved, err := Migrate(legacy, dest); err != nil || moved {
+		t.Fatalf("Migrate() without a legacy file = %v, %v; want nothing moved", moved, err)
+	}
+
+	if err := os.WriteFile(legacy, []byte(`{"a":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := Migrate(legacy, dest)
+	if err != nil || !moved {
+		t.Fatalf("Migrate() = %v, %v; want moved", moved, err)
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != `{"a":1}` {
+		t.Errorf("dest = %q (%v), want the legacy content", data, err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Error("legacy file should be gone")
+	}
+
+	// An existing destination always wins
+	if err := os.WriteFile(legacy, []byte(`{"a":2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if moved, err := Migrate(legacy, dest); err != nil || moved {
+		t.Errorf("Migrate() over an existing file = %v, %v; want nothing moved", moved, err)
+	}
+	if moved, err := Migrate(dest, dest); err != nil || moved {
+		t.Errorf("Migrate() onto itself = %v, %v; want nothing moved", moved, err)
+	}
+}
@@ -0,0 +1,75 @@
+package modelselect
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
+	"github.com/lsilvatti/bakasub/internal/core/tokenizer"
+)
+
+// FromCatalog converts catalog entries into selector rows.
+// Models without provider pricing show the estimator's fallback price.
+func FromCatalog(infos []catalog.ModelInfo) []ModelInfo {
+	estimator := tokenizer.NewEstimator()
+	models := make([]ModelInfo, 0, len(infos))
+
+	for _, info := range infos {
+		name := info.Name
+		if name == "" {
+			name = info.ID
+		}
+
+		inputPrice := info.InputPer1M
+		if !info.PricingKnown {
+			inputPrice = estimator.PricingFor(info.ID).InputPer1M
+		}
+
+		isFree := info.IsFree()
+		price := fmt.Sprintf("$%.2f", inputPrice)
+		if isFree || inputPrice < 0.01 {
+			price = "FREE"
+			isFree = true
+		}
+
+		models = append(models, ModelInfo{
+			ID:          info.ID,
+			Name:        name,
+			ContextSize: formatContextSize(info.ContextLength),
+			PricePerM:   price,
+			IsFree:      isFree,
+		})
+	}
+
+	return models
+}
+
+// LoadFromCatalog refreshes the shared catalog for a provider when its cached
+// list is stale and returns the provider's models. If the provider cannot be
+// reached, the last cached list is used; the error is only returned when
+// there is nothing cached.
+func LoadFromCatalog(ctx context.Context, provider string, fetcher catalog.Fetcher) ([]ModelInfo, error) {
+	c := catalog.Shared()
+
+	err := c.Refresh(ctx, provider, fetcher)
+	cached := c.Models(provider)
+	if err != nil && len(cached) == 0 {
+		return nil, err
+	}
+
+	return FromCatalog(cached), nil
+}
+
+// formatContextSize renders a token count as "8k", "128k" or "1M"
+func formatContextSize(tokens int) string {
+	switch {
+	case tokens <= 0:
+		return "varies"
+	case tokens >= 1000000:
+		return fmt.Sprintf("%dM", tokens/1000000)
+	case tokens >= 1000:
+		return fmt.Sprintf("%dk", tokens/1000)
+	default:
+		return fmt.Sprintf("%d", tokens)
+	}
+}
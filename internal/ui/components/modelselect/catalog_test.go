@@ -0,0 +1,32 @@
+package modelselect
+
+import (
+	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
+)
+
+func TestFromCatalog(t *testing.T) {
+	models := FromCatalog([]catalog.ModelInfo{
+		{ID: "openai/gpt-4o", Name: "GPT-4o", ContextLength: 128000, InputPer1M: 2.5, PricingKnown: true},
+		{ID: "meta-llama/llama-3.3-70b-instruct:free", ContextLength: 1048576, PricingKnown: true},
+		{ID: "gpt-4o-mini"}, // No pricing from provider
+	})
+
+	if len(models) != 3 {
+		t.Fatalf("Expected 3 models, got %d", len(models))
+	}
+
+	if models[0].PricePerM != "$2.50" || models[0].ContextSize != "128k" || models[0].IsFree {
+		t.Errorf("Unexpected paid model: %+v", models[0])
+	}
+	if models[1].PricePerM != "FREE" || models[1].ContextSize != "1M" || !models[1].IsFree {
+		t.Errorf("Unexpected free model: %+v", models[1])
+	}
+	if models[1].Name != models[1].ID {
+		t.Errorf("Expected ID as name fallback, got %q", models[1].Name)
+	}
+	if models[2].PricePerM != "$0.15" || models[2].ContextSize != "varies" {
+		t.Errorf("Expected static fallback pricing, got %+v", models[2])
+	}
+}
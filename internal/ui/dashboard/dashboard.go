@@ -23,6 +23,7 @@ import (
 	"github.com/lsilvatti/bakasub/internal/ui/remuxer"
 	"github.com/lsilvatti/bakasub/internal/ui/review"
 	"github.com/lsilvatti/bakasub/internal/ui/settings"
+	"github.com/lsilvatti/bakasub/internal/ui/stats"
 	"github.com/lsilvatti/bakasub/internal/ui/styles"
 	"github.com/lsilvatti/bakasub/pkg/utils"
 )
@@ -51,6 +52,8 @@ const (
 	ViewGlossary
 	// ViewReview shows the manual review editor
 	ViewReview
+	// ViewStats shows the translation memory statistics
+	ViewStats
 )
 
 // DirectoryAnalysis holds results of scanning a directory
@@ -95,6 +98,7 @@ type Model struct {
 	remuxerModel     *remuxer.Model
 	glossaryModel    *glossary.Model
 	reviewModel      *review.Model
+	statsModel       *stats.Model
 
 	// Module error (for displaying errors when opening modules)
 	moduleError error
@@ -263,6 +267,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateGlossary(msg)
 	case ViewReview:
 		return m.updateReview(msg)
+	case ViewStats:
+		return m.updateStats(msg)
 	}
 
 	switch msg := msg.(type) {
@@ -439,6 +445,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.selectedPath != "" {
 				glossaryPath := filepath.Join(filepath.Dir(m.findFirstMKV()), "glossary.json")
 				m.glossaryModel = glossary.New(glossaryPath)
+				m.glossaryModel.SetLanguages("", m.config.TargetLang)
 				m.glossaryModel.SetSize(m.width, m.height)
 				m.viewState = ViewGlossary
 				return m, m.glossaryModel.Init()
@@ -452,6 +459,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewState = ViewSettings
 			return m, m.settingsModel.Init()
 
+		case "s":
+			// Translation memory statistics
+			m.statsModel = stats.New("")
+			m.statsModel.SetSize(m.width, m.height)
+			m.viewState = ViewStats
+			return m, m.statsModel.Init()
+
 		case "k":
 			// Open Ko-fi link in browser
 			kofiURL := "https://ko-fi.com/lsilvatti"
@@ -635,16 +649,20 @@ func (m Model) updateJob(msg tea.Msg) (tea.Model, tea.Cmd) {
 			SourceLang:      msg.JobConfig.SourceLang,
 			TargetLang:      msg.JobConfig.TargetLang,
 			MediaType:       msg.JobConfig.MediaType,
+			ProjectID:       msg.JobConfig.ProjectID,
 			AIModel:         msg.JobConfig.AIModel,
 			Temperature:     msg.JobConfig.Temperature,
 			GlossaryPath:    msg.JobConfig.GlossaryPath,
 			GlossaryTerms:   msg.JobConfig.GlossaryTerms,
+			GlossaryPrePass: msg.JobConfig.GlossaryPrePass,
 			RemoveHITags:    msg.JobConfig.RemoveHITags,
 			MuxMode:         msg.JobConfig.MuxMode,
 			SetDefault:      msg.JobConfig.SetDefault,
 			BackupOriginal:  msg.JobConfig.BackupOriginal,
+			LintReports:     msg.JobConfig.LintReports,
 			ExtractFonts:    msg.JobConfig.ExtractFonts,
 			AutoDetectTrack: msg.JobConfig.AutoDetectTrack,
+			Unattended:      m.watchModeActive || m.config.TouchlessMode,
 		}
 
 		// Convert analyzed files
@@ -853,6 +871,31 @@ func (m Model) updateReview(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateStats handles updates when in cache statistics view
+func (m Model) updateStats(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.statsModel == nil {
+		m.viewState = ViewDashboard
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case stats.ClosedMsg:
+		m.viewState = ViewDashboard
+		m.statsModel = nil
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	statsModel, cmd := m.statsModel.Update(msg)
+	if sm, ok := statsModel.(*stats.Model); ok {
+		m.statsModel = sm
+	}
+	return m, cmd
+}
+
 // findFirstMKV returns the path to the first MKV file in the selected path
 func (m Model) findFirstMKV() string {
 	if m.selectedPath == "" {
@@ -1024,6 +1067,10 @@ func (m Model) View() string {
 		if m.reviewModel != nil {
 			return m.reviewModel.View()
 		}
+	case ViewStats:
+		if m.statsModel != nil {
+			return m.statsModel.View()
+		}
 	}
 
 	// Calculate content width (full width minus border/padding)
@@ -1233,7 +1280,8 @@ func (m Model) renderSystemAI(contentWidth int) string {
 	// Settings info
 	settingsLine := locales.T("dashboard.system.target") + " " + styles.Highlight.Render(m.targetLang) +
 		"  │  " + locales.T("dashboard.system.temp") + " " + styles.Highlight.Render(m.temperature) +
-		"             " + styles.RenderHotkey("c", locales.T("dashboard.system.configuration"))
+		"             " + styles.RenderHotkey("c", locales.T("dashboard.system.configuration")) +
+		"  " + styles.RenderHotkey("s", locales.T("dashboard.system.cache_stats"))
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -14,11 +14,18 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lsilvatti/bakasub/internal/config"
 	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/characters"
 	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/lintreport"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
 	"github.com/lsilvatti/bakasub/internal/core/pipeline"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
 	"github.com/lsilvatti/bakasub/internal/locales"
 	"github.com/lsilvatti/bakasub/internal/ui/components/tape"
+	"github.com/lsilvatti/bakasub/internal/ui/glossary"
 	"github.com/lsilvatti/bakasub/internal/ui/layout"
+	"github.com/lsilvatti/bakasub/internal/ui/review"
 	"github.com/lsilvatti/bakasub/internal/ui/styles"
 )
 
@@ -233,16 +240,20 @@ type JobConfig struct {
 	SourceLang      string
 	TargetLang      string
 	MediaType       string
+	ProjectID       string // Series/project the files belong to (scopes the translation memory)
 	AIModel         string
 	Temperature     float64
 	GlossaryPath    string
 	GlossaryTerms   map[string]string
+	GlossaryPrePass bool // Ask the model for glossary terms and review them before translating
 	RemoveHITags    bool
 	MuxMode         string
 	SetDefault      bool
 	BackupOriginal  bool
 	ExtractFonts    bool
 	AutoDetectTrack bool
+	LintReports     []string // Lint report formats written next to each output file
+	Unattended      bool     // Watch/touchless mode: abort instead of asking when a budget limit is hit, skip the quality gate prompt
 }
 
 // AnalyzedFile represents a file to process
@@ -275,15 +286,31 @@ type Model struct {
 	elapsedTime   time.Duration
 
 	// Statistics
+	jobID          string
 	linesProcessed int
 	tokensUsed     int
 	costSoFar      float64
+	usage          ai.Usage            // Provider-reported usage aggregated over the job
+	dedup          pipeline.DedupStats // Repeated lines translated once, aggregated over the job
 	errors         int
 
+	// Budget prompt
+	showBudgetPrompt bool
+	budgetStatus     pipeline.BudgetStatus
+	budgetReply      chan bool
+
 	// Quality Gate
 	qualityIssues   []QualityIssue
 	qualityAction   int // 0=auto-fix, 1=manual review, 2=ignore
 	showQualityGate bool
+	qualityReport   pipeline.QualityReport
+	qualityReply    chan pipeline.QualityDecision
+	qualityCache    *db.Cache
+	reviewer        *review.Model // Review editor opened from the quality gate
+
+	// Glossary pre-pass review
+	glossaryEditor *glossary.Model
+	glossaryReply  chan struct{}
 
 	// Logging
 	logBuffer    *LogBuffer
@@ -331,6 +358,7 @@ func New(cfg *config.Config, jobConfig JobConfig) Model {
 		ctx:          ctx,
 		cancel:       cancel,
 		jobName:      jobName,
+		jobID:        time.Now().Format("20060102-150405"),
 		totalFiles:   totalFiles,
 		fileIndex:    1,
 		status:       StatusRunning,
@@ -377,6 +405,7 @@ func (m Model) startExecution() tea.Cmd {
 func (m *Model) executePipeline() tea.Cmd {
 	cfg := m.cfg
 	jobConfig := m.jobConfig
+	jobID := m.jobID
 	ctx := m.ctx
 	msgChan := m.msgChan
 
@@ -408,20 +437,30 @@ func (m *Model) executePipeline() tea.Cmd {
 				return
 			}
 
-			// Open cache database (optional)
-			cache, cacheErr := db.Open("")
+			// Shared cache database (optional)
+			cache, cacheErr := db.GetInstance("")
 			if cacheErr != nil {
 				msgChan <- LogMsg{Level: LogWarn, Message: "Cache unavailable, continuing without cache"}
 			} else {
 				msgChan <- LogMsg{Level: LogInfo, Message: "Translation cache connected"}
 			}
-			defer func() {
-				if cache != nil {
-					cache.Close()
-				}
-			}()
+
+			// Quality gate rules of the job's profile
+			lintRules, lintErr := pipeline.LintRules(cfg.Lint, jobConfig.MediaType)
+			if lintErr != nil {
+				msgChan <- LogMsg{Level: LogWarn, Message: fmt.Sprintf("Ignoring invalid lint settings: %v", lintErr)}
+			}
+			lintReports, reportErr := lintreport.ParseFormats(jobConfig.LintReports)
+			if reportErr != nil {
+				msgChan <- LogMsg{Level: LogWarn, Message: fmt.Sprintf("Skipping lint reports: %v", reportErr)}
+			}
 
 			totalFiles := len(files)
+			var jobUsage ai.Usage
+			var jobDedup pipeline.DedupStats
+
+			// Spending limits are shared by all files of the job
+			budget := pipeline.NewBudgetGuard(cfg.Budget, cache)
 
 			// Process each file
 			for i, file := range files {
@@ -448,24 +487,58 @@ func (m *Model) executePipeline() tea.Cmd {
 					outputPath = base + "_translated" + ext
 				}
 
+				// The line count only drives the fixed strategy; budgeted
+				// strategies size batches from the model's context window
+				batchSize := 0
+				if cfg.Batching.Strategy == parser.BatchStrategyFixed {
+					batchSize = cfg.Batching.BatchSize
+				}
+
+				// Without a configured source language the linter detects it
+				sourceLang := jobConfig.SourceLang
+				if sourceLang == "" {
+					sourceLang = linter.AutoLang
+				}
+
 				// Create pipeline config for this file
 				pipelineCfg := &pipeline.PipelineConfig{
-					InputPath:      file.Path,
-					OutputPath:     outputPath,
-					SourceLang:     "auto",
-					TargetLang:     jobConfig.TargetLang,
-					Model:          jobConfig.AIModel,
-					Temperature:    jobConfig.Temperature,
-					BatchSize:      50,
-					RemoveHI:       jobConfig.RemoveHITags,
-					Glossary:       jobConfig.GlossaryTerms,
-					TrackID:        file.SelectedTrackID,
-					MuxMode:        jobConfig.MuxMode,
-					BackupOriginal: jobConfig.BackupOriginal,
+					InputPath:        file.Path,
+					OutputPath:       outputPath,
+					SourceLang:       sourceLang,
+					TargetLang:       jobConfig.TargetLang,
+					Model:            jobConfig.AIModel,
+					Temperature:      jobConfig.Temperature,
+					BatchSize:        batchSize,
+					BatchStrategy:    cfg.Batching.Strategy,
+					MaxBatchTokens:   cfg.Batching.MaxTokens,
+					SceneGap:         time.Duration(cfg.Batching.SceneGap * float64(time.Second)),
+					RemoveHI:         jobConfig.RemoveHITags,
+					Glossary:         jobConfig.GlossaryTerms,
+					GlossaryPath:     termbase.PathFor(filepath.Dir(file.Path)),
+					CharactersPath:   characters.PathFor(filepath.Dir(file.Path)),
+					GlossaryPrePass:  jobConfig.GlossaryPrePass,
+					TermPlaceholders: cfg.Glossary.Enforcement == "placeholders",
+					TrackID:          file.SelectedTrackID,
+					MuxMode:          jobConfig.MuxMode,
+					BackupOriginal:   jobConfig.BackupOriginal,
+					JobID:            jobID,
+					Profile:          jobConfig.MediaType,
+					ProjectID:        jobConfig.ProjectID,
+					CachePolicy:      db.ParseLookupPolicy(cfg.Cache.LookupPolicy),
+					FuzzyThreshold:   cfg.Cache.FuzzyThreshold,
+					ProviderName:     cfg.AIProvider,
+					LintRules:        lintRules,
+					LintReports:      lintReports,
 				}
 
 				p := pipeline.New(provider, cache, pipelineCfg)
 
+				// Continue from batches saved by an interrupted run of this file
+				if state := pipeline.ResumeStateFor(file.Path); state != nil {
+					p.ResumeState = state
+					msgChan <- LogMsg{Level: LogInfo, Message: fmt.Sprintf("Resuming %s from batch %d/%d", filepath.Base(file.Path), state.CompletedBatches, state.TotalBatches)}
+				}
+
 				// Set up logging callback to send logs to UI
 				p.LogCallback = func(logMsg string) {
 					level := LogInfo
@@ -497,6 +570,67 @@ func (m *Model) executePipeline() tea.Cmd {
 					}
 				}
 
+				// Set up usage callback (aggregated per job)
+				p.UsageCallback = func(batch ai.Usage) {
+					jobUsage.Add(batch)
+					select {
+					case msgChan <- UsageMsg{Batch: batch, File: p.Usage, Job: jobUsage}:
+					default:
+						// Channel full, skip message (the next one carries the totals)
+					}
+				}
+
+				// Set up dedup callback (aggregated per job)
+				p.DedupCallback = func(stats pipeline.DedupStats) {
+					jobDedup.Add(stats)
+					select {
+					case msgChan <- DedupMsg{File: stats, Job: jobDedup}:
+					default:
+						// Channel full, skip message
+					}
+				}
+
+				// Set up budget enforcement
+				p.Budget = budget
+				if !jobConfig.Unattended {
+					p.BudgetCallback = func(status pipeline.BudgetStatus) bool {
+						reply := make(chan bool, 1)
+						msgChan <- BudgetExceededMsg{Status: status, Reply: reply}
+						select {
+						case approved := <-reply:
+							return approved
+						case <-ctx.Done():
+							return false
+						}
+					}
+				}
+
+				// Review the glossary terms proposed by the pre-pass
+				if !jobConfig.Unattended {
+					p.GlossaryCallback = func(path string) {
+						reply := make(chan struct{}, 1)
+						msgChan <- GlossaryReviewMsg{Path: path, Reply: reply}
+						select {
+						case <-reply:
+						case <-ctx.Done():
+						}
+					}
+				}
+
+				// Ask what to do with the issues left before muxing
+				if !jobConfig.Unattended {
+					p.QualityCallback = func(report pipeline.QualityReport) pipeline.QualityDecision {
+						reply := make(chan pipeline.QualityDecision, 1)
+						msgChan <- QualityGateMsg{Issues: qualityIssues(report.Issues), Report: report, Reply: reply, Cache: cache}
+						select {
+						case decision := <-reply:
+							return decision
+						case <-ctx.Done():
+							return pipeline.QualityDecision{Action: pipeline.QualityIgnore}
+						}
+					}
+				}
+
 				// Execute pipeline for this file
 				if err := p.Execute(ctx); err != nil {
 					msgChan <- pipelineErrorMsg{err: err, fileIndex: i}
@@ -512,6 +646,15 @@ func (m *Model) executePipeline() tea.Cmd {
 				}
 			}
 
+			// Keep the translation memory within its configured limits
+			if report, err := pipeline.EnforceCacheLimits(cache, cfg.Cache); err != nil {
+				msgChan <- LogMsg{Level: LogWarn, Message: fmt.Sprintf("Warning: Cache eviction failed: %v", err)}
+			} else if report != nil && report.EvictedCount > 0 {
+				for _, line := range evictionLog(report) {
+					msgChan <- LogMsg{Level: LogInfo, Message: line}
+				}
+			}
+
 			msgChan <- pipelineCompleteMsg{}
 		}()
 
@@ -520,6 +663,20 @@ func (m *Model) executePipeline() tea.Cmd {
 	}
 }
 
+// evictionLog formats an eviction report for the job log, listing the evicted entries
+func evictionLog(report *db.EvictionReport) []string {
+	lines := []string{"Cache limits: " + report.String()}
+	for _, e := range report.Evicted {
+		lines = append(lines, fmt.Sprintf("  - %q -> %q (%s, used %dx, last %s)",
+			layout.TruncateToWidth(e.OriginalText, 40), layout.TruncateToWidth(e.TranslatedText, 40),
+			e.LangPair, e.UseCount, e.LastUsed.Local().Format("2006-01-02")))
+	}
+	if more := report.EvictedCount - len(report.Evicted); more > 0 {
+		lines = append(lines, fmt.Sprintf("  ... and %d more", more))
+	}
+	return lines
+}
+
 // pipelineErrorMsg is sent when pipeline encounters an error
 type pipelineErrorMsg struct {
 	err       error
@@ -553,6 +710,25 @@ type StatsMsg struct {
 	Errors         int
 }
 
+// UsageMsg reports provider usage after each request
+type UsageMsg struct {
+	Batch ai.Usage // Usage of the request that just completed
+	File  ai.Usage // Aggregated usage for the current file
+	Job   ai.Usage // Aggregated usage for the whole job
+}
+
+// DedupMsg reports the lines resolved before batching a file
+type DedupMsg struct {
+	File pipeline.DedupStats // Deduplication of the current file
+	Job  pipeline.DedupStats // Aggregated over the whole job
+}
+
+// BudgetExceededMsg pauses the job until the user decides whether to exceed a spending limit
+type BudgetExceededMsg struct {
+	Status pipeline.BudgetStatus
+	Reply  chan bool // Receives true to continue, false to abort
+}
+
 // StatusMsg updates job status
 type StatusMsg struct {
 	Status JobStatus
@@ -574,6 +750,16 @@ type CompletedMsg struct {
 // QualityGateMsg is sent when linter finds issues that need user decision
 type QualityGateMsg struct {
 	Issues []QualityIssue
+	Report pipeline.QualityReport
+	Reply  chan pipeline.QualityDecision // Receives the decision; the pipeline waits for it
+	Cache  *db.Cache                     // Records review edits as approved (nil = not recorded)
+}
+
+// GlossaryReviewMsg is sent when the glossary pre-pass proposed terms that
+// need approval before translating
+type GlossaryReviewMsg struct {
+	Path  string        // Series glossary to review
+	Reply chan struct{} // Closed review; the pipeline waits for it
 }
 
 // QualityDecisionMsg is sent when user makes a decision on quality issues
@@ -585,9 +771,26 @@ type QualityDecisionMsg struct {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	// The review editor opened from the quality gate takes over the screen
+	if m.reviewer != nil {
+		if handled, next, cmd := m.updateReviewer(msg); handled {
+			return next, cmd
+		}
+	}
+	if m.glossaryEditor != nil {
+		if handled, next, cmd := m.updateGlossaryEditor(msg); handled {
+			return next, cmd
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// Handle Quality Gate modal input first
+		// Handle Budget prompt input first
+		if m.showBudgetPrompt {
+			return m.handleBudgetPromptInput(msg)
+		}
+
+		// Handle Quality Gate modal input
 		if m.showQualityGate {
 			return m.handleQualityGateInput(msg)
 		}
@@ -652,12 +855,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case QualityGateMsg:
 		m.qualityIssues = msg.Issues
+		m.qualityReport = msg.Report
+		m.qualityReply = msg.Reply
+		m.qualityCache = msg.Cache
 		m.showQualityGate = true
 		m.status = StatusQualityGate
 		m.qualityAction = 0 // Default to auto-fix
 		// Continue listening for more messages (quality gate decisions will be handled)
 		return m, m.listenForMessages()
 
+	case QualityDecisionMsg:
+		return m.applyQualityDecision(pipeline.QualityAction(msg.Action))
+
+	case GlossaryReviewMsg:
+		m.logBuffer.AddLine(LogInfo, "Glossary pre-pass: review the proposed terms")
+		m.glossaryEditor = glossary.New(msg.Path)
+		m.glossaryEditor.SetLanguages(m.jobConfig.SourceLang, m.jobConfig.TargetLang)
+		m.glossaryEditor.SetSize(m.width, m.height)
+		m.glossaryReply = msg.Reply
+		return m, m.listenForMessages()
+
 	case LogMsg:
 		m.logBuffer.AddLine(msg.Level, msg.Message)
 		// Update viewport content
@@ -690,6 +907,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Continue listening for more messages
 		return m, m.listenForMessages()
 
+	case BudgetExceededMsg:
+		m.showBudgetPrompt = true
+		m.budgetStatus = msg.Status
+		m.budgetReply = msg.Reply
+		m.status = StatusPaused
+		return m, m.listenForMessages()
+
+	case UsageMsg:
+		m.usage = msg.Job
+		m.tokensUsed = msg.Job.TotalTokens()
+		m.costSoFar = msg.Job.Cost
+		// Continue listening for more messages
+		return m, m.listenForMessages()
+
+	case DedupMsg:
+		m.dedup = msg.Job
+		// Continue listening for more messages
+		return m, m.listenForMessages()
+
 	case StatusMsg:
 		m.status = msg.Status
 		// Continue listening for more messages
@@ -796,6 +1032,18 @@ func (m Model) View() string {
 		return layout.RenderTooSmallWarning(m.width, m.height)
 	}
 
+	if m.reviewer != nil {
+		return m.reviewer.View()
+	}
+	if m.glossaryEditor != nil {
+		return m.glossaryEditor.View()
+	}
+
+	// Show Budget prompt if active
+	if m.showBudgetPrompt {
+		return m.renderBudgetPrompt()
+	}
+
 	// Show Quality Gate modal if active
 	if m.showQualityGate {
 		return m.renderQualityGate()
@@ -920,8 +1168,9 @@ func (m Model) renderProgress(contentWidth int) string {
 	stats := lipgloss.JoinVertical(
 		lipgloss.Left,
 		fmt.Sprintf("%s %d", locales.T("execution.stats.lines"), m.linesProcessed),
-		fmt.Sprintf("%s %s", locales.T("execution.stats.tokens"), formatNumber(m.tokensUsed)),
-		fmt.Sprintf("%s $%.4f", locales.T("execution.stats.cost"), m.costSoFar),
+		m.renderTokens(),
+		m.renderCost(),
+		m.renderSaved(),
 		fmt.Sprintf("%s %d", locales.T("execution.stats.errors"), m.errors),
 	)
 
@@ -935,6 +1184,32 @@ func (m Model) renderProgress(contentWidth int) string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, filePanel, batchPanel, statsPanel)
 }
 
+// renderTokens formats the token counter, including prompt-cache hits when reported
+func (m Model) renderTokens() string {
+	tokens := fmt.Sprintf("%s %s", locales.T("execution.stats.tokens"), formatNumber(m.tokensUsed))
+	if m.usage.CachedTokens > 0 {
+		tokens += " " + fmt.Sprintf(locales.T("execution.stats.cached"), formatNumber(m.usage.CachedTokens))
+	}
+	return tokens
+}
+
+// renderCost formats the cost counter, marking estimated costs with "~"
+func (m Model) renderCost() string {
+	if m.usage.Requests > 0 && !m.usage.CostReported {
+		return fmt.Sprintf("%s ~$%.4f", locales.T("execution.stats.cost"), m.costSoFar)
+	}
+	return fmt.Sprintf("%s $%.4f", locales.T("execution.stats.cost"), m.costSoFar)
+}
+
+// renderSaved formats the tokens saved by translating repeated lines once
+func (m Model) renderSaved() string {
+	saved := fmt.Sprintf("%s %s", locales.T("execution.stats.saved"), formatNumber(m.dedup.TokensSaved))
+	if m.dedup.DuplicateLines > 0 {
+		saved += " " + fmt.Sprintf(locales.T("execution.stats.repeats"), m.dedup.DuplicateLines)
+	}
+	return saved
+}
+
 func (m Model) renderTape() string {
 	if m.tapeView.GetPairCount() == 0 {
 		// Show empty state with instructions
@@ -982,6 +1257,56 @@ func (m Model) renderLogs() string {
 	)
 }
 
+// handleBudgetPromptInput answers the paused pipeline: continue past the limit or abort
+func (m Model) handleBudgetPromptInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var approved bool
+	switch msg.String() {
+	case "y", "enter":
+		approved = true
+		m.logBuffer.AddLine(LogWarn, fmt.Sprintf("Budget override approved (%s limit)", m.budgetStatus.Scope))
+	case "n", "esc":
+		approved = false
+		m.logBuffer.AddLine(LogWarn, fmt.Sprintf("Job stopped at %s budget limit", m.budgetStatus.Scope))
+	default:
+		return m, nil
+	}
+
+	if m.budgetReply != nil {
+		m.budgetReply <- approved
+		m.budgetReply = nil
+	}
+	m.showBudgetPrompt = false
+	m.status = StatusRunning
+	m.viewport.SetContent(m.logBuffer.GetRawText())
+	if m.autoScroll {
+		m.viewport.GotoBottom()
+	}
+	return m, nil
+}
+
+// renderBudgetPrompt renders the spending limit modal
+func (m Model) renderBudgetPrompt() string {
+	var s strings.Builder
+
+	s.WriteString(styles.TitleStyle.Render(locales.T("execution.budget.title")))
+	s.WriteString("\n\n")
+	s.WriteString(fmt.Sprintf(locales.T("execution.budget.description"), locales.T("execution.budget.scope_"+m.budgetStatus.Scope)))
+	s.WriteString("\n\n")
+
+	var details strings.Builder
+	details.WriteString(fmt.Sprintf("   %-12s $%.4f\n", locales.T("execution.budget.limit"), m.budgetStatus.Limit))
+	details.WriteString(fmt.Sprintf("   %-12s $%.4f\n", locales.T("execution.budget.spent"), m.budgetStatus.Spent))
+	details.WriteString(fmt.Sprintf("   %-12s ~$%.4f\n", locales.T("execution.budget.next_batch"), m.budgetStatus.Projected))
+	s.WriteString(styles.Panel.Render(details.String()))
+	s.WriteString("\n\n")
+	s.WriteString(styles.Dimmed.Render(locales.T("execution.budget.resume_hint")))
+	s.WriteString("\n\n")
+	s.WriteString(styles.KeyHintStyle.Render("[Y]") + " " + locales.T("execution.budget.continue") + "   ")
+	s.WriteString(styles.KeyHintStyle.Render("[N]") + " " + locales.T("execution.budget.abort"))
+
+	return styles.ModalStyle.Width(78).Render(s.String())
+}
+
 func (m Model) handleQualityGateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "up", "k":
@@ -1009,6 +1334,107 @@ func (m Model) handleQualityGateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// applyQualityDecision answers the paused pipeline, opening the review editor
+// on the flagged lines for a manual review
+func (m Model) applyQualityDecision(action pipeline.QualityAction) (tea.Model, tea.Cmd) {
+	switch action {
+	case pipeline.QualityManualReview:
+		m.logBuffer.AddLine(LogInfo, fmt.Sprintf("Quality Gate: reviewing %d issues", len(m.qualityReport.Issues)))
+		m.reviewer = review.NewFromLines(m.qualityReport.Source, m.qualityReport.Lines)
+		m.reviewer.SetIssues(m.qualityReport.Issues)
+		if m.qualityCache != nil {
+			m.reviewer.SetCache(m.qualityCache, m.qualityReport.Scope)
+		}
+		m.reviewer.SetSize(m.width, m.height)
+		m.status = StatusQualityGate
+		return m, nil
+	case pipeline.QualityAutoFix:
+		m.logBuffer.AddLine(LogInfo, "Quality Gate: re-requesting failing lines")
+	default:
+		m.logBuffer.AddLine(LogWarn, "Quality Gate: issues ignored")
+	}
+
+	m.replyQuality(pipeline.QualityDecision{Action: action})
+	return m, nil
+}
+
+// updateReviewer forwards messages to the review editor and resumes the
+// pipeline with the reviewed lines once it is closed
+func (m Model) updateReviewer(msg tea.Msg) (bool, tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case review.ClosedMsg:
+		lines := m.reviewer.Lines()
+		m.reviewer = nil
+		m.replyQuality(pipeline.QualityDecision{Action: pipeline.QualityManualReview, Lines: lines})
+		return true, m, nil
+	case tea.WindowSizeMsg:
+		m.reviewer.SetSize(msg.Width, msg.Height)
+		return false, m, nil
+	case tea.KeyMsg, review.SavedMsg:
+		updated, cmd := m.reviewer.Update(msg)
+		reviewer := updated.(review.Model)
+		m.reviewer = &reviewer
+		return true, m, cmd
+	}
+	return false, m, nil
+}
+
+// updateGlossaryEditor forwards messages to the glossary editor and resumes
+// the pipeline once it is closed, saving the reviewed terms
+func (m Model) updateGlossaryEditor(msg tea.Msg) (bool, tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case glossary.ClosedMsg:
+		if err := m.glossaryEditor.Save(); err != nil {
+			m.logBuffer.AddLine(LogError, fmt.Sprintf("Failed to save glossary: %v", err))
+		}
+		m.glossaryEditor = nil
+		if m.glossaryReply != nil {
+			m.glossaryReply <- struct{}{}
+			m.glossaryReply = nil
+		}
+		m.viewport.SetContent(m.logBuffer.GetRawText())
+		if m.autoScroll {
+			m.viewport.GotoBottom()
+		}
+		return true, m, nil
+	case tea.WindowSizeMsg:
+		m.glossaryEditor.SetSize(msg.Width, msg.Height)
+		return false, m, nil
+	case tea.KeyMsg:
+		_, cmd := m.glossaryEditor.Update(msg)
+		return true, m, cmd
+	}
+	return false, m, nil
+}
+
+// replyQuality sends the decision to the paused pipeline
+func (m *Model) replyQuality(decision pipeline.QualityDecision) {
+	if m.qualityReply != nil {
+		m.qualityReply <- decision
+		m.qualityReply = nil
+	}
+	m.showQualityGate = false
+	m.status = StatusRunning
+	m.viewport.SetContent(m.logBuffer.GetRawText())
+	if m.autoScroll {
+		m.viewport.GotoBottom()
+	}
+}
+
+// qualityIssues converts lint issues for display
+func qualityIssues(issues []linter.Issue) []QualityIssue {
+	result := make([]QualityIssue, len(issues))
+	for i, issue := range issues {
+		result[i] = QualityIssue{
+			LineID:   issue.LineID,
+			Severity: string(issue.Severity),
+			Type:     issue.IssueType,
+			Content:  issue.Content,
+		}
+	}
+	return result
+}
+
 func (m Model) renderQualityGate() string {
 	var s strings.Builder
 
@@ -1,8 +1,18 @@
 package execution
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
+	"github.com/lsilvatti/bakasub/internal/core/pipeline"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
 )
 
 // TestLogLevelConstants tests LogLevel constants
@@ -302,3 +312,215 @@ func TestLogBufferLargeBatch(t *testing.T) {
 		t.Errorf("Count() = %d, want 50 (maxSize)", buffer.Count())
 	}
 }
+
+// TestUsageMsgUpdatesStats tests that provider usage drives the token/cost counters
+func TestUsageMsgUpdatesStats(t *testing.T) {
+	m := New(config.Default(), JobConfig{InputPath: "/tmp/test.mkv"})
+
+	job := ai.Usage{PromptTokens: 1200, CompletionTokens: 300, CachedTokens: 1000, Cost: 0.0123, CostReported: true, Requests: 2}
+	updated, _ := m.Update(UsageMsg{Job: job})
+	m = updated.(Model)
+
+	if m.tokensUsed != 1500 {
+		t.Errorf("tokensUsed = %d, want 1500", m.tokensUsed)
+	}
+	if m.costSoFar != 0.0123 {
+		t.Errorf("costSoFar = %f, want 0.0123", m.costSoFar)
+	}
+	if !strings.Contains(m.renderTokens(), "1.0K") {
+		t.Errorf("renderTokens() should mention cached tokens: %q", m.renderTokens())
+	}
+	if strings.Contains(m.renderCost(), "~") {
+		t.Errorf("reported cost should not be marked as estimate: %q", m.renderCost())
+	}
+
+	job.CostReported = false
+	updated, _ = m.Update(UsageMsg{Job: job})
+	m = updated.(Model)
+	if !strings.Contains(m.renderCost(), "~") {
+		t.Errorf("estimated cost should be marked with ~: %q", m.renderCost())
+	}
+}
+
+// TestDedupMsgUpdatesSaved tests that deduplicated lines show up as saved tokens
+func TestDedupMsgUpdatesSaved(t *testing.T) {
+	m := New(config.Default(), JobConfig{InputPath: "/tmp/test.mkv"})
+
+	job := pipeline.DedupStats{TotalLines: 300, UniqueLines: 220, DuplicateLines: 80, TokensSaved: 2500}
+	updated, _ := m.Update(DedupMsg{File: job, Job: job})
+	m = updated.(Model)
+
+	saved := m.renderSaved()
+	if !strings.Contains(saved, "2.5K") || !strings.Contains(saved, "80") {
+		t.Errorf("renderSaved() = %q, want tokens saved and repeat count", saved)
+	}
+}
+
+// TestEvictionLog tests that the job log lists what eviction removed
+func TestEvictionLog(t *testing.T) {
+	report := &db.EvictionReport{
+		Policy:        db.EvictLRU,
+		EntriesBefore: 10,
+		EntriesAfter:  7,
+		EvictedCount:  3,
+		Evicted: []db.EvictedEntry{
+			{OriginalText: "Eh?", TranslatedText: "Hã?", LangPair: "en->pt-br", UseCount: 2},
+			{OriginalText: "Wait!", TranslatedText: "Espera!", LangPair: "en->pt-br", UseCount: 1},
+		},
+	}
+
+	lines := evictionLog(report)
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want summary, 2 entries and a remainder: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], "evicted 3 of 10") || !strings.Contains(lines[1], `"Eh?"`) || !strings.Contains(lines[3], "1 more") {
+		t.Errorf("unexpected log: %q", lines)
+	}
+}
+
+// TestBudgetPromptReply tests that the budget modal answers the waiting pipeline
+func TestBudgetPromptReply(t *testing.T) {
+	m := New(config.Default(), JobConfig{InputPath: "/tmp/test.mkv"})
+
+	reply := make(chan bool, 1)
+	updated, _ := m.Update(BudgetExceededMsg{
+		Status: pipeline.BudgetStatus{Scope: pipeline.BudgetScopeJob, Limit: 1, Spent: 1.2},
+		Reply:  reply,
+	})
+	m = updated.(Model)
+
+	if !m.showBudgetPrompt || m.status != StatusPaused {
+		t.Fatal("budget prompt should pause the job")
+	}
+	if !strings.Contains(m.renderBudgetPrompt(), "1.2000") {
+		t.Error("budget prompt should show the amount spent")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+
+	if m.showBudgetPrompt {
+		t.Error("budget prompt should close after answering")
+	}
+	select {
+	case approved := <-reply:
+		if approved {
+			t.Error("'n' should abort the job")
+		}
+	default:
+		t.Error("pipeline did not receive an answer")
+	}
+}
+
+// TestQualityGateManualReview tests that a manual review edits the flagged lines and answers the waiting pipeline
+func TestQualityGateManualReview(t *testing.T) {
+	m := New(config.Default(), JobConfig{InputPath: "/tmp/test.mkv"})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	report := pipeline.QualityReport{
+		Source: linter.TextLines([]string{"Hello", "Where are you going now?"}),
+		Lines:  linter.TextLines([]string{"Olá", "Where are you going now?"}),
+		Issues: []linter.Issue{{LineID: 2, Severity: linter.SeverityHigh, IssueType: "Untranslated", RuleID: linter.RuleUntranslated}},
+	}
+	reply := make(chan pipeline.QualityDecision, 1)
+	updated, _ = m.Update(QualityGateMsg{Issues: qualityIssues(report.Issues), Report: report, Reply: reply})
+	m = updated.(Model)
+	if !m.showQualityGate || m.status != StatusQualityGate {
+		t.Fatal("quality gate should pause the job")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.reviewer == nil {
+		t.Fatal("manual review should open the review editor")
+	}
+	if !strings.Contains(m.View(), "Where are you going now?") {
+		t.Error("the editor should start on the flagged line")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Aonde vai?")})
+	m = updated.(Model)
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	if m.reviewer != nil || m.status != StatusRunning {
+		t.Error("closing the editor should resume the job")
+	}
+	select {
+	case decision := <-reply:
+		if decision.Action != pipeline.QualityManualReview || decision.Lines[0].Text != "Olá" || decision.Lines[1].Text != "Aonde vai?" {
+			t.Errorf("unexpected decision: %+v", decision)
+		}
+	default:
+		t.Error("pipeline did not receive an answer")
+	}
+}
+
+// TestQualityGateIgnore tests that esc ignores the issues
+func TestQualityGateIgnore(t *testing.T) {
+	m := New(config.Default(), JobConfig{InputPath: "/tmp/test.mkv"})
+	reply := make(chan pipeline.QualityDecision, 1)
+	updated, _ := m.Update(QualityGateMsg{Reply: reply})
+	m = updated.(Model)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	m.Update(cmd())
+
+	if decision := <-reply; decision.Action != pipeline.QualityIgnore {
+		t.Errorf("esc should ignore the issues, got %+v", decision)
+	}
+}
+
+// TestGlossaryReview tests approving pre-pass proposals in the glossary editor
+func TestGlossaryReview(t *testing.T) {
+	path := filepath.Join(t.TempDir(), termbase.FileName)
+	store := &termbase.Store{Path: path}
+	store.Propose([]termbase.Proposal{{Term: "nakama", Type: "Term", Translation: "companheiro", Rationale: "recurring word"}})
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(config.Default(), JobConfig{InputPath: "/tmp/test.mkv"})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	reply := make(chan struct{}, 1)
+	updated, _ = m.Update(GlossaryReviewMsg{Path: path, Reply: reply})
+	m = updated.(Model)
+	if m.glossaryEditor == nil || !strings.Contains(m.View(), "recurring word") {
+		t.Fatal("the glossary editor should open on the proposals")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m = updated.(Model)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	if m.glossaryEditor != nil {
+		t.Error("closing the editor should resume the job")
+	}
+	select {
+	case <-reply:
+	default:
+		t.Fatal("pipeline did not receive an answer")
+	}
+	saved, err := termbase.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.Glossary()["nakama"]; got != "companheiro" {
+		t.Errorf("approved proposal not saved, got %q", got)
+	}
+}
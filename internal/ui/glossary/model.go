@@ -1,16 +1,19 @@
 package glossary
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"regexp"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+	"github.com/lsilvatti/bakasub/internal/core/ner"
 	"github.com/lsilvatti/bakasub/internal/core/parser"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
 	"github.com/lsilvatti/bakasub/internal/locales"
 	"github.com/lsilvatti/bakasub/internal/ui/styles"
 )
@@ -20,14 +23,11 @@ type State int
 const (
 	StateView State = iota
 	StateAddTerm
+	StateExchange
 )
 
-type Entry struct {
-	Original     string `json:"original"`
-	Translation  string `json:"translation"`
-	Type         string `json:"type"`
-	AutoDetected bool   `json:"auto_detected"`
-}
+// Entry is a term of the series glossary
+type Entry = termbase.Term
 
 // ClosedMsg is sent when the glossary editor should be closed
 type ClosedMsg struct{}
@@ -44,37 +44,29 @@ type Model struct {
 	addOriginal    string
 	addTranslation string
 	addType        string
-	addField       int // 0=original, 1=translation, 2=type
+	addForbidden   string // Comma-separated forbidden renderings
+	addField       int    // 0=original, 1=translation, 2=type, 3=forbidden
+	editIndex      int    // Entry being translated, -1 when adding
 
 	// Pagination
 	currentPage  int
 	itemsPerPage int
+
+	// Import/export
+	exchangeInput  textinput.Model
+	exchangeImport bool // Importing rather than exporting
+	conflict       exchange.ConflictPolicy
+	langs          termbase.Languages
+	exchangeStatus string
+	exchangeError  bool
 }
 
 func New(glossaryPath string) *Model {
 	entries := loadOrCreate(glossaryPath)
 
 	// Default columns (will be resized on WindowSizeMsg)
-	columns := []table.Column{
-		{Title: "ORIGINAL", Width: 30},
-		{Title: "TRANSLATION", Width: 30},
-		{Title: "TYPE", Width: 15},
-		{Title: "SOURCE", Width: 10},
-	}
-
-	rows := make([]table.Row, len(entries))
-	for i, e := range entries {
-		source := "Manual"
-		if e.AutoDetected {
-			source := "Auto"
-			_ = source
-		}
-		rows[i] = table.Row{e.Original, e.Translation, e.Type, source}
-	}
-
 	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
+		table.WithColumns(tableColumns(30)),
 		table.WithFocused(true),
 		table.WithHeight(15),
 	)
@@ -94,13 +86,57 @@ func New(glossaryPath string) *Model {
 		Padding(0, 1)
 	t.SetStyles(s)
 
-	return &Model{
-		table:        t,
-		entries:      entries,
-		filePath:     glossaryPath,
-		currentPage:  1,
-		itemsPerPage: 15,
+	exchangeInput := textinput.New()
+	exchangeInput.Placeholder = "glossary.csv"
+	exchangeInput.CharLimit = 255
+	exchangeInput.Width = 54
+	exchangeInput.SetValue(filepath.Join(filepath.Dir(glossaryPath), "glossary.csv"))
+
+	m := &Model{
+		table:         t,
+		entries:       entries,
+		filePath:      glossaryPath,
+		editIndex:     -1,
+		currentPage:   1,
+		itemsPerPage:  15,
+		exchangeInput: exchangeInput,
+		conflict:      exchange.ConflictPreferApproved,
 	}
+	m.refreshTable()
+	return m
+}
+
+// tableColumns returns the table columns for a width of the text columns
+func tableColumns(textWidth int) []table.Column {
+	return []table.Column{
+		{Title: "ORIGINAL", Width: textWidth},
+		{Title: "TRANSLATION", Width: textWidth},
+		{Title: "TYPE", Width: 10},
+		{Title: "SOURCE", Width: 10},
+		{Title: "SEEN", Width: 10},
+		{Title: "ENFORCE", Width: 8},
+	}
+}
+
+// entryRow renders an entry as a table row
+func entryRow(e Entry) table.Row {
+	source := "Manual"
+	switch {
+	case e.Status == termbase.StatusCandidate && e.Proposed:
+		source = "LLM"
+	case e.Status == termbase.StatusCandidate:
+		source = "Candidate"
+	case e.Status == termbase.StatusRejected:
+		source = "Rejected"
+	case e.AutoDetected:
+		source = "Auto"
+	}
+
+	seen := ""
+	if len(e.Episodes) > 0 {
+		seen = fmt.Sprintf("%d (%d ep)", e.Count(), len(e.Episodes))
+	}
+	return table.Row{e.Original, e.Translation, e.Type, source, seen, string(e.Enforce)}
 }
 
 func (m *Model) SetSize(width, height int) {
@@ -108,23 +144,30 @@ func (m *Model) SetSize(width, height int) {
 	m.height = height
 
 	// Resize table columns dynamically
-	// Reserve: TYPE=15, SOURCE=10, borders/padding ~10
-	availableWidth := width - 35
+	// Reserve: TYPE=10, SOURCE=10, SEEN=10, ENFORCE=8, borders/padding ~12
+	availableWidth := width - 50
 	halfWidth := availableWidth / 2
 	if halfWidth < 15 {
 		halfWidth = 15
 	}
 
-	columns := []table.Column{
-		{Title: "ORIGINAL", Width: halfWidth},
-		{Title: "TRANSLATION", Width: halfWidth},
-		{Title: "TYPE", Width: 15},
-		{Title: "SOURCE", Width: 10},
-	}
-	m.table.SetColumns(columns)
+	m.table.SetColumns(tableColumns(halfWidth))
 	m.table.SetHeight(height - 8)
 }
 
+// SetLanguages sets the languages written to and picked from TBX files.
+// "auto" counts as unknown.
+func (m *Model) SetLanguages(source, target string) {
+	normalize := func(lang string) string {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang == "auto" {
+			return ""
+		}
+		return lang
+	}
+	m.langs = termbase.Languages{Source: normalize(source), Target: normalize(target)}
+}
+
 func (m *Model) Init() tea.Cmd {
 	// Request current terminal size
 	return tea.WindowSize()
@@ -142,28 +185,83 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.state == StateAddTerm {
 			return m.handleAddTermInput(msg)
 		}
+		if m.state == StateExchange {
+			return m.handleExchangeInput(msg)
+		}
 
 		switch msg.String() {
 		case "esc", "q":
 			return m, func() tea.Msg { return ClosedMsg{} }
 		case "ctrl+s":
-			m.save()
+			m.Save()
 		case "a":
 			// Add new term
 			m.state = StateAddTerm
 			m.addOriginal = ""
 			m.addTranslation = ""
 			m.addType = "Noun"
+			m.addForbidden = ""
 			m.addField = 0
+			m.editIndex = -1
+			return m, nil
+		case "i", "e":
+			// Import or export the glossary as CSV, TSV or TBX
+			m.state = StateExchange
+			m.exchangeImport = msg.String() == "i"
+			m.exchangeStatus = ""
+			m.exchangeInput.Focus()
+			m.exchangeInput.CursorEnd()
+			return m, textinput.Blink
+		case "t":
+			// Translate selected term (approves it)
+			if idx := m.selectedIndex(); idx >= 0 {
+				e := m.entries[idx]
+				m.state = StateAddTerm
+				m.addOriginal = e.Original
+				m.addTranslation = e.Target()
+				m.addType = e.Type
+				m.addForbidden = strings.Join(e.Forbidden, ", ")
+				m.addField = 1
+				m.editIndex = idx
+			}
+			return m, nil
+		case "p":
+			// Promote selected term, keeping it as-is unless translated
+			if idx := m.selectedIndex(); idx >= 0 {
+				m.entries[idx].Promote()
+				m.refreshTable()
+			}
+			return m, nil
+		case "f":
+			// Cycle how the selected term is enforced: prompt only, keep, force
+			if idx := m.selectedIndex(); idx >= 0 {
+				m.entries[idx].Enforce = m.entries[idx].Enforce.Next()
+				m.refreshTable()
+			}
+			return m, nil
+		case "n":
+			// Toggle whether enforcement accepts inflected translations
+			if idx := m.selectedIndex(); idx >= 0 {
+				m.entries[idx].Inflect = !m.entries[idx].Inflect
+				m.refreshTable()
+			}
+			return m, nil
+		case "r":
+			// Reject selected term, or restore a rejected one as a candidate
+			if idx := m.selectedIndex(); idx >= 0 {
+				if m.entries[idx].Status == termbase.StatusRejected {
+					m.entries[idx].Status = termbase.StatusCandidate
+				} else {
+					m.entries[idx].Reject()
+				}
+				m.refreshTable()
+			}
 			return m, nil
 		case "d", "delete", "backspace":
 			// Delete selected term
-			if len(m.entries) > 0 {
-				idx := m.table.Cursor()
-				if idx >= 0 && idx < len(m.entries) {
-					m.entries = append(m.entries[:idx], m.entries[idx+1:]...)
-					m.refreshTable()
-				}
+			if idx := m.selectedIndex(); idx >= 0 {
+				m.entries = append(m.entries[:idx], m.entries[idx+1:]...)
+				m.refreshTable()
 			}
 			return m, nil
 		case "left", "h":
@@ -197,26 +295,37 @@ func (m *Model) handleAddTermInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "enter":
-		if m.addField < 2 {
+		if m.addField < 3 {
 			m.addField++
 		} else {
-			// Save the new term
+			// Save the new or translated term
 			if m.addOriginal != "" && m.addTranslation != "" {
-				newEntry := Entry{
-					Original:     m.addOriginal,
-					Translation:  m.addTranslation,
-					Type:         m.addType,
-					AutoDetected: false,
+				if m.editIndex >= 0 && m.editIndex < len(m.entries) {
+					e := &m.entries[m.editIndex]
+					e.Original = m.addOriginal
+					e.Translation = m.addTranslation
+					e.Type = m.addType
+					e.Forbidden = splitForbidden(m.addForbidden)
+					e.Promote()
+				} else {
+					m.entries = append(m.entries, Entry{
+						Original:     m.addOriginal,
+						Translation:  m.addTranslation,
+						Type:         m.addType,
+						Forbidden:    splitForbidden(m.addForbidden),
+						AutoDetected: false,
+						Status:       termbase.StatusApproved,
+					})
 				}
-				m.entries = append(m.entries, newEntry)
 				m.refreshTable()
 			}
 			m.state = StateView
+			m.editIndex = -1
 		}
 		return m, nil
 
 	case "tab":
-		m.addField = (m.addField + 1) % 3
+		m.addField = (m.addField + 1) % 4
 		return m, nil
 
 	case "backspace":
@@ -233,6 +342,10 @@ func (m *Model) handleAddTermInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if len(m.addType) > 0 {
 				m.addType = m.addType[:len(m.addType)-1]
 			}
+		case 3:
+			if len(m.addForbidden) > 0 {
+				m.addForbidden = m.addForbidden[:len(m.addForbidden)-1]
+			}
 		}
 		return m, nil
 
@@ -246,6 +359,8 @@ func (m *Model) handleAddTermInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.addTranslation += key
 			case 2:
 				m.addType += key
+			case 3:
+				m.addForbidden += key
 			}
 		}
 	}
@@ -253,6 +368,62 @@ func (m *Model) handleAddTermInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m *Model) handleExchangeInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = StateView
+		m.exchangeInput.Blur()
+		return m, nil
+	case "tab":
+		// Cycle the import conflict policy
+		if m.exchangeImport {
+			m.conflict = m.conflict.Next()
+		}
+		return m, nil
+	case "enter":
+		if m.exchangeImport {
+			m.importTerms()
+		} else {
+			m.exportTerms()
+		}
+		m.state = StateView
+		m.exchangeInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.exchangeInput, cmd = m.exchangeInput.Update(msg)
+	return m, cmd
+}
+
+// exportTerms writes the entries, including unsaved edits, to the exchange file
+func (m *Model) exportTerms() {
+	path := strings.TrimSpace(m.exchangeInput.Value())
+	n, err := termbase.Export(&termbase.Store{Terms: m.entries}, path, "", m.langs)
+	m.setExchangeStatus(locales.Tf("glossary_editor.exported", n, path), err)
+}
+
+// importTerms merges the exchange file into the entries. The result is
+// written with the rest of the edits on save.
+func (m *Model) importTerms() {
+	path := strings.TrimSpace(m.exchangeInput.Value())
+	store := &termbase.Store{Terms: m.entries}
+	result, err := termbase.Import(store, path, "", m.langs, m.conflict)
+	if err == nil {
+		m.entries = store.Terms
+		m.refreshTable()
+	}
+	m.setExchangeStatus(locales.Tf("glossary_editor.imported", result.Added, result.Updated, result.Skipped, len(result.Conflicts)), err)
+}
+
+func (m *Model) setExchangeStatus(status string, err error) {
+	m.exchangeError = err != nil
+	if err != nil {
+		status = err.Error()
+	}
+	m.exchangeStatus = status
+}
+
 func (m *Model) refreshTable() {
 	// Get paginated entries
 	start := (m.currentPage - 1) * m.itemsPerPage
@@ -268,15 +439,23 @@ func (m *Model) refreshTable() {
 	paginatedEntries := m.entries[start:end]
 	rows := make([]table.Row, len(paginatedEntries))
 	for i, e := range paginatedEntries {
-		source := "Manual"
-		if e.AutoDetected {
-			source = "Auto"
-		}
-		rows[i] = table.Row{e.Original, e.Translation, e.Type, source}
+		rows[i] = entryRow(e)
 	}
 	m.table.SetRows(rows)
 }
 
+// selectedIndex returns the index in entries of the selected row, or -1
+func (m *Model) selectedIndex() int {
+	if len(m.table.Rows()) == 0 {
+		return -1
+	}
+	idx := (m.currentPage-1)*m.itemsPerPage + m.table.Cursor()
+	if idx < 0 || idx >= len(m.entries) {
+		return -1
+	}
+	return idx
+}
+
 func (m *Model) getTotalPages() int {
 	if len(m.entries) == 0 {
 		return 1
@@ -297,6 +476,9 @@ func (m *Model) View() string {
 	if m.state == StateAddTerm {
 		return m.viewAddTerm()
 	}
+	if m.state == StateExchange {
+		return m.viewExchange()
+	}
 
 	header := styles.TitleStyle.Render(locales.T("glossary_editor.title")) + "\n"
 	header += styles.SubtleStyle.Render(fmt.Sprintf("%s %s", locales.T("glossary_editor.file_label"), m.filePath)) + "\n\n"
@@ -304,11 +486,34 @@ func (m *Model) View() string {
 	// Terms management section
 	controls := styles.SectionStyle.Render("┌── "+locales.T("glossary_editor.terms_management")+" ──────────────────────────────────────────────────┐") + "\n"
 	controls += "│  " + styles.KeyHintStyle.Render("[ a ]") + " " + locales.T("glossary_editor.add_new_term") + "    "
-	controls += styles.KeyHintStyle.Render("[ i ]") + " " + locales.T("glossary_editor.import_csv") + "    "
+	controls += styles.KeyHintStyle.Render("[ i ]") + " " + locales.T("glossary_editor.import") + "    "
+	controls += styles.KeyHintStyle.Render("[ e ]") + " " + locales.T("glossary_editor.export") + "    "
 	controls += styles.KeyHintStyle.Render("[DEL]") + " " + locales.T("glossary_editor.remove_selected") + "\n"
+	controls += "│  " + styles.KeyHintStyle.Render("[ p ]") + " " + locales.T("glossary_editor.promote") + "    "
+	controls += styles.KeyHintStyle.Render("[ t ]") + " " + locales.T("glossary_editor.translate") + "    "
+	controls += styles.KeyHintStyle.Render("[ r ]") + " " + locales.T("glossary_editor.reject") + "    "
+	controls += styles.KeyHintStyle.Render("[ f ]") + " " + locales.T("glossary_editor.enforce") + "\n"
+	controls += "│  " + styles.KeyHintStyle.Render("[ n ]") + " " + locales.T("glossary_editor.inflect") + "\n"
 	controls += styles.SectionStyle.Render("└──────────────────────────────────────────────────────────────────────┘") + "\n\n"
 
 	tableView := m.table.View()
+	if idx := m.selectedIndex(); idx >= 0 && m.entries[idx].Rationale != "" {
+		tableView += "\n  " + styles.SubtleStyle.Render(locales.T("glossary_editor.rationale")+" "+m.entries[idx].Rationale)
+	}
+	if idx := m.selectedIndex(); idx >= 0 && len(m.entries[idx].Forbidden) > 0 {
+		tableView += "\n  " + styles.SubtleStyle.Render(locales.T("glossary_editor.forbidden")+" "+strings.Join(m.entries[idx].Forbidden, ", "))
+	}
+	if idx := m.selectedIndex(); idx >= 0 && m.entries[idx].Inflect {
+		tableView += "\n  " + styles.SubtleStyle.Render(locales.T("glossary_editor.inflect_on"))
+	}
+
+	if m.exchangeStatus != "" {
+		status := styles.StatusOK.Render(m.exchangeStatus)
+		if m.exchangeError {
+			status = styles.StatusError.Render(m.exchangeStatus)
+		}
+		tableView += "\n  " + status
+	}
 
 	// Pagination indicator
 	totalPages := m.getTotalPages()
@@ -332,7 +537,11 @@ func (m *Model) View() string {
 func (m *Model) viewAddTerm() string {
 	var b strings.Builder
 
-	b.WriteString(styles.TitleStyle.Render(locales.T("glossary_editor.add_new_term")))
+	title := locales.T("glossary_editor.add_new_term")
+	if m.editIndex >= 0 {
+		title = locales.T("glossary_editor.translate_term")
+	}
+	b.WriteString(styles.TitleStyle.Render(title))
 	b.WriteString("\n\n")
 
 	// Original term
@@ -371,6 +580,18 @@ func (m *Model) viewAddTerm() string {
 	}
 	b.WriteString("\n\n")
 
+	// Forbidden renderings
+	forbiddenStyle := styles.SubtleStyle
+	if m.addField == 3 {
+		forbiddenStyle = styles.HighlightStyle
+	}
+	b.WriteString(forbiddenStyle.Render(locales.T("glossary_editor.forbidden_label") + " "))
+	b.WriteString(fmt.Sprintf("[%s]", m.addForbidden))
+	if m.addField == 3 {
+		b.WriteString("_")
+	}
+	b.WriteString("\n\n")
+
 	b.WriteString(styles.KeyHintStyle.Render("[TAB]") + " " + locales.T("glossary_editor.next_field") + "  ")
 	b.WriteString(styles.KeyHintStyle.Render("[ENTER]") + " " + locales.T("glossary_editor.confirm") + "  ")
 	b.WriteString(styles.KeyHintStyle.Render("[ESC]") + " " + locales.T("common.cancel"))
@@ -378,80 +599,90 @@ func (m *Model) viewAddTerm() string {
 	return styles.ModalStyle.Width(60).Render(b.String())
 }
 
-func (m Model) save() error {
-	data, err := json.MarshalIndent(m.entries, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(m.filePath, data, 0644)
-}
+func (m *Model) viewExchange() string {
+	var b strings.Builder
 
-func loadOrCreate(path string) []Entry {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return []Entry{}
+	title := locales.T("glossary_editor.export_title")
+	if m.exchangeImport {
+		title = locales.T("glossary_editor.import_title")
 	}
+	b.WriteString(styles.TitleStyle.Render(title))
+	b.WriteString("\n\n")
 
-	var entries []Entry
-	json.Unmarshal(data, &entries)
-	return entries
-}
+	b.WriteString(styles.HighlightStyle.Render(locales.T("glossary_editor.file_label")) + " ")
+	b.WriteString(m.exchangeInput.View())
+	b.WriteString("\n\n")
 
-func AutoDetectTerms(subtitlePath string) ([]Entry, error) {
-	subFile, err := parser.ParseFile(subtitlePath)
-	if err != nil {
-		return nil, err
+	if m.exchangeImport {
+		b.WriteString(styles.SubtleStyle.Render(locales.T("glossary_editor.conflict") + " "))
+		b.WriteString(locales.T("glossary_editor.conflict_" + string(m.conflict)))
+		b.WriteString("\n\n")
 	}
+	b.WriteString(styles.SubtleStyle.Render(locales.T("glossary_editor.formats")))
+	b.WriteString("\n\n")
 
-	termMap := make(map[string]bool)
-	entries := []Entry{}
-
-	capitalized := regexp.MustCompile(`\b([A-Z][a-z]{2,})\b`)
-
-	for _, line := range subFile.Lines {
-		matches := capitalized.FindAllString(line.Text, -1)
-		for _, match := range matches {
-			if !termMap[match] && !isCommonWord(match) {
-				termMap[match] = true
-				entries = append(entries, Entry{
-					Original:     match,
-					Translation:  match,
-					Type:         "name",
-					AutoDetected: true,
-				})
-			}
-		}
+	if m.exchangeImport {
+		b.WriteString(styles.KeyHintStyle.Render("[TAB]") + " " + locales.T("glossary_editor.cycle_conflict") + "  ")
 	}
+	b.WriteString(styles.KeyHintStyle.Render("[ENTER]") + " " + locales.T("glossary_editor.confirm") + "  ")
+	b.WriteString(styles.KeyHintStyle.Render("[ESC]") + " " + locales.T("common.cancel"))
+
+	return styles.ModalStyle.Width(70).Render(b.String())
+}
 
-	return entries, nil
+// Save writes the entries to the glossary file
+func (m Model) Save() error {
+	store := &termbase.Store{Path: m.filePath, Terms: m.entries}
+	return store.Save()
 }
 
-func isCommonWord(word string) bool {
-	common := []string{"The", "This", "That", "With", "From", "Have", "What", "When", "Where", "Which", "While"}
-	word = strings.ToLower(word)
-	for _, c := range common {
-		if strings.ToLower(c) == word {
-			return true
+// splitForbidden parses the comma-separated forbidden renderings of the form
+func splitForbidden(s string) []string {
+	var forbidden []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			forbidden = append(forbidden, f)
 		}
 	}
-	return false
+	return forbidden
 }
 
-func MergeGlossaries(auto, manual []Entry) []Entry {
-	merged := make(map[string]Entry)
-
-	for _, e := range auto {
-		merged[e.Original] = e
+// loadOrCreate loads the series glossary with candidates listed first, most
+// frequent first, and rejected terms last
+func loadOrCreate(path string) []Entry {
+	store, err := termbase.Load(path)
+	if err != nil {
+		return []Entry{}
 	}
 
-	for _, e := range manual {
-		merged[e.Original] = e
-	}
+	rank := map[termbase.Status]int{termbase.StatusCandidate: 0, termbase.StatusApproved: 1, termbase.StatusRejected: 2}
+	sort.SliceStable(store.Terms, func(i, j int) bool {
+		a, b := store.Terms[i], store.Terms[j]
+		if rank[a.Status] != rank[b.Status] {
+			return rank[a.Status] < rank[b.Status]
+		}
+		return a.Status == termbase.StatusCandidate && a.Count() > b.Count()
+	})
+	return store.Terms
+}
 
-	result := []Entry{}
-	for _, e := range merged {
-		result = append(result, e)
+// AutoDetectTerms scans a subtitle file with the NER scanner, the same
+// detection the pipeline records in the series glossary, and returns the
+// entities as candidates
+func AutoDetectTerms(subtitlePath string) ([]Entry, error) {
+	subFile, err := parser.ParseFile(subtitlePath)
+	if err != nil {
+		return nil, err
 	}
 
-	return result
+	store := &termbase.Store{}
+	store.Record(filepath.Base(subtitlePath), ner.NewScanner().ScanLines(subFile.Lines))
+	return store.Terms, nil
+}
+
+// MergeGlossaries merges manual entries over auto-detected ones
+func MergeGlossaries(auto, manual []Entry) []Entry {
+	store := &termbase.Store{Terms: append([]Entry{}, auto...)}
+	store.MergeTerms(manual, exchange.ConflictOverwrite)
+	return store.Terms
 }
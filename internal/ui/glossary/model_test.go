@@ -3,7 +3,15 @@ package glossary
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+	"github.com/lsilvatti/bakasub/internal/core/ner"
+	"github.com/lsilvatti/bakasub/internal/core/parser"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
 )
 
 // TestStateConstants tests State constants
@@ -15,6 +23,10 @@ func TestStateConstants(t *testing.T) {
 	if StateAddTerm != 1 {
 		t.Errorf("StateAddTerm = %d, want 1", StateAddTerm)
 	}
+
+	if StateExchange != 2 {
+		t.Errorf("StateExchange = %d, want 2", StateExchange)
+	}
 }
 
 // TestEntryStruct tests Entry structure
@@ -188,3 +200,155 @@ func TestLoadOrCreateNonExistent(t *testing.T) {
 		t.Error("loadOrCreate returned nil")
 	}
 }
+
+// press sends key presses to the editor
+func press(m *Model, keys ...string) {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "ctrl+u":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlU}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m.Update(msg)
+	}
+}
+
+// TestReviewCandidates tests promoting, rejecting and translating candidates
+func TestReviewCandidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), termbase.FileName)
+	store := &termbase.Store{Path: path}
+	store.Set("Sensei", "Mestre", "Title")
+	store.Record("ep01.mkv", []ner.Entity{
+		{Text: "Konoha", Type: ner.EntityPlace, Confidence: 0.9, Count: 2},
+		{Text: "Naruto", Type: ner.EntityName, Confidence: 0.9, Count: 5},
+		{Text: "Maybe", Type: ner.EntityName, Confidence: 0.6, Count: 3},
+	})
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(path)
+	if m.entries[0].Original != "Naruto" || m.entries[3].Original != "Sensei" {
+		t.Fatalf("candidates should be listed first by count: %+v", m.entries)
+	}
+
+	press(m, "p", "down", "r", "down", "t")
+	if m.state != StateAddTerm || m.addTranslation != "Konoha" || m.addField != 1 {
+		t.Fatalf("t should open the form on the translation, got %q (field %d)", m.addTranslation, m.addField)
+	}
+	for range len("Konoha") {
+		press(m, "backspace")
+	}
+	press(m, "F", "o", "l", "h", "a", "enter", "enter")
+	press(m, "A", "l", "d", "e", "i", "a", ",", " ", "V", "i", "l", "a", "enter")
+	press(m, "f", "f")
+
+	if err := m.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	saved, err := termbase.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for original, want := range map[string]string{"Naruto": "Naruto", "Konoha": "Folha", "Sensei": "Mestre"} {
+		if got := saved.Glossary()[original]; got != want {
+			t.Errorf("Glossary[%q] = %q, want %q", original, got, want)
+		}
+	}
+	if konoha := saved.Find("Konoha"); konoha.Enforce != termbase.EnforceForce || len(konoha.Forbidden) != 2 || konoha.Forbidden[1] != "Vila" {
+		t.Errorf("enforcement flags should be saved: %+v", konoha)
+	}
+	if maybe := saved.Find("Maybe"); maybe.Status != termbase.StatusRejected || maybe.Count() != 3 {
+		t.Errorf("rejected term should keep its counts: %+v", maybe)
+	}
+}
+
+// TestExchange tests exporting and importing the glossary from the editor
+func TestExchange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, termbase.FileName)
+	store := &termbase.Store{Path: path}
+	store.Set("Konoha", "Vila da Folha", "Place")
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(path)
+	m.SetLanguages("auto", "PT-BR")
+	if m.exchangeInput.Value() != filepath.Join(dir, "glossary.csv") {
+		t.Errorf("exchange file should default next to the glossary, got %q", m.exchangeInput.Value())
+	}
+
+	tbx := filepath.Join(dir, "terms.tbx")
+	press(m, "e", "ctrl+u")
+	press(m, strings.Split(tbx, "")...)
+	press(m, "enter")
+	if m.state != StateView || m.exchangeError {
+		t.Fatalf("export failed: %s", m.exchangeStatus)
+	}
+	if data, err := os.ReadFile(tbx); err != nil || !strings.Contains(string(data), `xml:lang="pt-br"`) {
+		t.Errorf("export should use the target language: %v\n%s", err, data)
+	}
+
+	csv := filepath.Join(dir, "terms.csv")
+	if err := os.WriteFile(csv, []byte("term,target\nKonoha,Aldeia da Folha\nNaruto,Naruto\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	press(m, "i", "ctrl+u")
+	press(m, strings.Split(csv, "")...)
+	press(m, "tab", "enter")
+	if m.conflict != exchange.ConflictKeep || m.exchangeError {
+		t.Fatalf("import failed: %s (policy %s)", m.exchangeStatus, m.conflict)
+	}
+	if len(m.entries) != 2 || m.entries[0].Translation != "Vila da Folha" || !strings.Contains(m.exchangeStatus, "1 conflicts") {
+		t.Errorf("keep should only add new terms: %+v (%s)", m.entries, m.exchangeStatus)
+	}
+
+	press(m, "i", "ctrl+u")
+	press(m, strings.Split(filepath.Join(dir, "missing.csv"), "")...)
+	press(m, "enter")
+	if !m.exchangeError {
+		t.Error("importing a missing file should report an error")
+	}
+}
+
+// TestAutoDetectTerms tests that auto-detection uses the NER scanner
+func TestAutoDetectTerms(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ep01.srt")
+	srt := "1\n00:00:01,000 --> 00:00:02,000\nWhere is Naruto going?\n\n2\n00:00:03,000 --> 00:00:04,000\nNaruto went to Konoha.\n"
+	if err := os.WriteFile(path, []byte(srt), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	terms, err := AutoDetectTerms(path)
+	if err != nil {
+		t.Fatalf("AutoDetectTerms failed: %v", err)
+	}
+
+	lines := []parser.SubtitleLine{{Text: "Where is Naruto going?"}, {Text: "Naruto went to Konoha."}}
+	entities := ner.NewScanner().ScanLines(lines)
+	if len(terms) != len(entities) || len(terms) == 0 {
+		t.Fatalf("got %+v, want the scanner entities %+v", terms, entities)
+	}
+	for _, e := range entities {
+		term := (&termbase.Store{Terms: terms}).Find(e.Text)
+		if term == nil || term.Status != termbase.StatusCandidate || term.Episodes["ep01.srt"] != e.Count {
+			t.Errorf("entity %+v should be a candidate, got %+v", e, term)
+		}
+	}
+
+	merged := MergeGlossaries(terms, []Entry{{Original: "naruto", Translation: "Naruto Uzumaki"}})
+	if term := (&termbase.Store{Terms: merged}).Find("Naruto"); len(merged) != len(terms) || term.Translation != "Naruto Uzumaki" {
+		t.Errorf("manual entries should win: %+v", merged)
+	}
+}
@@ -1,9 +1,7 @@
 package job
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -11,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
 	"github.com/lsilvatti/bakasub/internal/locales"
 	"github.com/lsilvatti/bakasub/internal/ui/styles"
 )
@@ -230,13 +229,6 @@ type GlossaryEditor struct {
 	height   int
 }
 
-// GlossaryEntry represents a term in the glossary
-type GlossaryEntry struct {
-	Original    string `json:"original"`
-	Translation string `json:"translation"`
-	Type        string `json:"type"`
-}
-
 // NewGlossaryEditor creates a new glossary editor
 func NewGlossaryEditor(path string, terms map[string]string) *GlossaryEditor {
 	columns := []table.Column{
@@ -285,32 +277,29 @@ func (m GlossaryEditor) View() string {
 	return styles.AppStyle.Render(s.String())
 }
 
-// LoadGlossaryFromFile loads a glossary from a JSON file
+// LoadGlossaryFromFile loads the approved terms of a series glossary
 func LoadGlossaryFromFile(path string) (map[string]string, error) {
-	data, err := os.ReadFile(path)
+	store, err := termbase.Load(path)
 	if err != nil {
 		return nil, err
 	}
-	var entries []GlossaryEntry
-	if err := json.Unmarshal(data, &entries); err != nil {
-		return nil, err
-	}
-	terms := make(map[string]string)
-	for _, entry := range entries {
-		terms[entry.Original] = entry.Translation
-	}
-	return terms, nil
+	return store.Glossary(), nil
 }
 
-// SaveGlossaryToFile saves a glossary to a JSON file
+// SaveGlossaryToFile saves the approved terms of a series glossary, keeping
+// its candidates and rejected terms
 func SaveGlossaryToFile(path string, terms map[string]string) error {
-	entries := []GlossaryEntry{}
-	for original, translation := range terms {
-		entries = append(entries, GlossaryEntry{Original: original, Translation: translation, Type: "noun"})
-	}
-	data, err := json.MarshalIndent(entries, "", "  ")
+	store, err := termbase.Load(path)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	for original := range store.Glossary() {
+		if _, ok := terms[original]; !ok {
+			store.Remove(original)
+		}
+	}
+	for original, translation := range terms {
+		store.Set(original, translation, "")
+	}
+	return store.Save()
 }
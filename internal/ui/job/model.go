@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/catalog"
 	"github.com/lsilvatti/bakasub/internal/core/media"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
+	"github.com/lsilvatti/bakasub/internal/core/tokenizer"
 	"github.com/lsilvatti/bakasub/internal/locales"
 	"github.com/lsilvatti/bakasub/internal/ui/components"
 	"github.com/lsilvatti/bakasub/internal/ui/layout"
@@ -32,6 +36,9 @@ var mediaTypes = []string{"anime", "movie", "series", "documentary", "youtube"}
 // Available mux modes
 var muxModes = []string{"replace", "new-file"}
 
+// lintReportModes are the lint report choices cycled in the job setup
+var lintReportModes = [][]string{nil, {"json"}, {"html"}, {"csv"}, {"json", "html", "csv"}}
+
 type Model struct {
 	cfg    *config.Config
 	width  int
@@ -46,8 +53,9 @@ type Model struct {
 	selectedFileIdx int
 
 	// Interactive selection indices
-	mediaTypeIdx int
-	muxModeIdx   int
+	mediaTypeIdx  int
+	muxModeIdx    int
+	lintReportIdx int
 
 	// Directory detection state
 	showDirModal   bool
@@ -94,15 +102,18 @@ func New(cfg *config.Config, inputPath string) Model {
 		InputPath:       inputPath,
 		TargetLang:      cfg.TargetLang,
 		MediaType:       "anime",
+		ProjectID:       defaultProjectID(inputPath),
 		AIModel:         cfg.Model,
 		Temperature:     cfg.Temperature,
 		RemoveHITags:    true,
 		MuxMode:         "replace",
 		SetDefault:      true,
 		BackupOriginal:  true,
+		LintReports:     cfg.Lint.Reports,
 		ExtractFonts:    true,
 		AutoDetectTrack: true,
 		GlossaryTerms:   make(map[string]string),
+		ModelPricePerM:  tokenizer.NewEstimator().WithCatalog(catalog.Shared(), cfg.AIProvider).PricingFor(cfg.Model).InputPer1M,
 	}
 
 	// Find initial indices for media type and mux mode
@@ -129,6 +140,7 @@ func New(cfg *config.Config, inputPath string) Model {
 		canStart:        false,
 		mediaTypeIdx:    mediaTypeIdx,
 		muxModeIdx:      muxModeIdx,
+		lintReportIdx:   lintReportIdx(jobConfig.LintReports),
 		analysisSpinner: components.NewNeonSpinner(),
 	}
 }
@@ -334,6 +346,15 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.muxModeIdx = (m.muxModeIdx + 1) % len(muxModes)
 			m.jobConfig.MuxMode = muxModes[m.muxModeIdx]
 			return m, nil
+		case msg.String() == "p":
+			// Toggle the LLM glossary pre-pass
+			m.jobConfig.GlossaryPrePass = !m.jobConfig.GlossaryPrePass
+			return m, nil
+		case msg.String() == "l":
+			// Cycle lint report formats
+			m.lintReportIdx = (m.lintReportIdx + 1) % len(lintReportModes)
+			m.jobConfig.LintReports = lintReportModes[m.lintReportIdx]
+			return m, nil
 		}
 	case ViewConflictResolution:
 		if m.conflictModal != nil {
@@ -509,6 +530,35 @@ func (m Model) analyzeFile(path string) (AnalyzedFile, error) {
 	return analyzed, nil
 }
 
+// lintReportIdx finds the lint report mode matching formats (0 = none matches)
+func lintReportIdx(formats []string) int {
+	for i, mode := range lintReportModes {
+		if slices.Equal(mode, formats) {
+			return i
+		}
+	}
+	return 0
+}
+
+// defaultProjectID names the project after the folder holding the input,
+// so episodes of a series kept together share their translation memory
+func defaultProjectID(inputPath string) string {
+	if inputPath == "" {
+		return ""
+	}
+
+	dir := inputPath
+	if info, err := os.Stat(inputPath); err != nil || !info.IsDir() {
+		dir = filepath.Dir(inputPath)
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	return filepath.Base(abs)
+}
+
 func (m Model) estimateCost() tea.Msg {
 	totalChars := len(m.jobConfig.Files) * 10000
 	tokenCount := totalChars / 4
@@ -536,7 +586,7 @@ func (m Model) loadGlossary() tea.Msg {
 		dir = filepath.Dir(dir)
 	}
 
-	glossaryPath := filepath.Join(dir, "glossary.json")
+	glossaryPath := termbase.PathFor(dir)
 	terms, err := LoadGlossaryFromFile(glossaryPath)
 	if err != nil {
 		return MsgGlossaryLoaded{Terms: make(map[string]string), Path: glossaryPath}
@@ -699,6 +749,14 @@ func (m Model) renderMain() string {
 	// Glossary
 	glossaryTerms := locales.Tf("job.translation.glossary_terms", len(m.jobConfig.GlossaryTerms))
 	s.WriteString(fmt.Sprintf("  %s %s\n", locales.T("job.translation.glossary"), glossaryTerms))
+
+	// Glossary pre-pass toggle
+	prePass := locales.T("job.translation.glossary_prepass_off")
+	if m.jobConfig.GlossaryPrePass {
+		prePass = locales.T("job.translation.glossary_prepass_on")
+	}
+	s.WriteString(fmt.Sprintf("  %s %s  ", locales.T("job.translation.glossary_prepass"), styles.AccentStyle.Render("[ "+prePass+" ]")))
+	s.WriteString(styles.KeyHintStyle.Render("[ p ]") + " " + locales.T("job.translation.glossary_prepass_toggle") + "\n")
 	s.WriteString("\n")
 
 	// Section 3: Muxing Output
@@ -708,6 +766,14 @@ func (m Model) renderMain() string {
 	muxModeDisplay := locales.T("job.mux_modes." + m.jobConfig.MuxMode)
 	s.WriteString(fmt.Sprintf("  %s %s  ", locales.T("job.muxing.mode"), styles.AccentStyle.Render("[ "+muxModeDisplay+" ]")))
 	s.WriteString(styles.KeyHintStyle.Render("[ x ]") + " " + locales.T("common.next") + "\n")
+
+	// Lint reports - interactive selector
+	lintReports := locales.T("job.muxing.lint_report_off")
+	if len(m.jobConfig.LintReports) > 0 {
+		lintReports = strings.ToUpper(strings.Join(m.jobConfig.LintReports, " + "))
+	}
+	s.WriteString(fmt.Sprintf("  %s %s  ", locales.T("job.muxing.lint_report"), styles.AccentStyle.Render("[ "+lintReports+" ]")))
+	s.WriteString(styles.KeyHintStyle.Render("[ l ]") + " " + locales.T("common.next") + "\n")
 	s.WriteString("\n")
 
 	// Cost Estimation Box
@@ -1,9 +1,15 @@
 package job
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lsilvatti/bakasub/internal/config"
+	"github.com/lsilvatti/bakasub/internal/core/ner"
+	"github.com/lsilvatti/bakasub/internal/core/termbase"
 )
 
 // TestViewStateConstants tests ViewState constants
@@ -225,3 +231,89 @@ func TestModelStateTransitions(t *testing.T) {
 		t.Error("State should be ViewConflictResolution")
 	}
 }
+
+// TestDefaultProjectID tests naming the project after the input folder
+func TestDefaultProjectID(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "My Show S01")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+
+	if got := defaultProjectID(dir); got != "My Show S01" {
+		t.Errorf("defaultProjectID(dir) = %q, want %q", got, "My Show S01")
+	}
+	if got := defaultProjectID(filepath.Join(dir, "episode01.mkv")); got != "My Show S01" {
+		t.Errorf("defaultProjectID(file) = %q, want %q", got, "My Show S01")
+	}
+	if got := defaultProjectID(""); got != "" {
+		t.Errorf("defaultProjectID(\"\") = %q, want empty", got)
+	}
+}
+
+// TestLintReportCycle tests the lint report default and the l key
+func TestLintReportCycle(t *testing.T) {
+	cfg := config.Default()
+	cfg.Lint.Reports = []string{"html"}
+	m := New(cfg, "/test/video.mkv")
+	if !slices.Equal(m.jobConfig.LintReports, []string{"html"}) {
+		t.Fatalf("LintReports = %v, want the configured formats", m.jobConfig.LintReports)
+	}
+
+	updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	m = updated.(Model)
+	if !slices.Equal(m.jobConfig.LintReports, []string{"csv"}) {
+		t.Errorf("LintReports = %v, want [csv]", m.jobConfig.LintReports)
+	}
+
+	for range 2 {
+		updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+		m = updated.(Model)
+	}
+	if len(m.jobConfig.LintReports) != 0 {
+		t.Errorf("LintReports = %v, want none after a full cycle", m.jobConfig.LintReports)
+	}
+}
+
+// TestGlossaryFileKeepsCandidates tests that jobs only load approved terms and keep candidates on save
+func TestGlossaryFileKeepsCandidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), termbase.FileName)
+	store := &termbase.Store{Path: path}
+	store.Set("Konoha", "Konoha", "Place")
+	store.Set("Sensei", "Mestre", "Title")
+	store.Record("ep01.mkv", []ner.Entity{{Text: "Naruto", Confidence: 0.9, Count: 3}})
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	terms, err := LoadGlossaryFromFile(path)
+	if err != nil || len(terms) != 2 || terms["Sensei"] != "Mestre" {
+		t.Fatalf("LoadGlossaryFromFile = %v, %v; want the approved terms", terms, err)
+	}
+
+	delete(terms, "Sensei")
+	terms["Konoha"] = "Folha"
+	if err := SaveGlossaryToFile(path, terms); err != nil {
+		t.Fatalf("SaveGlossaryToFile failed: %v", err)
+	}
+
+	store, _ = termbase.Load(path)
+	if store.Find("Sensei") != nil || store.Find("Konoha").Translation != "Folha" {
+		t.Errorf("approved terms not saved: %+v", store.Terms)
+	}
+	if naruto := store.Find("Naruto"); naruto == nil || naruto.Status != termbase.StatusCandidate {
+		t.Errorf("candidates should be kept: %+v", store.Terms)
+	}
+}
+
+// TestGlossaryPrePassToggle tests the p key
+func TestGlossaryPrePassToggle(t *testing.T) {
+	m := New(config.Default(), "/test/video.mkv")
+	if m.jobConfig.GlossaryPrePass {
+		t.Fatal("the pre-pass should be off by default")
+	}
+
+	updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if m = updated.(Model); !m.jobConfig.GlossaryPrePass {
+		t.Error("p should turn the pre-pass on")
+	}
+}
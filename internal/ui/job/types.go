@@ -111,10 +111,12 @@ type JobConfig struct {
 
 	// Translation
 	MediaType        string // "anime", "movie", "series", "documentary", "youtube"
+	ProjectID        string // Series/project ID scoping the translation memory (defaults to the folder name)
 	AIModel          string
 	Temperature      float64
 	GlossaryPath     string
 	GlossaryTerms    map[string]string
+	GlossaryPrePass  bool // Ask the model for glossary terms and review them before translating
 	RemoveHITags     bool
 	ContextualPrompt string
 
@@ -124,6 +126,7 @@ type JobConfig struct {
 	SetDefault     bool
 	SetForced      bool
 	BackupOriginal bool
+	LintReports    []string // Lint report formats written next to each output file
 
 	// Cost
 	EstimatedChars  int
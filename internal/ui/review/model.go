@@ -3,11 +3,15 @@ package review
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
 	"github.com/lsilvatti/bakasub/internal/core/parser"
 	"github.com/lsilvatti/bakasub/internal/locales"
 	"github.com/lsilvatti/bakasub/internal/ui/focus"
@@ -18,13 +22,28 @@ import (
 // ClosedMsg is sent when the review editor should be closed
 type ClosedMsg struct{}
 
+// SavedMsg is sent after the file was written and edits were recorded
+type SavedMsg struct {
+	Approved   int      // Translation memory entries marked as approved
+	Unrecorded int      // Edits the translation memory could not record
+	texts      []string // Translated texts as written to disk
+}
+
 type Model struct {
 	originalLines   []parser.SubtitleLine
 	translatedLines []parser.SubtitleLine
+	savedTexts      []string // Translated texts as last written, to detect edits
+	separateOrigin  bool     // Whether originalLines come from a different file
+	cache           *db.Cache
+	scope           db.Scope // Translation memory scope the lines were translated in
+	approved        int
+	unrecorded      int
 	currentIndex    int
 	editor          textarea.Model
 	focusManager    *focus.Manager
-	filePath        string
+	filePath        string                 // Empty when editing lines in memory
+	issues          map[int][]linter.Issue // Lint issues by line index
+	flagged         []int                  // Indexes of lines with issues, navigated instead of all lines
 	saved           bool
 	width           int
 	height          int
@@ -49,17 +68,37 @@ func New(originalPath, translatedPath string) (*Model, error) {
 		}
 	}
 
+	m := newModel(origFile.Lines, transFile.Lines, translatedPath)
+	m.separateOrigin = transFile != origFile
+	return m, nil
+}
+
+// NewFromLines edits translated lines in memory, next to their originals.
+// Saving records the edits in the translation memory without writing a file;
+// Lines returns the result.
+func NewFromLines(original, translated []parser.SubtitleLine) *Model {
+	lines := make([]parser.SubtitleLine, len(translated))
+	copy(lines, translated)
+
+	m := newModel(original, lines, "")
+	m.separateOrigin = true
+	return m
+}
+
+// newModel creates an editor positioned on the first line
+func newModel(original, translated []parser.SubtitleLine, filePath string) *Model {
 	ta := textarea.New()
 	ta.Focus()
 	ta.SetHeight(5)
 
 	m := &Model{
-		originalLines:   origFile.Lines,
-		translatedLines: transFile.Lines,
+		originalLines:   original,
+		translatedLines: translated,
 		currentIndex:    0,
 		editor:          ta,
 		focusManager:    focus.NewManager(1), // 1 text area field
-		filePath:        translatedPath,
+		savedTexts:      lineTexts(translated),
+		filePath:        filePath,
 		saved:           true,
 	}
 
@@ -70,7 +109,54 @@ func New(originalPath, translatedPath string) (*Model, error) {
 		m.editor.SetValue(m.translatedLines[0].Text)
 	}
 
-	return m, nil
+	return m
+}
+
+// SetIssues limits navigation to the lines with lint issues and shows them
+// next to the original. LineID is the 1-based position of the line.
+func (m *Model) SetIssues(issues []linter.Issue) {
+	m.issues = make(map[int][]linter.Issue)
+	m.flagged = nil
+	for _, issue := range issues {
+		i := issue.LineID - 1
+		if i < 0 || i >= len(m.translatedLines) {
+			continue
+		}
+		if _, seen := m.issues[i]; !seen {
+			m.flagged = append(m.flagged, i)
+		}
+		m.issues[i] = append(m.issues[i], issue)
+	}
+	sort.Ints(m.flagged)
+
+	if len(m.flagged) > 0 {
+		m.currentIndex = m.flagged[0]
+		m.editor.SetValue(m.translatedLines[m.currentIndex].Text)
+	}
+}
+
+// Lines returns the translated lines with the edits made so far
+func (m Model) Lines() []parser.SubtitleLine {
+	lines := make([]parser.SubtitleLine, len(m.translatedLines))
+	copy(lines, m.translatedLines)
+	return lines
+}
+
+// SetCache enables writing edited lines back to the translation memory as
+// approved, in the scope of the job that translated them. Edits need their
+// original from a separate file; the others are reported as unrecorded.
+func (m *Model) SetCache(cache *db.Cache, scope db.Scope) {
+	m.cache = cache
+	m.scope = scope
+}
+
+// lineTexts copies the text of each line
+func lineTexts(lines []parser.SubtitleLine) []string {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = line.Text
+	}
+	return texts
 }
 
 // SetSize updates the model dimensions
@@ -89,6 +175,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case SavedMsg:
+		m.saved = true
+		m.savedTexts = msg.texts
+		m.approved += msg.Approved
+		m.unrecorded += msg.Unrecorded
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -137,6 +230,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) nextLine() {
+	if len(m.flagged) > 0 {
+		if pos := m.flaggedPos(); pos < len(m.flagged)-1 {
+			m.currentIndex = m.flagged[pos+1]
+			m.editor.SetValue(m.translatedLines[m.currentIndex].Text)
+		}
+		return
+	}
 	if m.currentIndex < len(m.translatedLines)-1 {
 		m.currentIndex++
 		m.editor.SetValue(m.translatedLines[m.currentIndex].Text)
@@ -144,19 +244,60 @@ func (m *Model) nextLine() {
 }
 
 func (m *Model) prevLine() {
+	if len(m.flagged) > 0 {
+		if pos := m.flaggedPos(); pos > 0 {
+			m.currentIndex = m.flagged[pos-1]
+			m.editor.SetValue(m.translatedLines[m.currentIndex].Text)
+		}
+		return
+	}
 	if m.currentIndex > 0 {
 		m.currentIndex--
 		m.editor.SetValue(m.translatedLines[m.currentIndex].Text)
 	}
 }
 
+// flaggedPos returns the position of the current line among the flagged lines
+func (m Model) flaggedPos() int {
+	return sort.SearchInts(m.flagged, m.currentIndex)
+}
+
 func (m Model) saveFile() tea.Cmd {
+	lines := make([]parser.SubtitleLine, len(m.translatedLines))
+	copy(lines, m.translatedLines)
+	previous := m.savedTexts
+
+	var originals []string
+	if m.separateOrigin {
+		originals = lineTexts(m.originalLines)
+	}
+
 	return func() tea.Msg {
-		content := parser.ReassembleSRT(m.translatedLines)
-		if err := os.WriteFile(m.filePath, []byte(content), 0644); err != nil {
-			return err
+		if m.filePath != "" {
+			content := parser.ReassembleSRT(lines)
+			if err := os.WriteFile(m.filePath, []byte(content), 0644); err != nil {
+				return err
+			}
+		}
+
+		msg := SavedMsg{texts: lineTexts(lines)}
+		if m.cache == nil {
+			return msg
 		}
-		return nil
+
+		// Human edits become the approved translation of their original
+		for i, line := range lines {
+			if i >= len(previous) || line.Text == previous[i] {
+				continue
+			}
+			if i >= len(originals) || m.cache.ApproveEdit(originals[i], line.Text, m.scope) != nil {
+				msg.Unrecorded++
+				continue
+			}
+			msg.Approved++
+		}
+
+		return msg
 	}
 }
 
@@ -201,6 +342,14 @@ func (m Model) View() string {
 			orig.StartTime,
 			orig.Text)
 	}
+	if notes := m.issues[m.currentIndex]; len(notes) > 0 {
+		var b strings.Builder
+		b.WriteString("\n\n" + styles.SectionStyle.Render(locales.T("review.issues")))
+		for _, issue := range notes {
+			b.WriteString(fmt.Sprintf("\n[%s] %s: %s", issue.Severity, issue.IssueType, issue.Suggestion))
+		}
+		originalText += b.String()
+	}
 
 	leftPanel := leftStyle.Render(
 		styles.TitleStyle.Render(locales.T("review.original_readonly")) + "\n\n" +
@@ -220,9 +369,16 @@ func (m Model) View() string {
 
 	if !m.saved {
 		footer += " " + styles.WarningStyle.Render(locales.T("review.modified"))
+	} else if m.unrecorded > 0 {
+		footer += " " + styles.WarningStyle.Render(locales.Tf("review.unrecorded", m.unrecorded))
+	} else if m.approved > 0 {
+		footer += " " + styles.Dimmed.Render(locales.Tf("review.approved", m.approved))
 	}
 
 	progress := locales.Tf("review.progress_simple", m.currentIndex+1, len(m.translatedLines))
+	if len(m.flagged) > 0 {
+		progress = locales.Tf("review.progress_flagged", m.flaggedPos()+1, len(m.flagged), m.currentIndex+1)
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left,
 		styles.TitleStyle.Render(locales.T("review.editor_title")),
@@ -1,7 +1,12 @@
 package review
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/linter"
 )
 
 // TestClosedMsgStruct tests ClosedMsg structure
@@ -167,3 +172,180 @@ func TestModelZeroValue(t *testing.T) {
 		t.Error("height should be 0 for zero value")
 	}
 }
+
+// TestSaveFileApprovesEdits tests that saved edits are written back to the translation memory
+func TestSaveFileApprovesEdits(t *testing.T) {
+	dir := t.TempDir()
+	original := filepath.Join(dir, "episode.en.srt")
+	translated := filepath.Join(dir, "episode.pt.srt")
+	os.WriteFile(original, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nBye\n"), 0644)
+	os.WriteFile(translated, []byte("1\n00:00:01,000 --> 00:00:02,000\nOlá\n\n2\n00:00:03,000 --> 00:00:04,000\nTchau\n"), 0644)
+
+	cache, err := db.Open(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("db.Open failed: %v", err)
+	}
+	defer cache.Close()
+	scope := db.Scope{LangPair: "en->pt"}
+	cache.SaveScopedTranslation("Hello", "Olá", scope)
+	cache.SaveScopedTranslation("Bye", "Tchau", scope)
+
+	m, err := New(original, translated)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	m.SetCache(cache, scope)
+	m.translatedLines[0].Text = "Oi"
+
+	msg, ok := m.saveFile()().(SavedMsg)
+	if !ok {
+		t.Fatal("expected SavedMsg")
+	}
+	if msg.Approved != 1 {
+		t.Errorf("Approved = %d, want 1", msg.Approved)
+	}
+
+	entry, found := cache.GetScopedMatch("Hello", scope, db.PolicyStrict)
+	if !found || entry.TranslatedText != "Oi" || !entry.IsApproved() {
+		t.Errorf("got %+v, want approved Oi", entry)
+	}
+
+	// Saving again without further edits approves nothing new
+	updated, _ := m.Update(msg)
+	m2 := updated.(Model)
+	if !m2.saved {
+		t.Error("model should be marked saved")
+	}
+	if again := m2.saveFile()().(SavedMsg); again.Approved != 0 {
+		t.Errorf("second save Approved = %d, want 0", again.Approved)
+	}
+}
+
+// TestSaveFileSingleFileSkipsMemory tests that edits without a separate original leave the memory alone
+func TestSaveFileSingleFileSkipsMemory(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "episode.pt.srt")
+	os.WriteFile(path, []byte("1\n00:00:01,000 --> 00:00:02,000\nSim.\n"), 0644)
+
+	cache, err := db.Open(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("db.Open failed: %v", err)
+	}
+	defer cache.Close()
+	scope := db.Scope{LangPair: "en->pt"}
+	cache.SaveScopedTranslation("Yes.", "Sim.", scope)
+
+	m, err := New(path, "")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	m.SetCache(cache, scope)
+	m.translatedLines[0].Text = "Isso."
+
+	msg, ok := m.saveFile()().(SavedMsg)
+	if !ok || msg.Approved != 0 || msg.Unrecorded != 1 {
+		t.Fatalf("expected one unrecorded edit, got %+v", msg)
+	}
+	entry, found := cache.GetScopedMatch("Yes.", scope, db.PolicyStrict)
+	if !found || entry.TranslatedText != "Sim." || entry.IsApproved() {
+		t.Errorf("got %+v, want untouched machine entry", entry)
+	}
+
+	// The editor warns that the edit was not recorded
+	updated, _ := m.Update(msg)
+	if m2 := updated.(Model); m2.unrecorded != 1 {
+		t.Errorf("unrecorded = %d, want 1", m2.unrecorded)
+	}
+}
+
+// TestSaveFileRecordsNormalizedLine tests that editing a line whose text differs
+// from the cached one, as after typography normalization, is still recorded
+func TestSaveFileRecordsNormalizedLine(t *testing.T) {
+	dir := t.TempDir()
+	original := filepath.Join(dir, "episode.en.srt")
+	translated := filepath.Join(dir, "episode.pt.srt")
+	os.WriteFile(original, []byte("1\n00:00:01,000 --> 00:00:02,000\nHe said \"hi\"...\n"), 0644)
+	os.WriteFile(translated, []byte("1\n00:00:01,000 --> 00:00:02,000\nEle disse “oi”…\n"), 0644)
+
+	cache, err := db.Open(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("db.Open failed: %v", err)
+	}
+	defer cache.Close()
+	scope := db.Scope{LangPair: "en->pt"}
+	cache.SaveScopedTranslation(`He said "hi"...`, `Ele disse "oi"...`, scope)
+
+	m, err := New(original, translated)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	m.SetCache(cache, scope)
+	m.translatedLines[0].Text = "Ele disse “olá”…"
+
+	msg, ok := m.saveFile()().(SavedMsg)
+	if !ok || msg.Approved != 1 || msg.Unrecorded != 0 {
+		t.Fatalf("expected one approved edit, got %+v", msg)
+	}
+	entry, found := cache.GetScopedMatch(`He said "hi"...`, scope, db.PolicyStrict)
+	if !found || entry.TranslatedText != "Ele disse “olá”…" || !entry.IsApproved() {
+		t.Errorf("got %+v, want the approved correction", entry)
+	}
+}
+
+// TestSetIssuesNavigatesFlaggedLines tests that only lines with issues are visited
+func TestSetIssuesNavigatesFlaggedLines(t *testing.T) {
+	original := linter.TextLines([]string{"One", "Two", "Three", "Four"})
+	m := NewFromLines(original, linter.TextLines([]string{"Um", "Two", "Três", "Four"}))
+	m.SetIssues([]linter.Issue{
+		{LineID: 4, Severity: linter.SeverityHigh, IssueType: "Untranslated"},
+		{LineID: 2, Severity: linter.SeverityHigh, IssueType: "Untranslated"},
+		{LineID: 2, Severity: linter.SeverityMedium, IssueType: "Source Residue"},
+	})
+
+	if m.currentIndex != 1 || m.editor.Value() != "Two" {
+		t.Fatalf("editor should start on the first flagged line, got %d", m.currentIndex)
+	}
+	if len(m.issues[1]) != 2 {
+		t.Errorf("issues of a line should be grouped, got %+v", m.issues)
+	}
+
+	m.nextLine()
+	if m.currentIndex != 3 {
+		t.Errorf("nextLine should skip clean lines, got %d", m.currentIndex)
+	}
+	m.nextLine()
+	if m.currentIndex != 3 {
+		t.Errorf("nextLine should stop at the last flagged line, got %d", m.currentIndex)
+	}
+	m.prevLine()
+	if m.currentIndex != 1 {
+		t.Errorf("prevLine should skip clean lines, got %d", m.currentIndex)
+	}
+}
+
+// TestNewFromLinesSavesWithoutFile tests in-memory editing
+func TestNewFromLinesSavesWithoutFile(t *testing.T) {
+	cache, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.Open failed: %v", err)
+	}
+	defer cache.Close()
+	scope := db.Scope{LangPair: "en->pt"}
+	cache.SaveScopedTranslation("Hello", "Hello", scope)
+
+	translated := linter.TextLines([]string{"Hello"})
+	m := NewFromLines(linter.TextLines([]string{"Hello"}), translated)
+	m.SetCache(cache, scope)
+	m.translatedLines[0].Text = "Olá"
+
+	msg, ok := m.saveFile()().(SavedMsg)
+	if !ok || msg.Approved != 1 {
+		t.Fatalf("expected one approved edit, got %+v", msg)
+	}
+	if translated[0].Text != "Hello" {
+		t.Error("NewFromLines should not modify its input")
+	}
+	if lines := m.Lines(); lines[0].Text != "Olá" {
+		t.Errorf("Lines() = %+v, want the edit", lines)
+	}
+}
@@ -3,6 +3,7 @@ package settings
 import (
 	"context"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -11,6 +12,10 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lsilvatti/bakasub/internal/config"
 	"github.com/lsilvatti/bakasub/internal/core/ai"
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/core/exchange"
+	"github.com/lsilvatti/bakasub/internal/core/pipeline"
+	"github.com/lsilvatti/bakasub/internal/core/tm"
 	"github.com/lsilvatti/bakasub/internal/locales"
 	"github.com/lsilvatti/bakasub/internal/ui/components/langselector"
 	"github.com/lsilvatti/bakasub/internal/ui/components/modelselect"
@@ -34,6 +39,18 @@ type (
 		models []modelselect.ModelInfo
 		err    error
 	}
+
+	// tmExchangeMsg is sent when a translation memory import/export finishes
+	tmExchangeMsg struct {
+		status string
+		err    error
+	}
+
+	// evictMsg is sent when a manual cache eviction finishes
+	evictMsg struct {
+		status string
+		err    error
+	}
 )
 
 // Tab represents a settings tab
@@ -95,6 +112,12 @@ type Model struct {
 
 	// Advanced tab
 	selectedLogLevel int // 0=info, 1=debug
+	tmPathInput      textinput.Model
+	tmConflict       exchange.ConflictPolicy
+	tmStatus         string
+	tmError          bool
+	evictStatus      string
+	evictError       bool
 
 	// State
 	saved    bool
@@ -215,6 +238,13 @@ func New(cfg *config.Config) Model {
 	temperatureInput.CharLimit = 5
 	temperatureInput.Width = 10
 
+	// Create translation memory exchange file input
+	tmPathInput := textinput.New()
+	tmPathInput.Placeholder = "memory.tmx"
+	tmPathInput.CharLimit = 255
+	tmPathInput.Width = 54
+	tmPathInput.SetValue("bakasub-memory.tmx")
+
 	return Model{
 		width:              0, // Will be set by WindowSizeMsg
 		height:             0, // Will be set by WindowSizeMsg
@@ -234,6 +264,8 @@ func New(cfg *config.Config) Model {
 		promptInput:        promptInput,
 		profileNameInput:   profileNameInput,
 		temperatureInput:   temperatureInput,
+		tmPathInput:        tmPathInput,
+		tmConflict:         exchange.ConflictPreferApproved,
 	}
 }
 
@@ -278,6 +310,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case tmExchangeMsg:
+		m.tmError = msg.err != nil
+		if msg.err != nil {
+			m.tmStatus = msg.err.Error()
+		} else {
+			m.tmStatus = msg.status
+		}
+		return m, nil
+
+	case evictMsg:
+		m.evictError = msg.err != nil
+		if msg.err != nil {
+			m.evictStatus = msg.err.Error()
+		} else {
+			m.evictStatus = msg.status
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		key := msg.String()
 
@@ -394,6 +444,7 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.promptInput.Blur()
 		m.profileNameInput.Blur()
 		m.temperatureInput.Blur()
+		m.tmPathInput.Blur()
 		m.editingPrompt = false
 		m.editingProfileName = false
 		m.targetLangSelector.BlurCustomInput()
@@ -426,6 +477,7 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.promptInput.Blur()
 		m.profileNameInput.Blur()
 		m.temperatureInput.Blur()
+		m.tmPathInput.Blur()
 		m.editingPrompt = false
 		m.editingProfileName = false
 		m.targetLangSelector.BlurCustomInput()
@@ -479,6 +531,11 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, cmd
 		}
+	case TabAdvanced:
+		if m.tmPathInput.Focused() {
+			m.tmPathInput, cmd = m.tmPathInput.Update(msg)
+			return m, cmd
+		}
 	}
 
 	return m, cmd
@@ -754,6 +811,36 @@ func (m Model) handleTabNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case " ":
 			// Toggle auto update check
 			m.config.AutoCheckUpdates = !m.config.AutoCheckUpdates
+		case "p":
+			// Cycle translation memory lookup policy
+			m.config.Cache.LookupPolicy = nextLookupPolicy(m.config.Cache.LookupPolicy)
+		case "+", "=":
+			m.config.Cache.FuzzyThreshold = adjustFuzzyThreshold(m.config.Cache.FuzzyThreshold, 0.01)
+		case "-":
+			m.config.Cache.FuzzyThreshold = adjustFuzzyThreshold(m.config.Cache.FuzzyThreshold, -0.01)
+		case "f":
+			// Edit the translation memory exchange file
+			m.focusManager.EnterInput(0)
+			m.tmPathInput.Focus()
+			return m, textinput.Blink
+		case "c":
+			// Cycle import conflict policy
+			m.tmConflict = m.tmConflict.Next()
+		case "x":
+			return m, m.exportMemoryCmd()
+		case "i":
+			return m, m.importMemoryCmd()
+		case "e":
+			// Cycle the cache entry limit
+			m.config.Cache.MaxEntries = nextLimit(cacheEntryLimits, m.config.Cache.MaxEntries)
+		case "z":
+			// Cycle the cache size limit
+			m.config.Cache.MaxSizeMB = nextLimit(cacheSizeLimits, m.config.Cache.MaxSizeMB)
+		case "l":
+			// Toggle the eviction policy
+			m.config.Cache.EvictionPolicy = nextEvictionPolicy(m.config.Cache.EvictionPolicy)
+		case "v":
+			return m, m.evictCacheCmd()
 		}
 	}
 
@@ -1181,6 +1268,123 @@ func (m Model) renderPromptsTab(panelWidth int) string {
 	return styles.Panel.Width(panelWidth).BorderForeground(styles.Yellow).Render(content)
 }
 
+// cacheLookupPolicies lists the translation memory lookup policies in cycle order
+var cacheLookupPolicies = []db.LookupPolicy{db.PolicyStrict, db.PolicySameProject, db.PolicyAny}
+
+// nextLookupPolicy returns the policy after the current one
+func nextLookupPolicy(current string) string {
+	policy := db.ParseLookupPolicy(current)
+	for i, p := range cacheLookupPolicies {
+		if p == policy {
+			return string(cacheLookupPolicies[(i+1)%len(cacheLookupPolicies)])
+		}
+	}
+	return string(db.PolicyStrict)
+}
+
+// Fuzzy match threshold range; 1.00 reuses exact matches only
+const (
+	minFuzzyThreshold = 0.70
+	maxFuzzyThreshold = 1.00
+)
+
+// adjustFuzzyThreshold moves the threshold by delta, clamped to the allowed range
+func adjustFuzzyThreshold(current, delta float64) float64 {
+	if current <= 0 {
+		current = db.DefaultFuzzyThreshold
+	}
+	// Round to whole percent so repeated steps don't drift
+	next := math.Round((current+delta)*100) / 100
+	return math.Max(minFuzzyThreshold, math.Min(maxFuzzyThreshold, next))
+}
+
+// Cache limit presets in cycle order (0 = unlimited)
+var (
+	cacheEntryLimits = []int{0, 10000, 50000, 100000, 500000}
+	cacheSizeLimits  = []int{0, 100, 250, 512, 1024, 2048}
+)
+
+// nextLimit returns the preset after current, or the first one above it for custom values
+func nextLimit(presets []int, current int) int {
+	for _, p := range presets {
+		if p > current {
+			return p
+		}
+	}
+	return presets[0]
+}
+
+// nextEvictionPolicy toggles between LRU and LFU
+func nextEvictionPolicy(current string) string {
+	if db.ParseEvictionPolicy(current) == db.EvictLRU {
+		return string(db.EvictLFU)
+	}
+	return string(db.EvictLRU)
+}
+
+// formatLimit renders a limit value, or "unlimited" for 0
+func formatLimit(value int, unit string) string {
+	if value <= 0 {
+		return locales.T("settings.advanced.cache_unlimited")
+	}
+	return fmt.Sprintf("%d%s", value, unit)
+}
+
+// evictCacheCmd applies the configured cache limits now
+func (m Model) evictCacheCmd() tea.Cmd {
+	settings := m.config.Cache
+	return func() tea.Msg {
+		cache, err := db.GetInstance("")
+		if err != nil {
+			return evictMsg{err: err}
+		}
+
+		report, err := pipeline.EnforceCacheLimits(cache, settings)
+		if err != nil {
+			return evictMsg{err: err}
+		}
+		if report == nil {
+			return evictMsg{status: locales.T("settings.advanced.cache_no_limits")}
+		}
+		return evictMsg{status: report.String()}
+	}
+}
+
+// exportMemoryCmd exports the whole translation memory to the exchange file
+func (m Model) exportMemoryCmd() tea.Cmd {
+	path := strings.TrimSpace(m.tmPathInput.Value())
+	return func() tea.Msg {
+		cache, err := db.GetInstance("")
+		if err != nil {
+			return tmExchangeMsg{err: err}
+		}
+
+		n, err := tm.Export(cache, path, "", db.EntryFilter{}, "")
+		if err != nil {
+			return tmExchangeMsg{err: err}
+		}
+		return tmExchangeMsg{status: locales.Tf("settings.advanced.tm_exported", n, path)}
+	}
+}
+
+// importMemoryCmd merges the exchange file into the translation memory
+func (m Model) importMemoryCmd() tea.Cmd {
+	path := strings.TrimSpace(m.tmPathInput.Value())
+	policy := m.tmConflict
+	return func() tea.Msg {
+		cache, err := db.GetInstance("")
+		if err != nil {
+			return tmExchangeMsg{err: err}
+		}
+
+		result, err := tm.Import(cache, path, "", db.EntryFilter{}, policy)
+		if err != nil {
+			return tmExchangeMsg{err: err}
+		}
+		return tmExchangeMsg{status: locales.Tf("settings.advanced.tm_imported", result.Added, result.Updated, result.Skipped, len(result.Conflicts))}
+	}
+}
+
 func (m Model) renderAdvancedTab(panelWidth int) string {
 	logLevels := []string{"INFO (Default)", "DEBUG (Verbose)"}
 	var logList strings.Builder
@@ -1197,6 +1401,30 @@ func (m Model) renderAdvancedTab(panelWidth int) string {
 		updatesCheck = "[X]"
 	}
 
+	policy := db.ParseLookupPolicy(m.config.Cache.LookupPolicy)
+	fuzzyThreshold := m.config.Cache.FuzzyThreshold
+	if fuzzyThreshold <= 0 {
+		fuzzyThreshold = db.DefaultFuzzyThreshold
+	}
+
+	tmStatus := "   " + styles.Dimmed.Render(locales.T("settings.advanced.tm_formats"))
+	if m.tmStatus != "" {
+		if m.tmError {
+			tmStatus = "   " + styles.StatusError.Render(m.tmStatus)
+		} else {
+			tmStatus = "   " + styles.StatusOK.Render(m.tmStatus)
+		}
+	}
+
+	evictStatus := "   " + styles.Dimmed.Render(locales.T("settings.advanced.cache_limits_hint"))
+	if m.evictStatus != "" {
+		if m.evictError {
+			evictStatus = "   " + styles.StatusError.Render(m.evictStatus)
+		} else {
+			evictStatus = "   " + styles.StatusOK.Render(m.evictStatus)
+		}
+	}
+
 	content := lipgloss.JoinVertical(
 		lipgloss.Left,
 		styles.PanelTitle.Render(locales.T("settings.advanced.log_level")),
@@ -1205,6 +1433,33 @@ func (m Model) renderAdvancedTab(panelWidth int) string {
 		styles.PanelTitle.Render(locales.T("settings.advanced.updates")),
 		fmt.Sprintf("   %s %s  %s", updatesCheck, locales.T("settings.advanced.auto_check"), styles.KeyHintStyle.Render("[SPACE]")),
 		"",
+		styles.PanelTitle.Render(locales.T("settings.advanced.cache_policy")),
+		fmt.Sprintf("   < %s >  %s", strings.ToUpper(string(policy)), styles.KeyHintStyle.Render("[P]")),
+		"   "+styles.Dimmed.Render(locales.T("settings.advanced.cache_policy_"+string(policy))),
+		"",
+		styles.PanelTitle.Render(locales.T("settings.advanced.fuzzy_threshold")),
+		fmt.Sprintf("   < %.0f%% >  %s", fuzzyThreshold*100, styles.KeyHintStyle.Render("[+/-]")),
+		"   "+styles.Dimmed.Render(locales.T("settings.advanced.fuzzy_threshold_hint")),
+		"",
+		styles.PanelTitle.Render(locales.T("settings.advanced.tm_exchange")),
+		fmt.Sprintf("   %s %s  %s", locales.T("settings.advanced.tm_file"), m.tmPathInput.View(), styles.KeyHintStyle.Render("[F]")),
+		fmt.Sprintf("   %s < %s >  %s", locales.T("settings.advanced.tm_conflict"),
+			locales.T("settings.advanced.tm_conflict_"+string(m.tmConflict)), styles.KeyHintStyle.Render("[C]")),
+		fmt.Sprintf("   %s %s  %s %s",
+			styles.KeyHintStyle.Render("[X]"), locales.T("settings.advanced.tm_export"),
+			styles.KeyHintStyle.Render("[I]"), locales.T("settings.advanced.tm_import")),
+		tmStatus,
+		"",
+		styles.PanelTitle.Render(locales.T("settings.advanced.cache_limits")),
+		fmt.Sprintf("   %s < %s >  %s", locales.T("settings.advanced.cache_max_entries"),
+			formatLimit(m.config.Cache.MaxEntries, ""), styles.KeyHintStyle.Render("[E]")),
+		fmt.Sprintf("   %s < %s >  %s", locales.T("settings.advanced.cache_max_size"),
+			formatLimit(m.config.Cache.MaxSizeMB, " MB"), styles.KeyHintStyle.Render("[Z]")),
+		fmt.Sprintf("   %s < %s >  %s", locales.T("settings.advanced.cache_eviction"),
+			strings.ToUpper(string(db.ParseEvictionPolicy(m.config.Cache.EvictionPolicy))), styles.KeyHintStyle.Render("[L]")),
+		fmt.Sprintf("   %s %s", styles.KeyHintStyle.Render("[V]"), locales.T("settings.advanced.cache_evict_now")),
+		evictStatus,
+		"",
 		styles.PanelTitle.Render(locales.T("settings.advanced.system_info")),
 		"   VERSION: v1.0.0",
 		"   GO VERSION: 1.24.0",
@@ -1235,9 +1490,6 @@ func (m Model) fetchModelsCmd() tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
 
-		var provider ai.LLMProvider
-		var err error
-
 		switch m.selectedProvider {
 		case 0: // OpenRouter
 			apiKey := m.apiKeyInput.Value()
@@ -1248,16 +1500,10 @@ func (m Model) fetchModelsCmd() tea.Cmd {
 				// Return sample data if no API key
 				return modelsLoadedMsg{models: getSampleModels(), err: nil}
 			}
-			provider = ai.NewOpenRouterAdapter(apiKey, "", 0.3)
-			modelStrings, err := provider.ListModels(ctx)
+			models, err := modelselect.LoadFromCatalog(ctx, "openrouter", ai.NewOpenRouterAdapter(apiKey, "", 0.3))
 			if err != nil {
 				return modelsLoadedMsg{models: getSampleModels(), err: nil}
 			}
-			models := []modelselect.ModelInfo{}
-			for _, model := range modelStrings {
-				isFree := strings.Contains(strings.ToLower(model), ":free")
-				models = append(models, parseModelToModelInfo(model, isFree))
-			}
 			return modelsLoadedMsg{models: models, err: nil}
 
 		case 1: // Gemini
@@ -1265,25 +1511,28 @@ func (m Model) fetchModelsCmd() tea.Cmd {
 			if apiKey == "" {
 				apiKey = m.config.APIKey
 			}
-			provider, err = ai.NewGeminiAdapter(ctx, apiKey, "", 0.3)
+			provider, err := ai.NewGeminiAdapter(ctx, apiKey, "", 0.3)
 			if err != nil {
 				return modelsLoadedMsg{models: nil, err: err}
 			}
-			modelStrings, err := provider.ListModels(ctx)
+			models, err := modelselect.LoadFromCatalog(ctx, "gemini", provider)
 			if err != nil {
 				return modelsLoadedMsg{models: nil, err: err}
 			}
-			models := []modelselect.ModelInfo{}
-			for _, model := range modelStrings {
-				models = append(models, parseModelToModelInfo(model, false))
-			}
 			return modelsLoadedMsg{models: models, err: nil}
 
 		case 2: // OpenAI
-			modelStrings := []string{"gpt-4o", "gpt-4o-mini", "gpt-4-turbo", "gpt-3.5-turbo"}
-			models := []modelselect.ModelInfo{}
-			for _, model := range modelStrings {
-				models = append(models, parseModelToModelInfo(model, false))
+			apiKey := m.apiKeyInput.Value()
+			if apiKey == "" {
+				apiKey = m.config.APIKey
+			}
+			models, err := modelselect.LoadFromCatalog(ctx, "openai", ai.NewOpenAIAdapter(apiKey, "", 0.3))
+			if err != nil {
+				// Fallback to static list if API fails
+				models = []modelselect.ModelInfo{}
+				for _, model := range []string{"gpt-4o", "gpt-4o-mini", "gpt-4-turbo", "gpt-3.5-turbo"} {
+					models = append(models, parseModelToModelInfo(model, false))
+				}
 			}
 			return modelsLoadedMsg{models: models, err: nil}
 
@@ -1295,16 +1544,12 @@ func (m Model) fetchModelsCmd() tea.Cmd {
 			if endpoint == "" {
 				endpoint = "http://localhost:11434"
 			}
-			provider = ai.NewLocalLLMAdapter(endpoint, "", 0.3)
-			modelStrings, err := provider.ListModels(ctx)
+			// Installed local models change often, so they bypass the catalog cache
+			infos, err := ai.NewLocalLLMAdapter(endpoint, "", 0.3).ListModelInfo(ctx)
 			if err != nil {
 				return modelsLoadedMsg{models: nil, err: err}
 			}
-			models := []modelselect.ModelInfo{}
-			for _, model := range modelStrings {
-				models = append(models, parseModelToModelInfo(model, true))
-			}
-			return modelsLoadedMsg{models: models, err: nil}
+			return modelsLoadedMsg{models: modelselect.FromCatalog(infos), err: nil}
 		}
 
 		return modelsLoadedMsg{models: nil, err: fmt.Errorf("provider not configured")}
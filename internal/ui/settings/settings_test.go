@@ -1,8 +1,10 @@
 package settings
 
 import (
+	"fmt"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lsilvatti/bakasub/internal/config"
 )
 
@@ -240,3 +242,94 @@ func TestErrorState(t *testing.T) {
 		t.Errorf("errMsg = %q, want test error message", model.errMsg)
 	}
 }
+
+// TestNextLookupPolicy tests cycling the translation memory policy
+func TestNextLookupPolicy(t *testing.T) {
+	tests := map[string]string{
+		"strict":       "same_project",
+		"same_project": "any",
+		"any":          "strict",
+		"":             "same_project", // Unset means strict
+	}
+
+	for current, want := range tests {
+		if got := nextLookupPolicy(current); got != want {
+			t.Errorf("nextLookupPolicy(%q) = %q, want %q", current, got, want)
+		}
+	}
+}
+
+// TestTMExchangeStatus tests that import/export results are shown
+func TestTMExchangeStatus(t *testing.T) {
+	m := New(config.Default())
+
+	updated, _ := m.Update(tmExchangeMsg{status: "done"})
+	m = updated.(Model)
+	if m.tmStatus != "done" || m.tmError {
+		t.Errorf("status = %q (error %v), want done", m.tmStatus, m.tmError)
+	}
+
+	updated, _ = m.Update(tmExchangeMsg{err: fmt.Errorf("boom")})
+	m = updated.(Model)
+	if m.tmStatus != "boom" || !m.tmError {
+		t.Errorf("status = %q (error %v), want boom error", m.tmStatus, m.tmError)
+	}
+}
+
+// TestAdjustFuzzyThreshold tests stepping and clamping the fuzzy threshold
+func TestAdjustFuzzyThreshold(t *testing.T) {
+	tests := []struct {
+		current, delta, want float64
+	}{
+		{0.95, 0.01, 0.96},
+		{0.95, -0.01, 0.94},
+		{1.00, 0.01, 1.00},
+		{0.70, -0.01, 0.70},
+		{0, 0.01, 0.96}, // Unset means the default
+	}
+
+	for _, tt := range tests {
+		if got := adjustFuzzyThreshold(tt.current, tt.delta); got != tt.want {
+			t.Errorf("adjustFuzzyThreshold(%v, %v) = %v, want %v", tt.current, tt.delta, got, tt.want)
+		}
+	}
+}
+
+// TestNextLimit tests cycling the cache limit presets
+func TestNextLimit(t *testing.T) {
+	tests := []struct {
+		current, want int
+	}{
+		{0, 100},
+		{512, 1024},
+		{2048, 0},
+		{300, 512}, // Custom values move to the next preset
+	}
+
+	for _, tt := range tests {
+		if got := nextLimit(cacheSizeLimits, tt.current); got != tt.want {
+			t.Errorf("nextLimit(%d) = %d, want %d", tt.current, got, tt.want)
+		}
+	}
+}
+
+// TestCacheLimitKeys tests editing the cache limits on the Advanced tab
+func TestCacheLimitKeys(t *testing.T) {
+	m := New(config.Default())
+	m.activeTab = TabAdvanced
+
+	for _, key := range []string{"e", "z", "l"} {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+
+	if m.config.Cache.MaxEntries != 10000 || m.config.Cache.MaxSizeMB != 1024 || m.config.Cache.EvictionPolicy != "lfu" {
+		t.Errorf("unexpected cache settings: %+v", m.config.Cache)
+	}
+
+	updated, _ := m.Update(evictMsg{status: "evicted 3 of 10 entries"})
+	m = updated.(Model)
+	if m.evictStatus != "evicted 3 of 10 entries" || m.evictError {
+		t.Errorf("status = %q (error %v), want the eviction report", m.evictStatus, m.evictError)
+	}
+}
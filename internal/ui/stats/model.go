@@ -0,0 +1,247 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+	"github.com/lsilvatti/bakasub/internal/locales"
+	"github.com/lsilvatti/bakasub/internal/ui/layout"
+	"github.com/lsilvatti/bakasub/internal/ui/styles"
+)
+
+// Rows shown by each section of the view
+const (
+	trendDays  = 14
+	recentJobs = 5
+	maxPairs   = 5
+)
+
+// ClosedMsg is sent when the stats view should be closed
+type ClosedMsg struct{}
+
+// loadedMsg carries the telemetry read from the cache
+type loadedMsg struct {
+	stats *db.CacheStats
+	trend []db.LookupTrendPoint
+	pairs []db.LangPairLookups
+	jobs  []db.JobLookups
+	err   error
+}
+
+// Model shows the translation memory hit/miss telemetry and its trend
+type Model struct {
+	dbPath  string
+	stats   *db.CacheStats
+	trend   []db.LookupTrendPoint
+	pairs   []db.LangPairLookups
+	jobs    []db.JobLookups
+	err     error
+	loading bool
+	width   int
+	height  int
+}
+
+// New creates the stats view for the cache at dbPath ("" = shared cache)
+func New(dbPath string) *Model {
+	return &Model{dbPath: dbPath, loading: true}
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m *Model) Init() tea.Cmd {
+	return tea.Batch(tea.WindowSize(), m.loadCmd())
+}
+
+// loadCmd reads the telemetry in the background
+func (m *Model) loadCmd() tea.Cmd {
+	dbPath := m.dbPath
+	return func() tea.Msg {
+		if dbPath == "" {
+			cache, err := db.GetInstance("")
+			if err != nil {
+				return loadedMsg{err: err}
+			}
+			return load(cache, time.Now())
+		}
+
+		cache, err := db.Open(dbPath)
+		if err != nil {
+			return loadedMsg{err: err}
+		}
+		defer cache.Close()
+
+		return load(cache, time.Now())
+	}
+}
+
+// load reads every section of the view
+func load(cache *db.Cache, now time.Time) loadedMsg {
+	var msg loadedMsg
+	if msg.stats, msg.err = cache.GetStats(); msg.err != nil {
+		return msg
+	}
+	if msg.trend, msg.err = cache.GetLookupTrend(trendDays, now); msg.err != nil {
+		return msg
+	}
+	if msg.pairs, msg.err = cache.GetLookupsByLangPair(); msg.err != nil {
+		return msg
+	}
+	msg.jobs, msg.err = cache.GetRecentJobLookups(recentJobs)
+	return msg
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+
+	case loadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.stats = msg.stats
+		m.trend = msg.trend
+		m.pairs = msg.pairs
+		m.jobs = msg.jobs
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return m, func() tea.Msg { return ClosedMsg{} }
+		case "r":
+			m.loading = true
+			return m, m.loadCmd()
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Model) View() string {
+	if layout.IsWaitingForSize(m.width, m.height) {
+		return locales.T("common.loading")
+	}
+	if layout.IsTooSmall(m.width, m.height) {
+		return layout.RenderTooSmallWarning(m.width, m.height)
+	}
+
+	footer := styles.KeyHintStyle.Render("[R]") + " " + locales.T("cache_stats.refresh") + "  " +
+		styles.KeyHintStyle.Render("[ESC]") + " " + locales.T("cache_stats.exit")
+
+	var body string
+	switch {
+	case m.loading && m.stats == nil:
+		body = locales.T("common.loading")
+	case m.err != nil:
+		body = styles.StatusError.Render(locales.Tf("cache_stats.error", m.err))
+	default:
+		halfWidth := layout.SafeWidth(layout.CalculateHalf(m.width, 6), 30)
+		top := lipgloss.JoinHorizontal(lipgloss.Top,
+			styles.Panel.Width(halfWidth).Render(m.renderOverview()),
+			styles.Panel.Width(halfWidth).Render(m.renderTrend(halfWidth-4)),
+		)
+		bottom := lipgloss.JoinHorizontal(lipgloss.Top,
+			styles.Panel.Width(halfWidth).Render(m.renderPairs()),
+			styles.Panel.Width(halfWidth).Render(m.renderJobs()),
+		)
+		body = lipgloss.JoinVertical(lipgloss.Left, top, bottom)
+	}
+
+	return styles.MainWindow.Width(m.width - 4).Render(lipgloss.JoinVertical(lipgloss.Left,
+		styles.TitleStyle.Render(locales.T("cache_stats.title")),
+		"",
+		body,
+		"",
+		footer,
+	))
+}
+
+// renderOverview shows the all-time totals
+func (m *Model) renderOverview() string {
+	s := m.stats
+	return lipgloss.JoinVertical(lipgloss.Left,
+		styles.PanelTitle.Render(locales.T("cache_stats.overview")),
+		fmt.Sprintf("%s %s", locales.T("cache_stats.entries"), formatNumber(s.TotalEntries)),
+		fmt.Sprintf("%s %s", locales.T("cache_stats.lookups"), formatNumber(s.Lookups)),
+		fmt.Sprintf("%s %s", locales.T("cache_stats.exact_hits"), formatNumber(s.ExactHits)),
+		fmt.Sprintf("%s %s", locales.T("cache_stats.fuzzy_hits"), formatNumber(s.FuzzyHits)),
+		fmt.Sprintf("%s %s", locales.T("cache_stats.misses"), formatNumber(s.Misses)),
+		fmt.Sprintf("%s %.1f%%", locales.T("cache_stats.hit_rate"), s.HitRate),
+		fmt.Sprintf("%s %s", locales.T("cache_stats.saved_tokens"), formatNumber(s.SavedTokens)),
+		fmt.Sprintf("%s $%.4f", locales.T("cache_stats.saved_cost"), s.SavedCost),
+	)
+}
+
+// renderTrend shows the daily hit rate as bars
+func (m *Model) renderTrend(width int) string {
+	lines := []string{styles.PanelTitle.Render(locales.Tf("cache_stats.trend", trendDays))}
+
+	// "01-02 " + bar + " 100% " + lookups
+	barWidth := max(width-24, 5)
+	for _, p := range m.trend {
+		day := p.Day.Format("01-02")
+		if p.Lookups == 0 {
+			lines = append(lines, styles.Dimmed.Render(fmt.Sprintf("%s %s", day, strings.Repeat("·", barWidth))))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %3.0f%% %s",
+			day, layout.ProgressBar(p.Hits(), p.Lookups, barWidth), p.HitRate(),
+			styles.Dimmed.Render(formatNumber(p.Lookups))))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// renderPairs shows the busiest language pairs
+func (m *Model) renderPairs() string {
+	lines := []string{styles.PanelTitle.Render(locales.T("cache_stats.lang_pairs"))}
+	if len(m.pairs) == 0 {
+		lines = append(lines, styles.Dimmed.Render(locales.T("cache_stats.no_data")))
+	}
+	for i, p := range m.pairs {
+		if i == maxPairs {
+			break
+		}
+		pair := p.LangPair
+		if pair == "" {
+			pair = "?"
+		}
+		lines = append(lines, fmt.Sprintf("%-14s %5.1f%%  %s  $%.4f",
+			pair, p.HitRate(), locales.Tf("cache_stats.lookups_count", formatNumber(p.Lookups)), p.SavedCost))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// renderJobs shows the most recent jobs
+func (m *Model) renderJobs() string {
+	lines := []string{styles.PanelTitle.Render(locales.T("cache_stats.recent_jobs"))}
+	if len(m.jobs) == 0 {
+		lines = append(lines, styles.Dimmed.Render(locales.T("cache_stats.no_data")))
+	}
+	for _, j := range m.jobs {
+		when := ""
+		if !j.LastAt.IsZero() {
+			when = j.LastAt.Local().Format("01-02 15:04")
+		}
+		lines = append(lines, fmt.Sprintf("%s %-12s %5.1f%%  $%.4f",
+			styles.Dimmed.Render(when), j.LangPair, j.HitRate(), j.SavedCost))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// formatNumber formats large numbers with K/M suffixes
+func formatNumber(n int) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d", n)
+	}
+	if n < 1000000 {
+		return fmt.Sprintf("%.1fK", float64(n)/1000)
+	}
+	return fmt.Sprintf("%.1fM", float64(n)/1000000)
+}
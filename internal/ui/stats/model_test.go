@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lsilvatti/bakasub/internal/core/db"
+)
+
+// TestLoadAndView tests that recorded lookups show up in every section
+func TestLoadAndView(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	cache, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer cache.Close()
+
+	now := time.Now()
+	cache.RecordLookups(db.LookupRecord{JobID: "job-1", LangPair: "en->pt-br", Model: "gpt-4o",
+		Lookups: 200, ExactHits: 120, FuzzyHits: 30, Misses: 50, SavedTokens: 4500, SavedCost: 0.0321, CreatedAt: now})
+
+	m := New(dbPath)
+	m.SetSize(140, 50)
+	updated, _ := m.Update(load(cache, now))
+	m = updated.(*Model)
+
+	if m.loading || m.err != nil {
+		t.Fatalf("unexpected state: loading=%v err=%v", m.loading, m.err)
+	}
+	if len(m.trend) != trendDays || m.trend[trendDays-1].Lookups != 200 {
+		t.Errorf("today's trend point should hold the lookups: %+v", m.trend)
+	}
+
+	view := m.View()
+	for _, want := range []string{"75.0%", "4.5K", "$0.0321", "en->pt-br"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+}
+
+// TestLoadError tests that a failed load is reported
+func TestLoadError(t *testing.T) {
+	m := New("")
+	m.SetSize(140, 50)
+	updated, _ := m.Update(loadedMsg{err: errors.New("disk on fire")})
+	m = updated.(*Model)
+
+	if !strings.Contains(m.View(), "disk on fire") {
+		t.Error("view should show the load error")
+	}
+}
+
+// TestEscCloses tests that ESC leaves the view
+func TestEscCloses(t *testing.T) {
+	m := New("")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("expected a command")
+	}
+	if _, ok := cmd().(ClosedMsg); !ok {
+		t.Error("ESC should send ClosedMsg")
+	}
+}
@@ -1292,14 +1292,10 @@ func (m Model) fetchModelsCmd() tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
 
-		var provider ai.LLMProvider
-		var err error
-
 		switch m.providerSelection {
 		case 0: // OpenRouter
-			provider = ai.NewOpenRouterAdapter(m.apiKeyInput.Value(), "", 0.3)
-			// Fetch models from provider
-			modelStrings, err := provider.ListModels(ctx)
+			// Fetch models from the catalog, refreshing it from the provider when stale
+			models, err := modelselect.LoadFromCatalog(ctx, "openrouter", ai.NewOpenRouterAdapter(m.apiKeyInput.Value(), "", 0.3))
 			if err != nil {
 				// If fetch fails, return sample data for testing
 				freeModels := []ModelInfo{
@@ -1322,50 +1318,55 @@ func (m Model) fetchModelsCmd() tea.Cmd {
 				allModels := append(freeModels, paidModels...)
 				return modelsLoadedMsg{models: allModels, err: nil}
 			}
-			// Parse real models - assume free if contains ":free"
-			models := []ModelInfo{}
-			for _, model := range modelStrings {
-				isFree := strings.Contains(strings.ToLower(model), ":free")
-				models = append(models, parseModelsToModelInfo([]string{model}, isFree)...)
-			}
-			return modelsLoadedMsg{models: models, err: nil}
+			return modelsLoadedMsg{models: fromSelectorModels(models), err: nil}
 
 		case 1: // Gemini
-			provider, err = ai.NewGeminiAdapter(ctx, m.apiKeyInput.Value(), "", 0.3)
+			provider, err := ai.NewGeminiAdapter(ctx, m.apiKeyInput.Value(), "", 0.3)
 			if err != nil {
 				return modelsLoadedMsg{models: nil, err: err}
 			}
-			modelStrings, err := provider.ListModels(ctx)
+			models, err := modelselect.LoadFromCatalog(ctx, "gemini", provider)
 			if err != nil {
 				return modelsLoadedMsg{models: nil, err: err}
 			}
-			models := parseModelsToModelInfo(modelStrings, false)
-			return modelsLoadedMsg{models: models, err: nil}
+			return modelsLoadedMsg{models: fromSelectorModels(models), err: nil}
 
 		case 2: // OpenAI
-			provider = ai.NewOpenAIAdapter(m.apiKeyInput.Value(), "", 0.3)
-			modelStrings, err := provider.ListModels(ctx)
+			models, err := modelselect.LoadFromCatalog(ctx, "openai", ai.NewOpenAIAdapter(m.apiKeyInput.Value(), "", 0.3))
 			if err != nil {
 				// Fallback to static list if API fails
-				modelStrings = []string{"gpt-4o", "gpt-4o-mini", "gpt-4-turbo", "gpt-3.5-turbo"}
+				fallback := parseModelsToModelInfo([]string{"gpt-4o", "gpt-4o-mini", "gpt-4-turbo", "gpt-3.5-turbo"}, false)
+				return modelsLoadedMsg{models: fallback, err: nil}
 			}
-			models := parseModelsToModelInfo(modelStrings, false)
-			return modelsLoadedMsg{models: models, err: nil}
+			return modelsLoadedMsg{models: fromSelectorModels(models), err: nil}
 		case 3: // Local
-			provider = ai.NewLocalLLMAdapter(m.apiEndpointInput.Value(), "", 0.3)
-			modelStrings, err := provider.ListModels(ctx)
+			// Installed local models change often, so they bypass the catalog cache
+			infos, err := ai.NewLocalLLMAdapter(m.apiEndpointInput.Value(), "", 0.3).ListModelInfo(ctx)
 			if err != nil {
 				return modelsLoadedMsg{models: nil, err: err}
 			}
-			// Local models are free
-			models := parseModelsToModelInfo(modelStrings, true)
-			return modelsLoadedMsg{models: models, err: nil}
+			return modelsLoadedMsg{models: fromSelectorModels(modelselect.FromCatalog(infos)), err: nil}
 		}
 
 		return modelsLoadedMsg{models: nil, err: fmt.Errorf("provider not configured")}
 	}
 }
 
+// fromSelectorModels converts model selector rows to wizard ModelInfo
+func fromSelectorModels(models []modelselect.ModelInfo) []ModelInfo {
+	result := make([]ModelInfo, len(models))
+	for i, model := range models {
+		result[i] = ModelInfo{
+			ID:          model.ID,
+			Name:        model.Name,
+			ContextSize: model.ContextSize,
+			PricePerM:   model.PricePerM,
+			IsFree:      model.IsFree,
+		}
+	}
+	return result
+}
+
 // getLanguageName returns the language name for an ISO code
 func getLanguageName(code string) string {
 	code = strings.ToLower(strings.TrimSpace(code))